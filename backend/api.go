@@ -3,19 +3,122 @@ package backend
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 
-	"myproject/backend/database"
+	"myproject/backend/attachments"
+	"myproject/backend/diagnostics"
+	"myproject/backend/events"
+	"myproject/backend/foldersync"
+	"myproject/backend/ics"
+	"myproject/backend/importer"
+	"myproject/backend/lansync"
+	"myproject/backend/logging"
 	"myproject/backend/models"
+	"myproject/backend/packs"
+	"myproject/backend/profiles"
+	"myproject/backend/reminders"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	defaultDBPath        = "./DailyReflection.db"
+	reminderRulesSetting = "reminders.rules"
+
+	// folderSyncInterval is how often the sync folder is checked for changes
+	folderSyncInterval = 5 * time.Minute
 )
 
+var errProfilesUnavailable = errors.New("profiles are unavailable when the app is given a database")
+
+// StartupError describes why the app failed to start
+type StartupError struct {
+	Stage   string `json:"stage"` // "logging", "profiles" or "database"
+	Message string `json:"message"`
+}
+
+func (e *StartupError) Error() string {
+	return e.Stage + ": " + e.Message
+}
+
+// StartupStatus reports whether the app started successfully
+type StartupStatus struct {
+	Ready   bool          `json:"ready"`
+	Error   *StartupError `json:"error"`
+	DataDir string        `json:"dataDir"`
+}
+
+// Options configure a new App
+type Options struct {
+	// DBPath is the database file to open at startup. It defaults to the
+	// DB_PATH environment variable, then ./DailyReflection.db.
+	DBPath string
+
+	// DB is an already open database to use instead of opening DBPath.
+	// The caller keeps ownership and must close it, and profiles are
+	// unavailable while it is in use.
+	DB *sql.DB
+
+	// Logger replaces the rotating log file in the data directory
+	Logger *slog.Logger
+
+	// Clock drives reminders, defaulting to the wall clock
+	Clock reminders.Clock
+}
+
 // App struct
 type App struct {
-	ctx context.Context
+	ctx         context.Context
+	opts        Options
+	dataDir     string
+	db          *sql.DB
+	store       *models.Stores
+	attachments *attachments.Store
+	bus         *events.Bus
+	logger      *slog.Logger
+	logFile     io.Closer
+	startupErr  *StartupError
+	reminders   *reminders.Scheduler
+	profiles    *profiles.Manager
+	sync        *lansync.Service
+	folder      *foldersync.Syncer
+	unsubscribe func()
+
+	// mu guards the journal's database and what is built on it. Bindings
+	// hold it for reading; switching profiles and shutting down hold it for
+	// writing, so no binding runs against a database being closed.
+	mu sync.RWMutex
 }
 
 // NewApp creates a new App application struct
-func NewApp() *App {
-	return &App{}
+func NewApp(opts Options) *App {
+	if opts.DBPath == "" {
+		opts.DBPath = os.Getenv("DB_PATH")
+	}
+	if opts.DBPath == "" {
+		opts.DBPath = defaultDBPath
+	}
+	if opts.Clock == nil {
+		opts.Clock = reminders.SystemClock
+	}
+
+	a := &App{
+		opts:    opts,
+		dataDir: filepath.Dir(opts.DBPath),
+		bus:     events.NewBus(),
+	}
+	a.reminders = reminders.NewScheduler(opts.Clock, eventNotifier{app: a}, a.activityDone)
+	return a
 }
 
 // startup is called when the app starts. The context is saved
@@ -23,260 +126,1918 @@ func NewApp() *App {
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
 
-	// Initialize the database
-	err := database.Initialize("./DailyReflection.db")
+	// Set up logging before anything else can fail
+	a.logger = a.opts.Logger
+	if a.logger == nil {
+		logger, logFile, err := logging.New(logging.Options{
+			Dir:   logging.Dir(a.dataDir),
+			Level: logging.ParseLevel(os.Getenv("LOG_LEVEL")),
+		})
+		if err != nil {
+			logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+		}
+		a.logger, a.logFile = logger, logFile
+		slog.SetDefault(logger)
+
+		if err != nil {
+			a.fail("logging", err)
+		}
+	}
+
+	// Forward data change events to the frontend
+	a.unsubscribe = a.bus.Subscribe(func(e events.Event) {
+		a.emit(e.Type, e)
+	})
+
+	// Open the database. An injected database is used as is; otherwise the
+	// active profile's database is opened.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.opts.DB != nil {
+		a.use(a.opts.DB, a.opts.DBPath)
+	} else {
+		manager, err := profiles.Open(a.dataDir, filepath.Base(a.opts.DBPath))
+		if err != nil {
+			a.fail("profiles", err)
+			return
+		}
+		a.profiles = manager
+
+		profile := manager.Active()
+		db, err := manager.OpenDatabase(profile.ID)
+		if err != nil {
+			a.fail("database", err)
+			return
+		}
+		a.use(db, manager.Path(profile))
+		a.logger.Info("database opened", "profile", profile.ID, "path", manager.Path(profile))
+	}
+
+	a.startReminders()
+}
+
+// use makes db, opened from path, the journal's database and loads the
+// settings stored in it. The caller holds a.mu for writing, and stops the
+// sync workers of the journal used before.
+func (a *App) use(db *sql.DB, path string) {
+	store := models.NewStores(db, a.bus)
+	a.db = db
+	a.store = store
+	a.attachments = attachments.New(db, a.bus, attachments.Dir(path))
+
+	// Sync runs alongside the bindings, so it works on the stores of the
+	// journal it was started for rather than reading them from the App
+	a.sync = lansync.New(db, deviceName(), func(result lansync.SyncResult) {
+		a.logger.Info("sync received", "device", result.DeviceID, "received", result.Received, "conflicts", len(result.Conflicts))
+		if result.Received > 0 {
+			a.reindexEntries(store)
+		}
+		a.emit("sync:completed", result)
+	})
+	a.folder = foldersync.New(db, deviceName())
+	a.startFolderSync()
+
+	rules := reminders.DefaultRules()
+	if _, err := a.store.Settings.GetJSON(reminderRulesSetting, &rules); err != nil {
+		a.logger.Error("loading reminder rules", "error", err)
+	}
+	if err := a.reminders.SetRules(rules); err != nil {
+		a.logger.Error("loading reminder rules", "error", err)
+	}
+
+	// Offer back any answers left unsaved when the app last closed
+	drafts, err := a.store.Drafts.GetAll()
+	if err != nil {
+		a.logger.Error("loading drafts", "error", err)
+	} else if len(drafts) > 0 {
+		a.logger.Info("restoring drafts", "count", len(drafts))
+		a.emit("drafts:restored", drafts)
+	}
+
+	// Entries written before tags existed, or synced while the journal was
+	// closed, are indexed as it opens
+	a.reindexEntries(store)
+
+	// Remove the attachments of entries deleted since the journal was last opened
+	if removed, err := a.attachments.Prune(); err != nil {
+		a.logger.Error("pruning attachments", "error", err)
+	} else if removed > 0 {
+		a.logger.Info("pruned attachments", "files", removed)
+	}
+}
+
+// reindexEntries reads the hashtags and links in every entry again, after
+// entries may have changed without going through the stores
+func (a *App) reindexEntries(store *models.Stores) {
+	if err := store.Tags.Reindex(); err != nil {
+		a.logger.Error("indexing tags", "error", err)
+	}
+	if err := store.Links.Reindex(); err != nil {
+		a.logger.Error("indexing links", "error", err)
+	}
+}
+
+// startReminders starts checking reminder rules in the background
+func (a *App) startReminders() {
+	a.reminders.Start(time.Minute, func(err error) {
+		a.logger.Error("checking reminders", "error", err)
+	})
+}
+
+// startFolderSync replays the sync folder now and periodically, if one is
+// configured. The caller holds a.mu.
+func (a *App) startFolderSync() {
+	store := a.store
+	dir, err := a.folder.Folder()
 	if err != nil {
-		println("Error initializing database:", err.Error())
-	}
-
-	// Add some initial questions if database is empty
-	count := 0
-	database.DB.QueryRow("SELECT COUNT(*) FROM questions").Scan(&count)
-
-	if count == 0 {
-		initialQuestions := []string{
-			"What am I grateful for today?",
-			"What's something I learned recently?",
-			"What's a challenge I'm currently facing and how can I overcome it?",
-			"What brings me joy in my daily life?",
-			"What's one small step I can take today towards my biggest goal?",
-			"How can I be kinder to myself today?",
-			"What's something I appreciate about my body?",
-			"What's a belief I hold that might be limiting me?",
-			"If I had unlimited resources, what would I do with my life?",
-			"What relationships in my life deserve more attention?",
-			"What is one small victory I can celebrate about myself today?",
-			"How have my priorities shifted in the past year, and what does that reveal about my growth?",
-			"What negative thought pattern do I want to release, and what would I replace it with?",
-			"When did I last feel truly at peace, and how can I create more of those moments?",
-			"What advice would my future self, 10 years from now, give to me today?",
-			"Which of my personal strengths have I been underutilizing lately?",
-			"What fear has been holding me back, and what's one small way I could face it?",
-			"Who has positively influenced me recently, and what qualities of theirs do I admire?",
-			"What boundaries do I need to establish or reinforce in my life right now?",
-			"When do I feel most authentically myself, and how can I bring more of that into my daily life?",
-			"What am I holding onto that no longer serves my growth or happiness?",
-			"How do I typically respond to failure, and how might I respond more constructively?",
-			"What skill or area of knowledge would I like to develop further, and why?",
-			"What does 'success' mean to me right now, beyond external achievements?",
-			"Which aspects of my life feel balanced, and which need more attention?",
-			"What simple pleasures or small joys am I overlooking in my daily routine?",
-			"How has a recent challenge changed my perspective or made me stronger?",
-			"What am I curious about learning or exploring more deeply?",
-			"In what ways have I been kind to others recently, and how did it make me feel?",
-			"What activity makes me lose track of time in a positive way, and how could I engage in it more often?",
-			"When do I feel most connected to something greater than myself?",
-			"What past mistake am I still carrying, and how could I practice forgiveness—either of myself or someone else?",
-			"What would a perfect day look like for me right now, and what elements of it could I incorporate into my life?",
-			"How do my surroundings affect my mood and productivity, and what small change could improve them?",
-			"What would I do differently if I knew no one would judge me?",
-			"What recurring dreams or aspirations keep coming back to me, and what might they be telling me?",
-			"How do I recharge when I'm feeling depleted, and am I making enough time for it?",
-			"What habit would I like to develop, and what's the smallest first step I could take?",
-			"When was the last time I truly surprised myself, and what did I learn from it?",
-			"What legacy or impact would I like to leave in the lives of those around me?",
+		a.logger.Error("loading sync folder", "error", err)
+		return
+	}
+	if dir == "" {
+		return
+	}
+
+	a.folder.Start(folderSyncInterval, func(result *foldersync.Result) {
+		if result.Exported > 0 || result.Imported > 0 {
+			a.logger.Info("folder sync completed", "exported", result.Exported, "imported", result.Imported, "conflicts", len(result.Conflicts))
+			if result.Imported > 0 {
+				a.reindexEntries(store)
+			}
+			a.emit("sync:completed", result)
 		}
+	}, func(err error) {
+		a.logger.Error("folder sync failed", "folder", dir, "error", err)
+	})
+}
 
-		for _, q := range initialQuestions {
-			models.AddQuestion(q)
+// stopSync stops a journal's sync server and folder sync, waiting for a
+// sync in progress to finish
+func stopSync(logger *slog.Logger, server *lansync.Service, folder *foldersync.Syncer) {
+	if server != nil {
+		if err := server.Close(); err != nil {
+			logger.Error("stopping sync server", "error", err)
 		}
 	}
+	if folder != nil {
+		folder.Stop()
+	}
+}
+
+// fail records a startup failure and reports it to the frontend
+func (a *App) fail(stage string, err error) {
+	a.startupErr = &StartupError{Stage: stage, Message: err.Error()}
+	if a.logger != nil {
+		a.logger.Error("startup failed", "stage", stage, "error", err)
+	}
+	a.emit("startup:error", a.startupErr)
+}
+
+// ready returns why the journal can't be used, or nil once its database
+// is open. Bindings call it first, holding a.mu, so a failed startup is
+// reported to the frontend rather than crashing the app.
+func (a *App) ready() error {
+	if a.store != nil {
+		return nil
+	}
+	if a.startupErr != nil {
+		return a.startupErr
+	}
+	return errors.New("the journal isn't open yet")
 }
 
 // shutdown is called when the app is about to quit
 func (a *App) Shutdown(ctx context.Context) {
-	database.Close()
+	a.reminders.Stop()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stopSync(a.logger, a.sync, a.folder)
+	if a.unsubscribe != nil {
+		a.unsubscribe()
+	}
+	if a.db != nil && a.opts.DB == nil {
+		a.db.Close()
+	}
+	if a.logFile != nil {
+		a.logFile.Close()
+	}
+}
+
+// emit sends an event to the frontend. It does nothing when the app
+// is not running inside Wails, such as in tests.
+func (a *App) emit(name string, data ...interface{}) {
+	if a.ctx == nil || a.ctx.Value("events") == nil {
+		return
+	}
+	runtime.EventsEmit(a.ctx, name, data...)
+}
+
+// eventNotifier delivers reminders to the frontend as "reminder" events
+type eventNotifier struct {
+	app *App
+}
+
+func (n eventNotifier) Notify(notification reminders.Notification) error {
+	n.app.emit("reminder", notification)
+	return nil
+}
+
+// activityDone reports whether an activity was done on the given day
+func (a *App) activityDone(activity string, day time.Time) (bool, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return false, err
+	}
+
+	date := day.Format("2006-01-02")
+
+	switch activity {
+	case reminders.ActivityAffirmation:
+		return a.store.Affirmations.CheckDate(date)
+	case reminders.ActivityGratitude:
+		items, err := a.store.Gratitude.GetByDate(date)
+		return len(items) > 0, err
+	case reminders.ActivityCreativity:
+		return a.store.Creativity.HasForDate(date)
+	case reminders.ActivityAnswer:
+		return a.store.Answers.HasForDate(date)
+	case reminders.ActivityMorningRoutine, reminders.ActivityEveningRoutine:
+		statuses, err := a.store.Routines.GetStatus(date)
+		if err != nil {
+			return false, err
+		}
+		builtin := models.RoutineMorning
+		if activity == reminders.ActivityEveningRoutine {
+			builtin = models.RoutineEvening
+		}
+		for _, status := range statuses {
+			if status.Routine.Builtin == builtin {
+				return status.Done, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown activity %q", activity)
 }
 
 // GetActiveAffirmation gets the current active affirmation
 func (a *App) GetActiveAffirmation() (*models.Affirmation, error) {
-	return models.GetActiveAffirmation()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Affirmations.GetActive()
 }
 
 // SaveAffirmation saves a new affirmation
 func (a *App) SaveAffirmation(content string) (*models.Affirmation, error) {
-	return models.SaveAffirmation(content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Affirmations.Save(content)
 }
 
 // LogAffirmation logs that the user has completed their affirmation today
 func (a *App) LogAffirmation(affirmationID int64) error {
-	return models.LogAffirmationCompletion(affirmationID)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Affirmations.LogCompletion(affirmationID)
 }
 
 // CheckTodayAffirmation checks if the affirmation was completed today
 func (a *App) CheckTodayAffirmation(affirmationID int64) (bool, error) {
-	return models.CheckTodayAffirmation(affirmationID)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return false, err
+	}
+	return a.store.Affirmations.CheckToday(affirmationID)
 }
 
 // GetAffirmationStreak gets the current streak of consecutive days
 func (a *App) GetAffirmationStreak() (int, error) {
-	return models.GetAffirmationStreak()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+	return a.store.Affirmations.GetStreak()
 }
 
 // GetAllQuestions retrieves all questions from the database
 func (a *App) GetAllQuestions() ([]models.Question, error) {
-	return models.GetAllQuestions()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.GetAll()
 }
 
 // GetAllAnswers retrieves all answers from the database
 func (a *App) GetAllAnswers() ([]models.Answer, error) {
-	return models.GetAllAnswers()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Answers.GetAll()
 }
 
 // GetAllAffirmations retrieves all affirmations from the database
 func (a *App) GetAllAffirmations() ([]models.Affirmation, error) {
-	return models.GetAllAffirmations()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Affirmations.GetAll()
 }
 
 // GetAllAffirmationLogs retrieves all affirmation logs from the database
 func (a *App) GetAllAffirmationLogs() ([]models.AffirmationLog, error) {
-	return models.GetAllAffirmationLogs()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Affirmations.GetAllLogs()
 }
 
-// GetRandomQuestion returns a random question
+// GetRandomQuestion returns the next question to ask, picked with the
+// chosen strategy
 func (a *App) GetRandomQuestion() (*models.Question, error) {
-	return models.GetRandomQuestion()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.Next()
 }
 
 // CreateNewAnswer creates a new answer entry
 func (a *App) CreateNewAnswer(questionID int64, content string) (*models.Answer, error) {
-	return models.CreateNewAnswer(questionID, content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Answers.Create(questionID, content)
+}
+
+// CreateStructuredAnswer creates an answer from values for the fields of
+// its question's template
+func (a *App) CreateStructuredAnswer(questionID int64, fields []models.FieldValue) (*models.Answer, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Answers.CreateStructured(questionID, fields)
 }
 
 // GetAnswerHistoryByQuestionID gets all answers for a specific question
 func (a *App) GetAnswerHistoryByQuestionID(questionID int64) ([]models.AnswerHistory, error) {
-	return models.GetAnswerHistoryByQuestionID(questionID)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Answers.GetHistoryByQuestionID(questionID)
+}
+
+// SaveDraft saves today's draft answer for a question, replacing any earlier
+// draft from today. Saving empty content discards the draft.
+func (a *App) SaveDraft(questionID int64, content string) (*models.Draft, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Drafts.Save(questionID, content)
+}
+
+// SaveStructuredDraft saves today's draft of a structured answer, keeping
+// the values for the question's template. Saving no values discards the draft.
+func (a *App) SaveStructuredDraft(questionID int64, fields []models.FieldValue) (*models.Draft, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Drafts.SaveStructured(questionID, fields)
+}
+
+// GetDrafts gets every draft that hasn't been committed or discarded
+func (a *App) GetDrafts() ([]models.Draft, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Drafts.GetAll()
+}
+
+// CommitDraft turns a draft into an answer
+func (a *App) CommitDraft(id int64) (*models.Answer, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Drafts.Commit(id)
+}
+
+// DiscardDraft deletes a draft without saving it as an answer
+func (a *App) DiscardDraft(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Drafts.Delete(id)
+}
+
+// StartWritingSession records that the user started writing an activity,
+// such as "answer" or "creativity", and returns the session to end later
+func (a *App) StartWritingSession(activity string) (*models.WritingSession, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Writing.StartSession(activity)
+}
+
+// EndWritingSession records that the user stopped writing
+func (a *App) EndWritingSession(id int64) (*models.WritingSession, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Writing.EndSession(id)
+}
+
+// GetWritingStats gets word counts, writing time and frequent words for a date range
+func (a *App) GetWritingStats(r models.DateRange) (*models.WritingStats, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Writing.GetStats(r)
+}
+
+// GetSentimentTrend gets the average sentiment of entries between from and
+// to (YYYY-MM-DD, either may be empty) per "day", "week" or "month".
+// No mood data is recorded yet, so the trend has no mood correlation.
+func (a *App) GetSentimentTrend(from string, to string, granularity string) (*models.SentimentTrend, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Sentiment.GetTrend(models.DateRange{From: from, To: to}, granularity, nil)
 }
 
 // Question CRUD operations
 func (a *App) AddQuestion(content string) (*models.Question, error) {
-	return models.AddQuestion(content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.Add(content)
 }
 
 func (a *App) UpdateQuestion(id int64, content string) error {
-	return models.UpdateQuestion(id, content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Questions.Update(id, content)
 }
 
 func (a *App) DeleteQuestion(id int64) error {
-	return models.DeleteQuestion(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Questions.Delete(id)
+}
+
+// SetQuestionTemplate gives a question's answers fields to fill in, or
+// removes them when template is nil
+func (a *App) SetQuestionTemplate(id int64, template *models.Template) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Questions.SetTemplate(id, template)
+}
+
+// SkipQuestion records that the user passed over a question because it's
+// "not_relevant", they'll "answer_later" or for some "other" reason. It
+// isn't asked again today.
+func (a *App) SkipQuestion(id int64, reason string) (*models.QuestionSkip, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.Skip(id, reason)
+}
+
+// SnoozeQuestion sets a question aside until a date (YYYY-MM-DD)
+func (a *App) SnoozeQuestion(id int64, until string) (*models.QuestionSkip, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.Snooze(id, until)
+}
+
+// GetMostSkippedQuestions lists the questions skipped most often, which may
+// be worth rewording or removing. A limit of 0 lists every skipped question.
+func (a *App) GetMostSkippedQuestions(limit int) ([]models.SkippedQuestion, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.GetMostSkipped(limit)
+}
+
+// GetQuestionStrategies lists the strategies questions can be picked with
+func (a *App) GetQuestionStrategies() []string {
+	return models.QuestionStrategies()
+}
+
+// GetQuestionStrategy gets the strategy questions are picked with
+func (a *App) GetQuestionStrategy() (string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+	return a.store.Questions.Strategy()
+}
+
+// SetQuestionStrategy chooses the strategy questions are picked with
+func (a *App) SetQuestionStrategy(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Questions.SetStrategy(name)
 }
 
 // Answer CRUD operations
 func (a *App) UpdateAnswer(id int64, content string) error {
-	return models.UpdateAnswer(id, content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Answers.Update(id, content)
+}
+
+func (a *App) UpdateStructuredAnswer(id int64, fields []models.FieldValue) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Answers.UpdateStructured(id, fields)
 }
 
 func (a *App) DeleteAnswer(id int64) error {
-	return models.DeleteAnswer(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Answers.Delete(id)
 }
 
 // Affirmation CRUD operations
 func (a *App) UpdateAffirmation(id int64, content string) error {
-	return models.UpdateAffirmation(id, content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Affirmations.Update(id, content)
 }
 
 func (a *App) DeleteAffirmation(id int64) error {
-	return models.DeleteAffirmation(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Affirmations.Delete(id)
 }
 
 // Affirmation Log CRUD operations
 func (a *App) DeleteAffirmationLog(id int64) error {
-	return models.DeleteAffirmationLog(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Affirmations.DeleteLog(id)
 }
 
 // GetRecentAnswers retrieves answers from the last few days
 func (a *App) GetRecentAnswers(daysRange int) ([]models.Answer, error) {
-	return models.GetRecentAnswers(daysRange)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Answers.GetRecent(daysRange)
 }
 
 // GetQuestionById retrieves a specific question by its ID
 func (a *App) GetQuestionById(id int64) (*models.Question, error) {
-	return models.GetQuestionById(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Questions.GetByID(id)
 }
 
 // Gratitude Journal API Methods
 
 // AddGratitudeItem adds a new gratitude item for today
 func (a *App) AddGratitudeItem(content string) (*models.GratitudeItem, error) {
-	return models.AddGratitudeItem(content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Gratitude.Add(content)
 }
 
 // GetTodayGratitudeItems gets all gratitude items for today
 func (a *App) GetTodayGratitudeItems() ([]models.GratitudeItem, error) {
-	return models.GetTodayGratitudeItems()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Gratitude.GetToday()
 }
 
 // GetGratitudeItemsByDate gets all gratitude items for a specific date
 func (a *App) GetGratitudeItemsByDate(date string) ([]models.GratitudeItem, error) {
-	return models.GetGratitudeItemsByDate(date)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Gratitude.GetByDate(date)
 }
 
 // HasTodayGratitudeEntries checks if there are any entries for today
 func (a *App) HasTodayGratitudeEntries() (bool, error) {
-	return models.HasTodayGratitudeEntries()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return false, err
+	}
+	return a.store.Gratitude.HasToday()
 }
 
 // CountTodayGratitudeEntries counts the number of entries for today
 func (a *App) CountTodayGratitudeEntries() (int, error) {
-	return models.CountTodayGratitudeEntries()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+	return a.store.Gratitude.CountToday()
 }
 
 // GetAllGratitudeEntries gets all gratitude entries grouped by date
 func (a *App) GetAllGratitudeEntries() ([]models.GratitudeEntry, error) {
-	return models.GetAllGratitudeEntries()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Gratitude.GetAllEntries()
 }
 
 // UpdateGratitudeItem updates a gratitude item
 func (a *App) UpdateGratitudeItem(id int64, content string) error {
-	return models.UpdateGratitudeItem(id, content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Gratitude.Update(id, content)
 }
 
 // DeleteGratitudeItem deletes a gratitude item
 func (a *App) DeleteGratitudeItem(id int64) error {
-	return models.DeleteGratitudeItem(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Gratitude.Delete(id)
 }
 
 // GetLastNDaysWithGratitude gets entries for the last n days
 func (a *App) GetLastNDaysWithGratitude(n int) ([]models.GratitudeEntry, error) {
-	return models.GetLastNDaysWithGratitude(n)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Gratitude.GetLastNDays(n)
 }
 
 // GetGratitudeStreak calculates the current streak of consecutive days with gratitude entries
 func (a *App) GetGratitudeStreak() (int, error) {
-	return models.GetGratitudeStreak()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+	return a.store.Gratitude.GetStreak()
 }
 
 // SaveCreativityEntry saves a creativity journal entry for a specific date
 func (a *App) SaveCreativityEntry(content string, entryDate string) (*models.CreativityEntry, error) {
-	return models.SaveCreativityEntry(content, entryDate)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Creativity.Save(content, entryDate)
+}
+
+// CreateCreativityEntry adds a creativity journal entry. Days can have
+// several entries unless one-per-day mode is on.
+func (a *App) CreateCreativityEntry(entry models.CreativityEntry) (*models.CreativityEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Creativity.Create(entry)
+}
+
+// GetCreativityEntriesByDate retrieves the creativity journal entries for a specific date
+func (a *App) GetCreativityEntriesByDate(entryDate string) ([]models.CreativityEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Creativity.GetByDate(entryDate)
+}
+
+// SaveCreativityEntryForPrompt saves a creativity journal entry written in
+// response to a prompt. A null promptID keeps the entry's prompt.
+func (a *App) SaveCreativityEntryForPrompt(content string, entryDate string, promptID *int64) (*models.CreativityEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Creativity.SaveForPrompt(content, entryDate, promptID)
 }
 
-// GetCreativityEntryByDate retrieves the creativity journal entry for a specific date
-func (a *App) GetCreativityEntryByDate(entryDate string) (*models.CreativityEntry, error) {
-	return models.GetCreativityEntryByDate(entryDate)
+// GetCreativityEntriesByPrompt retrieves the entries written in response to a prompt
+func (a *App) GetCreativityEntriesByPrompt(promptID int64) ([]models.CreativityEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Creativity.GetByPrompt(promptID)
 }
 
 // GetAllCreativityEntries retrieves all creativity journal entries
 func (a *App) GetAllCreativityEntries() ([]models.CreativityEntry, error) {
-	return models.GetAllCreativityEntries()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Creativity.GetAll()
 }
 
 // UpdateCreativityEntry updates a creativity journal entry
 func (a *App) UpdateCreativityEntry(id int64, content string) error {
-	return models.UpdateCreativityEntry(id, content)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Creativity.Update(id, content)
+}
+
+// EditCreativityEntry changes a creativity journal entry's title, kind, content, date and prompt
+func (a *App) EditCreativityEntry(entry models.CreativityEntry) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Creativity.Edit(entry)
 }
 
 // DeleteCreativityEntry deletes a creativity journal entry
 func (a *App) DeleteCreativityEntry(id int64) error {
-	return models.DeleteCreativityEntry(id)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Creativity.Delete(id)
 }
 
 // HasCreativityEntryForDate checks if there is a creativity journal entry for the given date
 func (a *App) HasCreativityEntryForDate(entryDate string) (bool, error) {
-	return models.HasCreativityEntryForDate(entryDate)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return false, err
+	}
+	return a.store.Creativity.HasForDate(entryDate)
 }
 
 // GetCreativityStreak returns the current streak of consecutive days with creativity entries
 func (a *App) GetCreativityStreak() (int, error) {
-	return models.GetCreativityStreak()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+	return a.store.Creativity.GetStreak()
+}
+
+// GetCreativityOnePerDay reports whether the creativity journal is limited to one entry per day
+func (a *App) GetCreativityOnePerDay() (bool, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return false, err
+	}
+	return a.store.Creativity.OnePerDay()
+}
+
+// SetCreativityOnePerDay limits the creativity journal to one entry per day, or allows several
+func (a *App) SetCreativityOnePerDay(enabled bool) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Creativity.SetOnePerDay(enabled)
+}
+
+// GetDailyCreativityPrompt returns the creativity prompt for a date (YYYY-MM-DD)
+func (a *App) GetDailyCreativityPrompt(date string) (*models.CreativityPrompt, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Prompts.GetDaily(date)
+}
+
+// GetCreativityPrompts lists the creativity prompts, optionally with archived ones
+func (a *App) GetCreativityPrompts(includeArchived bool) ([]models.CreativityPrompt, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Prompts.GetAll(includeArchived)
+}
+
+// GetCreativityPromptCategories lists the prompt categories in use
+func (a *App) GetCreativityPromptCategories() ([]string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Prompts.GetCategories()
+}
+
+// AddCreativityPrompt adds a prompt of the user's own
+func (a *App) AddCreativityPrompt(content string, category string) (*models.CreativityPrompt, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Prompts.Add(content, category)
+}
+
+// UpdateCreativityPrompt changes one of the user's prompts
+func (a *App) UpdateCreativityPrompt(id int64, content string, category string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Prompts.Update(id, content, category)
+}
+
+// ArchiveCreativityPrompt stops a prompt being chosen as the daily prompt, or restores it
+func (a *App) ArchiveCreativityPrompt(id int64, archived bool) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Prompts.SetArchived(id, archived)
+}
+
+// DeleteCreativityPrompt deletes one of the user's prompts
+func (a *App) DeleteCreativityPrompt(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Prompts.Delete(id)
+}
+
+// GetHabits lists the habits being tracked, optionally with archived ones
+func (a *App) GetHabits(includeArchived bool) ([]models.Habit, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Habits.GetAll(includeArchived)
+}
+
+// CreateHabit adds a habit to track
+func (a *App) CreateHabit(habit models.Habit) (*models.Habit, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Habits.Create(habit)
+}
+
+// UpdateHabit changes a habit's name, frequency, target and unit
+func (a *App) UpdateHabit(habit models.Habit) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Habits.Update(habit)
+}
+
+// ArchiveHabit hides a habit from the current habits, or restores it
+func (a *App) ArchiveHabit(id int64, archived bool) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Habits.SetArchived(id, archived)
+}
+
+// DeleteHabit deletes a habit and its check-ins
+func (a *App) DeleteHabit(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Habits.Delete(id)
+}
+
+// LogHabit records a check-in of a habit on a date, defaulting to today.
+// quantity may be null.
+func (a *App) LogHabit(habitID int64, date string, quantity *float64, note string) (*models.HabitLog, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Habits.Log(habitID, date, quantity, note)
+}
+
+// GetHabitLogs returns a habit's check-ins in a date range
+func (a *App) GetHabitLogs(habitID int64, r models.DateRange) ([]models.HabitLog, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Habits.GetLogs(habitID, r)
+}
+
+// DeleteHabitLog deletes one of a habit's check-ins
+func (a *App) DeleteHabitLog(habitID int64, logID int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Habits.DeleteLog(habitID, logID)
+}
+
+// GetHabitStats returns a habit's streaks and completion rate over a date range
+func (a *App) GetHabitStats(habitID int64, r models.DateRange) (*models.HabitStats, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Habits.GetStats(habitID, r)
+}
+
+// GetGoals lists goals with a status ("active", "paused", "completed" or
+// "abandoned"), or all goals if status is empty
+func (a *App) GetGoals(status string) ([]models.Goal, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.GetAll(status)
+}
+
+// GetGoal gets a goal with its milestones
+func (a *App) GetGoal(id int64) (*models.Goal, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.GetByID(id)
+}
+
+// CreateGoal adds a goal
+func (a *App) CreateGoal(goal models.Goal) (*models.Goal, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.Create(goal)
+}
+
+// UpdateGoal changes a goal's title, why, target date and status
+func (a *App) UpdateGoal(goal models.Goal) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.Update(goal)
+}
+
+// SetGoalStatus changes a goal's status
+func (a *App) SetGoalStatus(id int64, status string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.SetStatus(id, status)
+}
+
+// DeleteGoal deletes a goal, keeping the entries linked to it
+func (a *App) DeleteGoal(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.Delete(id)
+}
+
+// AddGoalMilestone adds a milestone to a goal
+func (a *App) AddGoalMilestone(goalID int64, title string, targetDate string) (*models.GoalMilestone, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.AddMilestone(goalID, title, targetDate)
+}
+
+// SetGoalMilestoneDone marks a milestone as reached, or not
+func (a *App) SetGoalMilestoneDone(id int64, done bool) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.SetMilestoneDone(id, done)
+}
+
+// DeleteGoalMilestone deletes a milestone
+func (a *App) DeleteGoalMilestone(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.DeleteMilestone(id)
+}
+
+// CheckInGoal records this week's progress towards a goal
+func (a *App) CheckInGoal(goalID int64, progress int, note string) (*models.GoalCheckIn, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.CheckIn(goalID, progress, note)
+}
+
+// GetGoalCheckIns returns a goal's weekly check-ins
+func (a *App) GetGoalCheckIns(goalID int64) ([]models.GoalCheckIn, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.GetCheckIns(goalID)
+}
+
+// LinkToGoal links an answer, creativity entry or gratitude item to a goal.
+// entryType is "answer", "creativity" or "gratitude".
+func (a *App) LinkToGoal(goalID int64, entryType string, entryID int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.Link(goalID, entryType, entryID)
+}
+
+// UnlinkFromGoal removes the link between an entry and a goal
+func (a *App) UnlinkFromGoal(goalID int64, entryType string, entryID int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Goals.Unlink(goalID, entryType, entryID)
+}
+
+// GetLinkedGoals returns the goals an entry is linked to
+func (a *App) GetLinkedGoals(entryType string, entryID int64) ([]models.Goal, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.GetLinkedGoals(entryType, entryID)
+}
+
+// GetGoalTimeline returns everything written about a goal in chronological order
+func (a *App) GetGoalTimeline(goalID int64) ([]models.GoalTimelineItem, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Goals.GetTimeline(goalID)
+}
+
+// GetRoutines returns the routines, built-in ones first
+func (a *App) GetRoutines() ([]models.Routine, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Routines.GetAll()
+}
+
+// CreateRoutine adds a routine of ordered steps
+func (a *App) CreateRoutine(routine models.Routine) (*models.Routine, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Routines.Create(routine)
+}
+
+// UpdateRoutine changes a routine's name and steps
+func (a *App) UpdateRoutine(routine models.Routine) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Routines.Update(routine)
+}
+
+// DeleteRoutine deletes a routine the user added, along with its runs
+func (a *App) DeleteRoutine(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.store.Routines.Delete(id)
+}
+
+// StartRoutine starts a routine today, or returns today's run if it was
+// already started
+func (a *App) StartRoutine(routineID int64) (*models.RoutineRun, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Routines.Start(routineID, "")
+}
+
+// CompleteRoutineStep records a step of a routine run as done. entryID is
+// the entry written for the step, or 0; note is the intention or review
+// for steps written in the routine.
+func (a *App) CompleteRoutineStep(runID int64, stepKey string, entryID int64, note string) (*models.RoutineStatus, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Routines.CompleteStep(runID, stepKey, entryID, note)
+}
+
+// GetRoutineStatus returns how far through each routine the user got on a
+// date (YYYY-MM-DD)
+func (a *App) GetRoutineStatus(date string) ([]models.RoutineStatus, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Routines.GetStatus(date)
+}
+
+// GetTags lists the #hashtags used in entries, by name
+func (a *App) GetTags() ([]models.Tag, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Tags.GetAll()
+}
+
+// GetTagCloud returns the tags used by entries in a date range, most used first
+func (a *App) GetTagCloud(r models.DateRange) ([]models.Tag, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Tags.GetCloud(r)
+}
+
+// GetEntriesByTag returns the answers, gratitude items, creativity entries
+// and affirmations that use a tag, newest first
+func (a *App) GetEntriesByTag(tag string) ([]models.EntrySummary, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Tags.GetEntries(tag)
+}
+
+// GetEntryTags returns the tags an entry uses
+func (a *App) GetEntryTags(entryType string, id int64) ([]string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Tags.GetForEntry(entryType, id)
+}
+
+// RenameTag renames a tag in every entry that uses it, merging it into
+// another tag if that name is taken. It returns how many entries changed.
+func (a *App) RenameTag(from string, to string) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+	return a.store.Tags.Rename(from, to)
+}
+
+// MergeTags renames several tags to one in every entry that uses them
+func (a *App) MergeTags(tags []string, into string) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+	return a.store.Tags.Merge(tags, into)
+}
+
+// GetBacklinks returns the entries with a [[link]] to an entry, newest first
+func (a *App) GetBacklinks(entityType string, id int64) ([]models.Backlink, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Links.GetBacklinks(entityType, id)
+}
+
+// GetEntryLinks returns the [[links]] written in an entry and where they lead
+func (a *App) GetEntryLinks(entityType string, id int64) ([]models.EntryLink, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Links.GetLinks(entityType, id)
+}
+
+// GetBrokenLinks returns the [[links]] that don't lead to an entry, such as
+// links to entries since deleted
+func (a *App) GetBrokenLinks() ([]models.EntryLink, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Links.GetBroken()
+}
+
+// AddAttachment attaches the file at path to an entry ("answer", "creativity" or "gratitude")
+func (a *App) AddAttachment(entryType string, entryID int64, path string) (*attachments.Attachment, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.attachments.Add(entryType, entryID, path)
+}
+
+// ListAttachments returns the attachments of an entry
+func (a *App) ListAttachments(entryType string, entryID int64) ([]attachments.Attachment, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.attachments.List(entryType, entryID)
+}
+
+// DeleteAttachment removes an attachment, and its file once no entry refers to it
+func (a *App) DeleteAttachment(id int64) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.attachments.Delete(id)
+}
+
+// GetImportFormats lists the external journaling formats that can be imported
+func (a *App) GetImportFormats() []string {
+	return importer.Formats()
+}
+
+// PreviewImport reports what an import would create without writing anything
+func (a *App) PreviewImport(opts importer.Options) (*importer.Report, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	src, err := importer.NewSource(opts)
+	if err != nil {
+		return nil, err
+	}
+	return importer.New(a.store).Plan(src, opts)
+}
+
+// RunImport imports entries from an external journaling format
+func (a *App) RunImport(opts importer.Options) (*importer.Report, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	src, err := importer.NewSource(opts)
+	if err != nil {
+		return nil, err
+	}
+	return importer.New(a.store).Apply(src, opts)
+}
+
+// ExportICS writes journaling activity to an iCalendar file as all-day events
+func (a *App) ExportICS(path string, options ics.ExportOptions) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	days, err := a.store.ActivityByDate(options.From, options.To)
+	if err != nil {
+		return err
+	}
+
+	events, err := ics.BuildEvents(days, options)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := ics.Write(f, events, time.Now()); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// GetReminderRules gets the configured reminder rules
+func (a *App) GetReminderRules() []reminders.Rule {
+	return a.reminders.Rules()
+}
+
+// SaveReminderRules validates, stores and applies new reminder rules
+func (a *App) SaveReminderRules(rules []reminders.Rule) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	if err := a.reminders.SetRules(rules); err != nil {
+		return err
+	}
+	return a.store.Settings.SetJSON(reminderRulesSetting, rules)
+}
+
+// GetStartupStatus reports whether the journal is ready to use, and why not
+// if it isn't. A failure that still let the database open, such as not
+// being able to write logs, is reported without the app being unready.
+func (a *App) GetStartupStatus() StartupStatus {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return StartupStatus{
+		Ready:   a.ready() == nil,
+		Error:   a.startupErr,
+		DataDir: a.dataDir,
+	}
+}
+
+// CreateDiagnosticsBundle writes a zip of logs, schema version, row counts
+// and an integrity check to the data directory and returns its path.
+// No journal content is included.
+func (a *App) CreateDiagnosticsBundle() (string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	report := diagnostics.Collect(a.db)
+	if a.startupErr != nil {
+		report.StartupError = a.startupErr.Error()
+	}
+
+	path := filepath.Join(a.dataDir, "diagnostics-"+time.Now().Format("20060102-150405")+".zip")
+	if err := diagnostics.WriteBundle(path, report, logging.Dir(a.dataDir)); err != nil {
+		return "", err
+	}
+
+	a.logger.Info("diagnostics bundle created", "path", path)
+	return path, nil
+}
+
+// ListProfiles lists the journal profiles
+func (a *App) ListProfiles() ([]profiles.Profile, error) {
+	if a.profiles == nil {
+		return nil, errProfilesUnavailable
+	}
+	return a.profiles.List(), nil
+}
+
+// GetActiveProfile returns the profile in use
+func (a *App) GetActiveProfile() (*profiles.Profile, error) {
+	if a.profiles == nil {
+		return nil, errProfilesUnavailable
+	}
+	profile := a.profiles.Active()
+	return &profile, nil
+}
+
+// GetSeedSets lists the question sets a new profile can start with
+func (a *App) GetSeedSets() []string {
+	return profiles.SeedSets()
+}
+
+// CreateProfile creates a profile whose database starts with the questions in seedSet
+func (a *App) CreateProfile(name string, seedSet string) (*profiles.Profile, error) {
+	if a.profiles == nil {
+		return nil, errProfilesUnavailable
+	}
+	profile, err := a.profiles.Create(name, seedSet)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("profile created", "profile", profile.ID, "seedSet", seedSet)
+	return &profile, nil
+}
+
+// SwitchProfile closes the current database and opens the profile's database.
+// The current profile stays active if the new database can't be opened.
+func (a *App) SwitchProfile(id string) error {
+	if a.profiles == nil {
+		return errProfilesUnavailable
+	}
+	profile, err := a.profiles.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// Reminders read from the database, so they are paused while it changes
+	a.reminders.Stop()
+	defer a.startReminders()
+
+	db, err := a.profiles.OpenDatabase(profile.ID)
+	if err != nil {
+		return err
+	}
+	if err := a.profiles.SetActive(profile.ID); err != nil {
+		db.Close()
+		return err
+	}
+
+	a.mu.Lock()
+	previous, previousSync, previousFolder := a.db, a.sync, a.folder
+	a.use(db, a.profiles.Path(profile))
+
+	// A profile that opens recovers from a database that failed at startup
+	if a.startupErr != nil && a.startupErr.Stage == "database" {
+		a.startupErr = nil
+	}
+	a.mu.Unlock()
+
+	// The previous journal's sync workers may be part way through a sync,
+	// so they are stopped before its database is closed
+	stopSync(a.logger, previousSync, previousFolder)
+	if previous != nil {
+		previous.Close()
+	}
+
+	a.logger.Info("profile switched", "profile", profile.ID)
+	a.emit("profile:switched", profile)
+	return nil
+}
+
+// DeleteProfile deletes a profile and its database. The active profile can't be deleted.
+func (a *App) DeleteProfile(id string) error {
+	if a.profiles == nil {
+		return errProfilesUnavailable
+	}
+	if err := a.profiles.Delete(id); err != nil {
+		return err
+	}
+	a.logger.Info("profile deleted", "profile", id)
+	return nil
+}
+
+// GetQuestionPacks lists the installed question packs
+func (a *App) GetQuestionPacks() ([]models.QuestionPack, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.store.Packs.GetAll()
+}
+
+// GetBuiltinQuestionPacks lists the question packs that ship with the app
+func (a *App) GetBuiltinQuestionPacks() ([]packs.Pack, error) {
+	return packs.Builtin()
+}
+
+// InstallQuestionPack installs a question pack from a JSON or YAML file.
+// Questions already in the journal are skipped.
+func (a *App) InstallQuestionPack(path string) (*models.PackInstallResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	pack, err := packs.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.installPack(pack)
+}
+
+// InstallBuiltinQuestionPack installs one of the packs that ship with the app
+func (a *App) InstallBuiltinQuestionPack(id string) (*models.PackInstallResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	pack, err := packs.BuiltinPack(id)
+	if err != nil {
+		return nil, err
+	}
+	return a.installPack(pack)
+}
+
+func (a *App) installPack(pack *packs.Pack) (*models.PackInstallResult, error) {
+	result, err := a.store.Packs.Install(packs.ToModel(pack), pack.Questions)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("question pack installed", "pack", pack.ID, "version", pack.Version, "added", result.Added, "skipped", len(result.Skipped))
+	return result, nil
+}
+
+// UninstallQuestionPack removes a pack's questions. Questions that have
+// answers are kept as the user's own.
+func (a *App) UninstallQuestionPack(id string) (*models.PackUninstallResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	result, err := a.store.Packs.Uninstall(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("question pack %q is not installed", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("question pack uninstalled", "pack", id, "removed", result.Removed, "kept", result.Kept)
+	return result, nil
+}
+
+// ExportQuestionPack writes an installed pack to a JSON or YAML file, chosen
+// by the extension of path. An empty id exports the questions the user added
+// themselves.
+func (a *App) ExportQuestionPack(id string, path string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	meta := models.QuestionPack{ID: "my-questions", Name: "My questions", Version: "1.0.0"}
+	if id != "" {
+		installed, err := a.store.Packs.GetByID(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("question pack %q is not installed", id)
+		}
+		if err != nil {
+			return err
+		}
+		meta = *installed
+	}
+
+	questions, err := a.store.Packs.GetQuestions(id)
+	if err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("there are no questions to export")
+	}
+
+	return packs.Write(path, packs.FromQuestions(meta, questions))
+}
+
+// SyncStatus describes this journal's LAN sync server
+type SyncStatus struct {
+	Running   bool     `json:"running"`
+	Port      int      `json:"port"`
+	DeviceID  string   `json:"deviceId"`
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"` // host:port other devices can pair with, when running
+}
+
+// deviceName names this device to paired devices
+func deviceName() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "Daily Reflection"
+	}
+	return name
+}
+
+// GetSyncStatus reports whether the sync server is running and where
+func (a *App) GetSyncStatus() (*SyncStatus, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.syncStatus()
+}
+
+// syncStatus is GetSyncStatus for callers already holding a.mu
+func (a *App) syncStatus() (*SyncStatus, error) {
+	deviceID, err := a.sync.DeviceID()
+	if err != nil {
+		return nil, err
+	}
+
+	running, port := a.sync.Listening()
+	status := &SyncStatus{Running: running, Port: port, DeviceID: deviceID, Name: deviceName(), Addresses: []string{}}
+	if !running {
+		return status, nil
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		ip, ok := addr.(*net.IPNet)
+		if !ok || ip.IP.IsLoopback() || ip.IP.To4() == nil {
+			continue
+		}
+		status.Addresses = append(status.Addresses, net.JoinHostPort(ip.IP.String(), strconv.Itoa(port)))
+	}
+	return status, nil
+}
+
+// StartSyncServer lets paired devices on the local network sync with this
+// journal. A port of 0 picks a free one.
+func (a *App) StartSyncServer(port int) (*SyncStatus, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	addr, err := a.sync.Listen(net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("sync server started", "address", addr)
+	return a.syncStatus()
+}
+
+// StopSyncServer stops serving sync requests
+func (a *App) StopSyncServer() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	if err := a.sync.Close(); err != nil {
+		return err
+	}
+	a.logger.Info("sync server stopped")
+	return nil
+}
+
+// StartSyncPairing creates a code another device enters to pair with this one
+func (a *App) StartSyncPairing() (*lansync.PairingCode, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	if running, _ := a.sync.Listening(); !running {
+		return nil, fmt.Errorf("start the sync server before pairing")
+	}
+	return a.sync.StartPairing()
+}
+
+// PairSyncDevice pairs with the device at address (host:port) using the code it shows
+func (a *App) PairSyncDevice(address string, code string) (*lansync.Peer, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	peer, err := a.sync.Pair(address, code)
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("sync device paired", "device", peer.DeviceID, "name", peer.Name)
+	return peer, nil
+}
+
+// GetSyncPeers lists the paired devices
+func (a *App) GetSyncPeers() ([]lansync.Peer, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	return a.sync.Peers()
+}
+
+// RemoveSyncPeer unpairs a device
+func (a *App) RemoveSyncPeer(deviceID string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	return a.sync.RemovePeer(deviceID)
+}
+
+// SyncWithDevice exchanges changes with a paired device. Records changed
+// on both devices keep the later change and are listed as conflicts.
+func (a *App) SyncWithDevice(deviceID string) (*lansync.SyncResult, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	result, err := a.sync.Sync(deviceID)
+	if err != nil {
+		a.logger.Error("sync failed", "device", deviceID, "error", err)
+		return nil, err
+	}
+	a.logger.Info("sync completed", "device", deviceID, "received", result.Received, "sent", result.Sent, "conflicts", len(result.Conflicts))
+	if result.Received > 0 {
+		a.reindexEntries(a.store)
+	}
+	a.emit("sync:completed", result)
+	return result, nil
+}
+
+// GetSyncFolder returns the folder the journal is replicated through, or ""
+func (a *App) GetSyncFolder() (string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+	return a.folder.Folder()
+}
+
+// SetSyncFolder replicates the journal through dir, such as a folder kept in
+// sync by Syncthing or a network share. An empty dir turns folder sync off.
+func (a *App) SetSyncFolder(dir string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return err
+	}
+	a.folder.Stop()
+	if err := a.folder.SetFolder(dir); err != nil {
+		a.startFolderSync()
+		return err
+	}
+	a.logger.Info("sync folder changed", "folder", dir)
+	a.startFolderSync()
+	return nil
+}
+
+// SyncFolderNow writes local changes to the sync folder and replays the
+// changes other devices have written there
+func (a *App) SyncFolderNow() (*foldersync.Result, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+	result, err := a.folder.Sync()
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("folder sync completed", "exported", result.Exported, "imported", result.Imported, "conflicts", len(result.Conflicts))
+	if result.Imported > 0 {
+		a.reindexEntries(a.store)
+	}
+	a.emit("sync:completed", result)
+	return result, nil
 }
@@ -2,33 +2,43 @@
 package backend
 
 import (
+	"archive/zip"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"myproject/backend/database"
 	"myproject/backend/models"
+	"myproject/backend/profiles"
 )
 
 func TestAppIntegration(t *testing.T) {
-	// Test database path
-	testDB := "./api_test.db"
+	// Test database, discarded when the test finishes
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
 
-	// Clean up existing test database
-	os.Remove(testDB)
+	if err := profiles.Seed(models.NewStores(db, nil), profiles.SeedDefault); err != nil {
+		t.Fatalf("Failed to seed test database: %v", err)
+	}
 
 	// Create test app with context
-	app := NewApp()
+	app := NewApp(Options{
+		DB:     db,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
 	ctx := context.Background()
 
 	// Start the app with the test database
-	t.Setenv("DB_PATH", testDB)
 	app.Startup(ctx)
-
-	// Clean up after tests
-	defer func() {
-		app.Shutdown(ctx)
-		os.Remove(testDB)
-	}()
+	defer app.Shutdown(ctx)
 
 	// Test Questions API
 	t.Run("QuestionsAPI", func(t *testing.T) {
@@ -161,7 +171,7 @@ func TestAppIntegration(t *testing.T) {
 	t.Run("CRUDOperations", func(t *testing.T) {
 		// Test question CRUD
 		testQuestion := "New question for CRUD test"
-		question, err := models.AddQuestion(testQuestion)
+		question, err := app.AddQuestion(testQuestion)
 		if err != nil {
 			t.Fatalf("Failed to add question: %v", err)
 		}
@@ -247,3 +257,325 @@ func TestAppIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestStartupAndDiagnostics(t *testing.T) {
+	ctx := context.Background()
+
+	// Test that a database that can't be opened is reported instead of ignored
+	t.Run("StartupFailure", func(t *testing.T) {
+		dir := t.TempDir()
+
+		// A directory can't be opened as a database
+		dbPath := filepath.Join(dir, "DailyReflection.db")
+		os.Mkdir(dbPath, 0755)
+
+		app := NewApp(Options{DBPath: dbPath})
+		app.Startup(ctx)
+		defer app.Shutdown(ctx)
+
+		status := app.GetStartupStatus()
+		if status.Ready {
+			t.Fatalf("Expected startup to fail")
+		}
+
+		if status.Error == nil || status.Error.Stage != "database" {
+			t.Fatalf("Expected database startup error, got %+v", status.Error)
+		}
+
+		// Bindings report the startup error instead of panicking
+		if _, err := app.GetActiveAffirmation(); !errors.Is(err, status.Error) {
+			t.Errorf("Expected the startup error, got %v", err)
+		}
+		if _, err := app.GetSyncStatus(); !errors.Is(err, status.Error) {
+			t.Errorf("Expected the startup error, got %v", err)
+		}
+		if err := app.SetSyncFolder(dir); !errors.Is(err, status.Error) {
+			t.Errorf("Expected the startup error, got %v", err)
+		}
+		if _, err := app.InstallBuiltinQuestionPack("work"); !errors.Is(err, status.Error) {
+			t.Errorf("Expected the startup error, got %v", err)
+		}
+
+		// Diagnostics still work without a database
+		path, err := app.CreateDiagnosticsBundle()
+		if err != nil {
+			t.Fatalf("Failed to create diagnostics bundle: %v", err)
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected bundle at %s: %v", path, err)
+		}
+	})
+
+	// Test the contents of a diagnostics bundle
+	t.Run("DiagnosticsBundle", func(t *testing.T) {
+		dir := t.TempDir()
+		app := NewApp(Options{DBPath: filepath.Join(dir, "DailyReflection.db")})
+		app.Startup(ctx)
+		defer app.Shutdown(ctx)
+
+		if status := app.GetStartupStatus(); !status.Ready {
+			t.Fatalf("Expected startup to succeed, got %+v", status.Error)
+		}
+
+		if _, err := app.AddGratitudeItem("Private gratitude content"); err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+
+		path, err := app.CreateDiagnosticsBundle()
+		if err != nil {
+			t.Fatalf("Failed to create diagnostics bundle: %v", err)
+		}
+
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatalf("Failed to open bundle: %v", err)
+		}
+		defer zr.Close()
+
+		files := map[string]bool{}
+		for _, f := range zr.File {
+			files[f.Name] = true
+		}
+
+		if !files["diagnostics.json"] {
+			t.Errorf("Expected diagnostics.json in bundle, got %v", files)
+		}
+
+		if !files["logs/daily-reflection.log"] {
+			t.Errorf("Expected log file in bundle, got %v", files)
+		}
+	})
+}
+
+func TestProfiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	app := NewApp(Options{
+		DBPath: filepath.Join(dir, "DailyReflection.db"),
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	app.Startup(ctx)
+	defer app.Shutdown(ctx)
+
+	if status := app.GetStartupStatus(); !status.Ready {
+		t.Fatalf("Expected startup to succeed, got %+v", status.Error)
+	}
+
+	if _, err := app.AddGratitudeItem("Personal gratitude"); err != nil {
+		t.Fatalf("Failed to add gratitude item: %v", err)
+	}
+
+	profile, err := app.CreateProfile("Work", profiles.SeedWork)
+	if err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	// Test that switching opens the other profile's database
+	if err := app.SwitchProfile(profile.ID); err != nil {
+		t.Fatalf("Failed to switch profile: %v", err)
+	}
+
+	items, err := app.GetTodayGratitudeItems()
+	if err != nil {
+		t.Fatalf("Failed to get gratitude items: %v", err)
+	}
+
+	if len(items) != 0 {
+		t.Errorf("Expected new profile to have no gratitude items, got %d", len(items))
+	}
+
+	workQuestions, _ := profiles.SeedQuestions(profiles.SeedWork)
+	questions, err := app.GetAllQuestions()
+	if err != nil {
+		t.Fatalf("Failed to get questions: %v", err)
+	}
+
+	if len(questions) != len(workQuestions) {
+		t.Errorf("Expected %d work questions, got %d", len(workQuestions), len(questions))
+	}
+
+	if err := app.DeleteProfile(profile.ID); err == nil {
+		t.Errorf("Expected error deleting the active profile")
+	}
+
+	// Test switching back
+	if err := app.SwitchProfile(profiles.DefaultID); err != nil {
+		t.Fatalf("Failed to switch profile: %v", err)
+	}
+
+	items, err = app.GetTodayGratitudeItems()
+	if err != nil {
+		t.Fatalf("Failed to get gratitude items: %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Errorf("Expected 1 gratitude item in default profile, got %d", len(items))
+	}
+
+	// Test that bindings running while profiles are switched never use a
+	// database being closed
+	done := make(chan struct{})
+	failed := make(chan error, 1)
+	go func() {
+		defer close(failed)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := app.GetTodayGratitudeItems(); err != nil {
+				failed <- err
+				return
+			}
+		}
+	}()
+	for range 5 {
+		if err := app.SwitchProfile(profile.ID); err != nil {
+			t.Fatalf("Failed to switch profile: %v", err)
+		}
+		if err := app.SwitchProfile(profiles.DefaultID); err != nil {
+			t.Fatalf("Failed to switch profile: %v", err)
+		}
+	}
+	close(done)
+	if err := <-failed; err != nil {
+		t.Errorf("Expected bindings to keep working while switching, got %v", err)
+	}
+
+	if err := app.DeleteProfile(profile.ID); err != nil {
+		t.Fatalf("Failed to delete profile: %v", err)
+	}
+
+	list, err := app.ListProfiles()
+	if err != nil {
+		t.Fatalf("Failed to list profiles: %v", err)
+	}
+
+	if len(list) != 1 {
+		t.Errorf("Expected 1 profile after deletion, got %d", len(list))
+	}
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func() *App {
+		app := NewApp(Options{
+			DBPath: filepath.Join(t.TempDir(), "DailyReflection.db"),
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+		app.Startup(ctx)
+		t.Cleanup(func() { app.Shutdown(ctx) })
+
+		if status := app.GetStartupStatus(); !status.Ready {
+			t.Fatalf("Expected startup to succeed, got %+v", status.Error)
+		}
+		return app
+	}
+	laptop, desktop := newApp(), newApp()
+
+	if _, err := desktop.StartSyncPairing(); err == nil {
+		t.Errorf("Expected pairing to need a running sync server")
+	}
+
+	status, err := desktop.StartSyncServer(0)
+	if err != nil {
+		t.Fatalf("Failed to start sync server: %v", err)
+	}
+
+	code, err := desktop.StartSyncPairing()
+	if err != nil {
+		t.Fatalf("Failed to start pairing: %v", err)
+	}
+
+	peer, err := laptop.PairSyncDevice(fmt.Sprintf("127.0.0.1:%d", status.Port), code.Code)
+	if err != nil {
+		t.Fatalf("Failed to pair: %v", err)
+	}
+
+	if _, err := laptop.AddGratitudeItem("A quiet morning"); err != nil {
+		t.Fatalf("Failed to add gratitude item: %v", err)
+	}
+
+	result, err := laptop.SyncWithDevice(peer.DeviceID)
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+
+	if result.Sent == 0 {
+		t.Errorf("Expected changes sent to the desktop")
+	}
+
+	items, err := desktop.GetTodayGratitudeItems()
+	if err != nil {
+		t.Fatalf("Failed to get gratitude items: %v", err)
+	}
+
+	if len(items) != 1 || items[0].Content != "A quiet morning" {
+		t.Errorf("Expected the gratitude item synced, got %+v", items)
+	}
+
+	// Test that switching profiles stops the server
+	profile, err := desktop.CreateProfile("Work", profiles.SeedWork)
+	if err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	if err := desktop.SwitchProfile(profile.ID); err != nil {
+		t.Fatalf("Failed to switch profile: %v", err)
+	}
+
+	status, err = desktop.GetSyncStatus()
+	if err != nil {
+		t.Fatalf("Failed to get sync status: %v", err)
+	}
+
+	if status.Running {
+		t.Errorf("Expected sync server stopped after switching profile")
+	}
+}
+
+func TestFolderSync(t *testing.T) {
+	ctx := context.Background()
+	folder := t.TempDir()
+
+	newApp := func() *App {
+		app := NewApp(Options{
+			DBPath: filepath.Join(t.TempDir(), "DailyReflection.db"),
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+		app.Startup(ctx)
+		t.Cleanup(func() { app.Shutdown(ctx) })
+
+		if err := app.SetSyncFolder(folder); err != nil {
+			t.Fatalf("Failed to set sync folder: %v", err)
+		}
+		return app
+	}
+	laptop, desktop := newApp(), newApp()
+
+	if _, err := laptop.AddGratitudeItem("Long walk"); err != nil {
+		t.Fatalf("Failed to add gratitude item: %v", err)
+	}
+
+	if _, err := laptop.SyncFolderNow(); err != nil {
+		t.Fatalf("Failed to sync folder: %v", err)
+	}
+
+	// Each app also syncs in the background, so only the outcome is checked
+	if _, err := desktop.SyncFolderNow(); err != nil {
+		t.Fatalf("Failed to sync folder: %v", err)
+	}
+
+	items, _ := desktop.GetTodayGratitudeItems()
+	if len(items) != 1 || items[0].Content != "Long walk" {
+		t.Errorf("Expected the gratitude item synced, got %+v", items)
+	}
+
+	if err := desktop.SetSyncFolder(filepath.Join(folder, "missing")); err == nil {
+		t.Errorf("Expected a missing sync folder to be rejected")
+	}
+}
@@ -0,0 +1,391 @@
+// backend/attachments/attachments.go
+package attachments
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// Entry types attachments can belong to
+const (
+	EntryAnswer     = "answer"
+	EntryCreativity = "creativity"
+	EntryGratitude  = "gratitude"
+)
+
+var entryTables = map[string]string{
+	EntryAnswer:     "answers",
+	EntryCreativity: "creativity_entries",
+	EntryGratitude:  "gratitude_items",
+}
+
+const (
+	// MaxSize is the largest file that can be attached
+	MaxSize = 25 << 20
+
+	// thumbnailSize is the longest side of a thumbnail, in pixels
+	thumbnailSize = 256
+
+	// maxImagePixels is the largest image decoded to make a thumbnail.
+	// A small file can declare a huge image, so larger ones are skipped.
+	maxImagePixels = 40_000_000
+)
+
+// Attachment is a file attached to an entry
+type Attachment struct {
+	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
+	EntryType string    `json:"entryType"`
+	EntryID   int64     `json:"entryId"`
+	Name      string    `json:"name"` // The file's name when it was attached
+	Hash      string    `json:"hash"` // SHA-256 of the content, which names the stored file
+	MimeType  string    `json:"mimeType"`
+	Size      int64     `json:"size"`
+	Width     int       `json:"width"` // Image dimensions, or 0 for files that aren't images
+	Height    int       `json:"height"`
+	Path      string    `json:"path"`
+	Thumbnail string    `json:"thumbnail"` // A JPEG data URL, or empty for files that aren't images
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store keeps attached files in a directory named by their SHA-256, so a
+// file attached to several entries is stored once. The links to entries
+// are kept in the database, and aren't synced to other devices as the
+// files themselves aren't.
+type Store struct {
+	db  *sql.DB
+	bus *events.Bus
+	dir string
+}
+
+// Dir returns the attachments directory for a database file, which sits
+// next to it
+func Dir(dbPath string) string {
+	return strings.TrimSuffix(dbPath, filepath.Ext(dbPath)) + ".attachments"
+}
+
+// New creates a Store for db keeping files in dir. Changes are published
+// on bus, which may be nil.
+func New(db *sql.DB, bus *events.Bus, dir string) *Store {
+	return &Store{db: db, bus: bus, dir: dir}
+}
+
+// Add attaches the file at path to an entry
+func (s *Store) Add(entryType string, entryID int64, path string) (*Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > MaxSize {
+		return nil, fmt.Errorf("attachments can be at most %d MB", MaxSize>>20)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.add(entryType, entryID, filepath.Base(path), data)
+}
+
+func (s *Store) add(entryType string, entryID int64, name string, data []byte) (*Attachment, error) {
+	entryUUID, err := s.entryUUID(entryType, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	a := Attachment{
+		UUID:      ids.New(),
+		EntryType: entryType,
+		EntryID:   entryID,
+		Name:      name,
+		Hash:      hex.EncodeToString(sum[:]),
+		MimeType:  mimeType(name, data),
+		Size:      int64(len(data)),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.writeFile(s.path(a.Hash), data); err != nil {
+		return nil, err
+	}
+
+	// Images that can't be decoded, or are too large to, are kept without
+	// a thumbnail
+	if img, ok := decodeImage(data); ok {
+		a.Width, a.Height = img.Bounds().Dx(), img.Bounds().Dy()
+		var thumb bytes.Buffer
+		if err := jpeg.Encode(&thumb, thumbnail(img), &jpeg.Options{Quality: 80}); err != nil {
+			return nil, err
+		}
+		if err := s.writeFile(s.thumbnailPath(a.Hash), thumb.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := s.db.Exec(`
+		INSERT INTO attachments (uuid, entity_type, entity_uuid, hash, name, mime_type, size, width, height, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.UUID, entryType, entryUUID, a.Hash, a.Name, a.MimeType, a.Size, a.Width, a.Height, a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if a.ID, err = res.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AttachmentAdded, ID: a.ID, Key: entryType})
+	s.fill(&a)
+	return &a, nil
+}
+
+// List returns the attachments of an entry in the order they were added
+func (s *Store) List(entryType string, entryID int64) ([]Attachment, error) {
+	entryUUID, err := s.entryUUID(entryType, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, uuid, hash, name, mime_type, size, width, height, created_at
+		FROM attachments
+		WHERE entity_type = ? AND entity_uuid = ?
+		ORDER BY id`, entryType, entryUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Attachment{}
+	for rows.Next() {
+		a := Attachment{EntryType: entryType, EntryID: entryID}
+		err := rows.Scan(&a.ID, &a.UUID, &a.Hash, &a.Name, &a.MimeType, &a.Size, &a.Width, &a.Height, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		s.fill(&a)
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// Delete removes an attachment, and its file once no entry refers to it
+func (s *Store) Delete(id int64) error {
+	var hash, entryType string
+	err := s.db.QueryRow(`SELECT hash, entity_type FROM attachments WHERE id = ?`, id).Scan(&hash, &entryType)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM attachments WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if err := s.removeUnused(hash); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AttachmentDeleted, ID: id, Key: entryType})
+	return nil
+}
+
+// Prune removes the attachments of deleted entries and any files no
+// attachment refers to, returning how many files were removed
+func (s *Store) Prune() (int, error) {
+	for entryType, table := range entryTables {
+		// Table names come from entryTables, not user input
+		_, err := s.db.Exec(fmt.Sprintf(`
+			DELETE FROM attachments
+			WHERE entity_type = ? AND entity_uuid NOT IN (SELECT uuid FROM %s)`, table), entryType)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	entries, err := filepath.Glob(filepath.Join(s.dir, "*", "*"))
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, path := range entries {
+		hash := strings.TrimSuffix(filepath.Base(path), thumbnailSuffix)
+		if len(hash) != sha256.Size*2 || path != s.path(hash) {
+			continue
+		}
+		used, err := s.used(hash)
+		if err != nil {
+			return removed, err
+		}
+		if !used {
+			if err := s.removeFiles(hash); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// entryUUID returns the UUID of an entry, which attachments are linked by
+func (s *Store) entryUUID(entryType string, entryID int64) (string, error) {
+	table, ok := entryTables[entryType]
+	if !ok {
+		return "", fmt.Errorf("unknown entry type %q", entryType)
+	}
+
+	var uuid string
+	// Table names come from entryTables, not user input
+	err := s.db.QueryRow(fmt.Sprintf(`SELECT uuid FROM %s WHERE id = ?`, table), entryID).Scan(&uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%s %d not found", entryType, entryID)
+	}
+	return uuid, err
+}
+
+// fill sets the stored file's path and thumbnail
+func (s *Store) fill(a *Attachment) {
+	a.Path = s.path(a.Hash)
+	if thumb, err := os.ReadFile(s.thumbnailPath(a.Hash)); err == nil {
+		a.Thumbnail = "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(thumb)
+	}
+}
+
+const thumbnailSuffix = ".thumb.jpg"
+
+// path returns where a file is stored. Files are spread over directories
+// named by the first two characters of their hash.
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+func (s *Store) thumbnailPath(hash string) string {
+	return s.path(hash) + thumbnailSuffix
+}
+
+// writeFile writes data to path unless it's already there. The content is
+// named by its hash, so an existing file has the same content.
+func (s *Store) writeFile(path string, data []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// used reports whether any attachment refers to a file
+func (s *Store) used(hash string) (bool, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM attachments WHERE hash = ?`, hash).Scan(&count)
+	return count > 0, err
+}
+
+// removeUnused removes a file and its thumbnail if no attachment refers to it
+func (s *Store) removeUnused(hash string) error {
+	used, err := s.used(hash)
+	if err != nil || used {
+		return err
+	}
+	return s.removeFiles(hash)
+}
+
+func (s *Store) removeFiles(hash string) error {
+	for _, path := range []string{s.path(hash), s.thumbnailPath(hash)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// mimeType guesses a file's type from its content, then its name
+func mimeType(name string, data []byte) string {
+	detected := http.DetectContentType(data)
+	if detected != "application/octet-stream" && !strings.HasPrefix(detected, "text/plain") {
+		return detected
+	}
+	if byName := mime.TypeByExtension(filepath.Ext(name)); byName != "" {
+		return byName
+	}
+	return detected
+}
+
+// decodeImage decodes an image no larger than maxImagePixels
+func decodeImage(data []byte) (image.Image, bool) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return nil, false
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err == nil
+}
+
+// thumbnail scales an image to fit thumbnailSize, averaging the pixels
+// each thumbnail pixel covers and flattening transparency onto white
+func thumbnail(src image.Image) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if longest := max(w, h); longest > thumbnailSize {
+		tw = max(1, w*thumbnailSize/longest)
+		th = max(1, h*thumbnailSize/longest)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+
+			// Sample at most 4x4 pixels per box, which is plenty for a thumbnail
+			stepX, stepY := max(1, (x1-x0)/4), max(1, (y1-y0)/4)
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy += stepY {
+				for sx := x0; sx < x1; sx += stepX {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += cr + 0xffff - ca
+					g += cg + 0xffff - ca
+					bl += cb + 0xffff - ca
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(bl / n >> 8), 0xff})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,190 @@
+// backend/attachments/attachments_test.go
+package attachments
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"myproject/backend/database"
+	"myproject/backend/models"
+)
+
+// writePNG writes a w by h PNG and returns its path
+func writePNG(t *testing.T, dir string, name string, w, h int) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0x80, 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("Failed to write image: %v", err)
+	}
+	return path
+}
+
+func TestAttachments(t *testing.T) {
+	t.Parallel()
+
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	stores := models.NewStores(db, nil)
+	dir := filepath.Join(t.TempDir(), "journal.attachments")
+	store := New(db, nil, dir)
+
+	entry, err := stores.Creativity.Create(models.CreativityEntry{Title: "Sketch", Content: "A tree", EntryDate: "2024-06-01"})
+	if err != nil {
+		t.Fatalf("Failed to create creativity entry: %v", err)
+	}
+	item, err := stores.Gratitude.Add("The view from the hill")
+	if err != nil {
+		t.Fatalf("Failed to add gratitude item: %v", err)
+	}
+
+	src := t.TempDir()
+	photo := writePNG(t, src, "tree.png", 600, 300)
+
+	// Test that images get a thumbnail and the same file is stored once
+	t.Run("AddImage", func(t *testing.T) {
+		first, err := store.Add(EntryCreativity, entry.ID, photo)
+		if err != nil {
+			t.Fatalf("Failed to add attachment: %v", err)
+		}
+		if first.MimeType != "image/png" || first.Width != 600 || first.Height != 300 {
+			t.Errorf("Expected a 600x300 PNG, got %+v", first)
+		}
+		if !strings.HasPrefix(first.Thumbnail, "data:image/jpeg;base64,") {
+			t.Errorf("Expected a JPEG thumbnail, got %.40q", first.Thumbnail)
+		}
+		if filepath.Base(first.Path) != first.Hash || !strings.HasPrefix(first.Path, dir) {
+			t.Errorf("Expected the file stored under its hash in %s, got %s", dir, first.Path)
+		}
+
+		thumb, err := os.Open(store.thumbnailPath(first.Hash))
+		if err != nil {
+			t.Fatalf("Failed to open thumbnail: %v", err)
+		}
+		defer thumb.Close()
+		config, _, err := image.DecodeConfig(thumb)
+		if err != nil {
+			t.Fatalf("Failed to decode thumbnail: %v", err)
+		}
+		if config.Width != thumbnailSize || config.Height != thumbnailSize/2 {
+			t.Errorf("Expected a %dx%d thumbnail, got %dx%d", thumbnailSize, thumbnailSize/2, config.Width, config.Height)
+		}
+
+		second, err := store.Add(EntryGratitude, item.ID, photo)
+		if err != nil {
+			t.Fatalf("Failed to add attachment: %v", err)
+		}
+		if second.Path != first.Path {
+			t.Errorf("Expected the same content stored once, got %s and %s", first.Path, second.Path)
+		}
+	})
+
+	// Test that other files are kept without a thumbnail
+	t.Run("AddFile", func(t *testing.T) {
+		notes := filepath.Join(src, "notes.md")
+		if err := os.WriteFile(notes, []byte("# Ideas\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		a, err := store.Add(EntryCreativity, entry.ID, notes)
+		if err != nil {
+			t.Fatalf("Failed to add attachment: %v", err)
+		}
+		if a.Thumbnail != "" || a.Width != 0 || a.Name != "notes.md" {
+			t.Errorf("Expected a file without a thumbnail, got %+v", a)
+		}
+
+		if _, err := store.Add("affirmation", 1, notes); err == nil {
+			t.Error("Expected an unknown entry type to be rejected")
+		}
+		if _, err := store.Add(EntryAnswer, 999, notes); err == nil {
+			t.Error("Expected a missing entry to be rejected")
+		}
+
+		list, err := store.List(EntryCreativity, entry.ID)
+		if err != nil {
+			t.Fatalf("Failed to list attachments: %v", err)
+		}
+		if len(list) != 2 || list[0].Name != "tree.png" || list[1].Name != "notes.md" {
+			t.Errorf("Expected both attachments in the order added, got %+v", list)
+		}
+	})
+
+	// Test that a file is removed once nothing refers to it
+	t.Run("Delete", func(t *testing.T) {
+		list, _ := store.List(EntryCreativity, entry.ID)
+		if err := store.Delete(list[0].ID); err != nil {
+			t.Fatalf("Failed to delete attachment: %v", err)
+		}
+		if _, err := os.Stat(list[0].Path); err != nil {
+			t.Errorf("Expected the file kept for the gratitude item, got %v", err)
+		}
+
+		// Deleting the gratitude item leaves its attachment to be pruned
+		if err := stores.Gratitude.Delete(item.ID); err != nil {
+			t.Fatalf("Failed to delete gratitude item: %v", err)
+		}
+		removed, err := store.Prune()
+		if err != nil {
+			t.Fatalf("Failed to prune attachments: %v", err)
+		}
+		if removed != 1 {
+			t.Errorf("Expected 1 file pruned, got %d", removed)
+		}
+		if _, err := os.Stat(list[0].Path); !os.IsNotExist(err) {
+			t.Errorf("Expected the unused file removed, got %v", err)
+		}
+		if _, err := os.Stat(list[1].Path); err != nil {
+			t.Errorf("Expected the file still attached kept, got %v", err)
+		}
+	})
+
+	// Test that an image declaring more pixels than maxImagePixels is kept
+	// without being decoded
+	t.Run("LargeImage", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Black}), nil); err != nil {
+			t.Fatalf("Failed to encode image: %v", err)
+		}
+		// The logical screen size follows the 6 byte signature
+		data := buf.Bytes()
+		copy(data[6:10], []byte{0x30, 0x75, 0x30, 0x75})
+		path := filepath.Join(src, "bomb.gif")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("Failed to write image: %v", err)
+		}
+
+		sketch, err := stores.Creativity.Create(models.CreativityEntry{Title: "Mural", Content: "A wall", EntryDate: "2024-06-02"})
+		if err != nil {
+			t.Fatalf("Failed to create creativity entry: %v", err)
+		}
+		a, err := store.Add(EntryCreativity, sketch.ID, path)
+		if err != nil {
+			t.Fatalf("Failed to add attachment: %v", err)
+		}
+		if a.Thumbnail != "" || a.Width != 0 {
+			t.Errorf("Expected a large image without a thumbnail, got %+v", a)
+		}
+	})
+}
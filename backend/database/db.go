@@ -3,22 +3,136 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"myproject/backend/ids"
+	"myproject/backend/prompts"
+	"myproject/backend/sentiment"
+	"myproject/backend/textstats"
 
 	_ "modernc.org/sqlite"
 )
 
-var DB *sql.DB
+// migrations upgrade the schema one version at a time. The schema version
+// is kept in SQLite's user_version pragma, so migration i brings the
+// database to version i+1. Append new migrations; never reorder them.
+var migrations = []func(tx *sql.Tx) error{
+	createBaseTables,
+	addQuestionPacks,
+	addAnswerDrafts,
+	addWritingStats,
+	addSentiment,
+	addSyncTracking,
+	addRecordUUIDs,
+	addHabits,
+	addGoals,
+	addCreativityPrompts,
+	addCreativityKinds,
+	addAttachments,
+	addTags,
+	addEntryLinks,
+	addTemplates,
+	addRoutines,
+	addQuestionSkips,
+	addQuestionSnoozes,
+	requireUUIDs,
+	addSyncAliases,
+	addOriginSeqs,
+	addDraftFields,
+}
+
+// Open opens the database at dbPath and migrates it to the latest schema
+func Open(dbPath string) (*sql.DB, error) {
+	// Background sync and reindexing write alongside the app, so writers
+	// wait their turn, and take the write lock up front so two transactions
+	// can't each hold a read lock while waiting for the other's
+	db, err := sql.Open("sqlite", dbPath+"?_txlock=immediate&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// OpenMemory opens a new, empty in-memory database with the latest schema.
+// Each call returns an independent database, which makes it suitable as a
+// test fixture.
+func OpenMemory() (*sql.DB, error) {
+	// An in-memory database lives only as long as its connection, so the
+	// pool is limited to the one connection holding the data
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
 
-// Initialize initializes the database connection and creates tables if they don't exist
-func Initialize(dbPath string) error {
-	var err error
-	DB, err = sql.Open("sqlite", dbPath)
+// LatestSchemaVersion is the schema version this build migrates to
+func LatestSchemaVersion() int {
+	return len(migrations)
+}
+
+// SchemaVersion reads the schema version of an open database
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow(`PRAGMA user_version`).Scan(&version)
+	return version, err
+}
+
+// migrate applies every migration newer than the database's schema version
+func migrate(db *sql.DB) error {
+	version, err := SchemaVersion(db)
 	if err != nil {
 		return err
 	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this app supports (%d)", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
 
+		if err := migrations[i](tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrating database to version %d: %w", i+1, err)
+		}
+
+		// PRAGMA doesn't accept bound parameters
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createBaseTables creates the original tables. Databases created before
+// schema versioning already have them, which IF NOT EXISTS allows for.
+func createBaseTables(tx *sql.Tx) error {
 	// Create questions table
-	_, err = DB.Exec(`
+	_, err := tx.Exec(`
 	CREATE TABLE IF NOT EXISTS questions (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		content TEXT NOT NULL,
@@ -30,7 +144,7 @@ func Initialize(dbPath string) error {
 	}
 
 	// Create answers table
-	_, err = DB.Exec(`
+	_, err = tx.Exec(`
 	CREATE TABLE IF NOT EXISTS answers (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		question_id INTEGER NOT NULL,
@@ -44,7 +158,7 @@ func Initialize(dbPath string) error {
 	}
 
 	// Create affirmations table
-	_, err = DB.Exec(`
+	_, err = tx.Exec(`
 	CREATE TABLE IF NOT EXISTS affirmations (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		content TEXT NOT NULL,
@@ -56,7 +170,7 @@ func Initialize(dbPath string) error {
 	}
 
 	// Create affirmation logs table
-	_, err = DB.Exec(`
+	_, err = tx.Exec(`
 	CREATE TABLE IF NOT EXISTS affirmation_logs (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		affirmation_id INTEGER NOT NULL,
@@ -68,16 +182,19 @@ func Initialize(dbPath string) error {
 	}
 
 	// Create gratitude items table
-	_, err = DB.Exec(`
+	_, err = tx.Exec(`
 	CREATE TABLE IF NOT EXISTS gratitude_items (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		content TEXT NOT NULL,
 		entry_date TEXT NOT NULL,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	)`)
+	if err != nil {
+		return err
+	}
 
 	// Create creativity entries table
-	_, err = DB.Exec(`
+	_, err = tx.Exec(`
 	CREATE TABLE IF NOT EXISTS creativity_entries (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		content TEXT NOT NULL,
@@ -89,13 +206,885 @@ func Initialize(dbPath string) error {
 		return err
 	}
 
+	// Create settings table
+	_, err = tx.Exec(`
+	CREATE TABLE IF NOT EXISTS settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
 	return err
 }
 
-// Close closes the database connection
-func Close() error {
-	if DB != nil {
-		return DB.Close()
+// addQuestionPacks records installed question packs and which pack each question came from
+func addQuestionPacks(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE question_packs (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		version TEXT NOT NULL DEFAULT '',
+		author TEXT NOT NULL DEFAULT '',
+		language TEXT NOT NULL DEFAULT '',
+		tags TEXT NOT NULL DEFAULT '[]',
+		description TEXT NOT NULL DEFAULT '',
+		installed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE questions ADD COLUMN pack_id TEXT REFERENCES question_packs(id)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX idx_questions_pack_id ON questions(pack_id)`)
+	return err
+}
+
+// addAnswerDrafts keeps unsaved answers, one per question per day
+func addAnswerDrafts(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE answer_drafts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		question_id INTEGER NOT NULL,
+		content TEXT NOT NULL,
+		draft_date TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (question_id, draft_date),
+		FOREIGN KEY (question_id) REFERENCES questions(id)
+	)`)
+	return err
+}
+
+// addWritingStats stores word and character counts on answers and creativity
+// entries, backfilling existing rows, and records writing sessions
+func addWritingStats(tx *sql.Tx) error {
+	for _, table := range []string{"answers", "creativity_entries"} {
+		_, err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0`)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN char_count INTEGER NOT NULL DEFAULT 0`)
+		if err != nil {
+			return err
+		}
+
+		err = backfill(tx, table, []string{"word_count", "char_count"}, func(content string) []any {
+			words, chars := textstats.Count(content)
+			return []any{words, chars}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`
+	CREATE TABLE writing_sessions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		activity TEXT NOT NULL,
+		started_at TIMESTAMP NOT NULL,
+		ended_at TIMESTAMP
+	)`)
+	return err
+}
+
+// backfill computes column values for existing rows from their content,
+// for values SQL can't compute itself
+func backfill(tx *sql.Tx, table string, columns []string, compute func(content string) []any) error {
+	rows, err := tx.Query(`SELECT id, content FROM ` + table)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		id     int64
+		values []any
+	}
+	var updates []row
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return err
+		}
+		updates = append(updates, row{id, compute(content)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	set := make([]string, len(columns))
+	for i, c := range columns {
+		set[i] = c + ` = ?`
+	}
+	query := `UPDATE ` + table + ` SET ` + strings.Join(set, ", ") + ` WHERE id = ?`
+
+	for _, u := range updates {
+		if _, err := tx.Exec(query, append(u.values, u.id)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSentiment stores a sentiment score on answers, gratitude items and
+// creativity entries, backfilling existing rows
+func addSentiment(tx *sql.Tx) error {
+	for _, table := range []string{"answers", "gratitude_items", "creativity_entries"} {
+		_, err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN sentiment REAL NOT NULL DEFAULT 0`)
+		if err != nil {
+			return err
+		}
+
+		err = backfill(tx, table, []string{"sentiment"}, func(content string) []any {
+			return []any{sentiment.Score(content)}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncedTables are the tables whose rows are replicated between devices
+var syncedTables = []string{"questions", "answers", "affirmations", "affirmation_logs", "gratitude_items", "creativity_entries"}
+
+// addSyncTracking gives synced rows a UUID and records every change to them
+// in sync_records, so devices can exchange what changed since they last met
+func addSyncTracking(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE sync_records (
+		table_name TEXT NOT NULL,
+		uuid TEXT NOT NULL,
+		seq INTEGER NOT NULL,
+		modified INTEGER NOT NULL,
+		device TEXT NOT NULL,
+		deleted INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (table_name, uuid)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX idx_sync_records_seq ON sync_records(seq)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	CREATE TABLE sync_peers (
+		device_id TEXT PRIMARY KEY,
+		name TEXT NOT NULL DEFAULT '',
+		address TEXT NOT NULL DEFAULT '',
+		token TEXT NOT NULL,
+		sent_seq INTEGER NOT NULL DEFAULT 0,
+		received_seq INTEGER NOT NULL DEFAULT 0,
+		paired_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		last_sync_at TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT OR IGNORE INTO settings (key, value) VALUES ('sync.device_id', ?)`, ids.New())
+	if err != nil {
+		return err
+	}
+
+	for _, table := range syncedTables {
+		if _, err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN uuid TEXT`); err != nil {
+			return err
+		}
+
+		if err := assignUUIDs(tx, table); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`CREATE UNIQUE INDEX idx_` + table + `_uuid ON ` + table + `(uuid)`); err != nil {
+			return err
+		}
+
+		if err := trackChanges(tx, table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trackChanges records every insert, update and delete of table's rows in
+// sync_records, so they are replicated to other devices. Existing rows
+// count as changed once. The table needs a uuid column.
+func trackChanges(tx *sql.Tx, table string) error {
+	// Milliseconds since the Unix epoch, and this device's ID
+	const now = `CAST((julianday('now') - 2440587.5) * 86400000 AS INTEGER)`
+	const device = `(SELECT value FROM settings WHERE key = 'sync.device_id')`
+	const nextSeq = `(SELECT COALESCE(MAX(seq), 0) + 1 FROM sync_records)`
+
+	// Existing rows are recorded in the order they were created
+	_, err := tx.Exec(`
+	INSERT INTO sync_records (table_name, uuid, seq, modified, device)
+	SELECT '` + table + `', uuid, (SELECT COALESCE(MAX(seq), 0) FROM sync_records) + ROW_NUMBER() OVER (ORDER BY id), ` + now + `, ` + device + `
+	FROM ` + table)
+	if err != nil {
+		return err
+	}
+
+	record := func(ref string, deleted int) string {
+		return fmt.Sprintf(`
+		INSERT INTO sync_records (table_name, uuid, seq, modified, device, deleted)
+		VALUES ('%s', %s.uuid, %s, %s, %s, %d)
+		ON CONFLICT (table_name, uuid) DO UPDATE SET
+			seq = excluded.seq, modified = excluded.modified, device = excluded.device, deleted = excluded.deleted;`,
+			table, ref, nextSeq, now, device, deleted)
+	}
+
+	// Rows inserted without a UUID get a random (version 4) one; the
+	// update trigger then records the change
+	triggers := []string{
+		`CREATE TRIGGER ` + table + `_uuid AFTER INSERT ON ` + table + ` WHEN NEW.uuid IS NULL BEGIN
+		UPDATE ` + table + ` SET uuid = lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' ||
+			substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) ||
+			substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))
+		WHERE id = NEW.id;
+		END`,
+		`CREATE TRIGGER ` + table + `_sync_insert AFTER INSERT ON ` + table + ` WHEN NEW.uuid IS NOT NULL BEGIN` +
+			record("NEW", 0) + `
+		END`,
+		`CREATE TRIGGER ` + table + `_sync_update AFTER UPDATE ON ` + table + ` WHEN NEW.uuid IS NOT NULL BEGIN` +
+			record("NEW", 0) + `
+		END`,
+		`CREATE TRIGGER ` + table + `_sync_delete AFTER DELETE ON ` + table + ` WHEN OLD.uuid IS NOT NULL BEGIN` +
+			record("OLD", 1) + `
+		END`,
+	}
+	for _, trigger := range triggers {
+		if _, err := tx.Exec(trigger); err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// requireUUID returns a trigger that rejects rows inserted without a UUID.
+// UUIDs are generated by the models, so a row without one would never be synced.
+func requireUUID(table string) string {
+	return `CREATE TRIGGER ` + table + `_uuid BEFORE INSERT ON ` + table + ` WHEN NEW.uuid IS NULL BEGIN
+		SELECT RAISE(ABORT, '` + table + ` rows need a uuid');
+		END`
+}
+
+// createdColumns are the columns holding when a row was created, for the
+// tables where it isn't created_at
+var createdColumns = map[string]string{
+	"affirmation_logs": "completed_at",
+	"writing_sessions": "started_at",
+}
+
+// assignUUIDs gives every row of table a UUID that sorts by when the row
+// was created, as if it had been given one then
+func assignUUIDs(tx *sql.Tx, table string) error {
+	created, ok := createdColumns[table]
+	if !ok {
+		created = "created_at"
+	}
+
+	rows, err := tx.Query(`SELECT id, ` + created + ` FROM ` + table)
+	if err != nil {
+		return err
+	}
+	uuids := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var at sql.NullTime
+		if err := rows.Scan(&id, &at); err != nil {
+			rows.Close()
+			return err
+		}
+		if !at.Valid {
+			at.Time = time.Now()
+		}
+		uuids[id] = ids.At(at.Time)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, uuid := range uuids {
+		if _, err := tx.Exec(`UPDATE `+table+` SET uuid = ? WHERE id = ?`, uuid, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRecordUUIDs gives the rows that aren't synced a UUID too, so every
+// record can be told apart across journals
+func addRecordUUIDs(tx *sql.Tx) error {
+	for _, table := range []string{"answer_drafts", "writing_sessions"} {
+		if _, err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN uuid TEXT`); err != nil {
+			return err
+		}
+		if err := assignUUIDs(tx, table); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`CREATE UNIQUE INDEX idx_` + table + `_uuid ON ` + table + `(uuid)`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// affirmationHabitUUID identifies the built-in affirmation habit. It is the
+// same in every journal, so the habit merges when journals are synced.
+const affirmationHabitUUID = "3b0f6c1e-8d2a-4f57-b6e4-7a9c2d1f0e85"
+
+// addHabits adds user-defined habits and their check-ins. Affirmations become
+// a built-in habit whose check-ins stay in affirmation_logs.
+func addHabits(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE habits (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		builtin TEXT UNIQUE,
+		name TEXT NOT NULL,
+		frequency TEXT NOT NULL DEFAULT 'daily',
+		target INTEGER NOT NULL DEFAULT 1,
+		unit TEXT NOT NULL DEFAULT '',
+		archived INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	CREATE TABLE habit_logs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		habit_id INTEGER NOT NULL,
+		log_date TEXT NOT NULL,
+		quantity REAL,
+		note TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (habit_id) REFERENCES habits(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX idx_habit_logs_habit_date ON habit_logs(habit_id, log_date)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	INSERT INTO habits (uuid, builtin, name, frequency, target)
+	VALUES (?, 'affirmation', 'Affirmation', 'daily', 1)`, affirmationHabitUUID)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"habits", "habit_logs"} {
+		if _, err := tx.Exec(`CREATE UNIQUE INDEX idx_` + table + `_uuid ON ` + table + `(uuid)`); err != nil {
+			return err
+		}
+		if err := trackChanges(tx, table); err != nil {
+			return err
+		}
+	}
+
+	// Every journal starts with the same built-in habit, so it isn't a
+	// change to send to other devices until it is edited
+	_, err = tx.Exec(`
+	UPDATE sync_records SET modified = 0, device = ''
+	WHERE table_name = 'habits' AND uuid = ?`, affirmationHabitUUID)
+	return err
+}
+
+// addGoals adds goals with their milestones, weekly check-ins, and links to
+// the entries written about them. Links refer to entries by UUID, so they
+// survive syncing.
+func addGoals(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE goals (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		title TEXT NOT NULL,
+		why TEXT NOT NULL DEFAULT '',
+		target_date TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'active',
+		completed_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	CREATE TABLE goal_milestones (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		goal_id INTEGER NOT NULL,
+		title TEXT NOT NULL,
+		target_date TEXT NOT NULL DEFAULT '',
+		position INTEGER NOT NULL DEFAULT 0,
+		completed_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (goal_id) REFERENCES goals(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	CREATE TABLE goal_checkins (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		goal_id INTEGER NOT NULL,
+		week TEXT NOT NULL,
+		progress INTEGER NOT NULL DEFAULT 0,
+		note TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (goal_id) REFERENCES goals(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	CREATE TABLE goal_links (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		goal_id INTEGER NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_uuid TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (goal_id) REFERENCES goals(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX idx_goal_milestones_goal ON goal_milestones(goal_id)`,
+		`CREATE INDEX idx_goal_checkins_goal ON goal_checkins(goal_id, week)`,
+		`CREATE INDEX idx_goal_links_goal ON goal_links(goal_id)`,
+		`CREATE INDEX idx_goal_links_entity ON goal_links(entity_type, entity_uuid)`,
+	} {
+		if _, err := tx.Exec(index); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"goals", "goal_milestones", "goal_checkins", "goal_links"} {
+		if _, err := tx.Exec(`CREATE UNIQUE INDEX idx_` + table + `_uuid ON ` + table + `(uuid)`); err != nil {
+			return err
+		}
+		if err := trackChanges(tx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCreativityPrompts moves the creativity prompts into the database,
+// seeded with the built-in list, and records which prompt each entry
+// responded to
+func addCreativityPrompts(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE creativity_prompts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		content TEXT NOT NULL,
+		category TEXT NOT NULL DEFAULT '',
+		builtin INTEGER NOT NULL DEFAULT 0,
+		archived INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	// The prompt shown on each day, so it doesn't change once seen
+	_, err = tx.Exec(`
+	CREATE TABLE creativity_daily_prompts (
+		entry_date TEXT PRIMARY KEY,
+		prompt_id INTEGER NOT NULL,
+		FOREIGN KEY (prompt_id) REFERENCES creativity_prompts(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE creativity_entries ADD COLUMN prompt_id INTEGER REFERENCES creativity_prompts(id)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX idx_creativity_entries_prompt ON creativity_entries(prompt_id)`)
+	if err != nil {
+		return err
+	}
+
+	for _, content := range prompts.Creativity() {
+		_, err := tx.Exec(`
+		INSERT INTO creativity_prompts (uuid, content, category, builtin)
+		VALUES (?, ?, ?, 1)`, ids.ForPrompt(content), content, prompts.CreativityCategory)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`CREATE UNIQUE INDEX idx_creativity_prompts_uuid ON creativity_prompts(uuid)`); err != nil {
+		return err
+	}
+	if err := trackChanges(tx, "creativity_prompts"); err != nil {
+		return err
+	}
+
+	// Every journal starts with the same built-in prompts, so they aren't
+	// changes to send to other devices until they are edited
+	_, err = tx.Exec(`
+	UPDATE sync_records SET modified = 0, device = ''
+	WHERE table_name = 'creativity_prompts'`)
+	return err
+}
+
+// addCreativityKinds gives creativity entries a title and kind. Days can
+// have several entries, so entry_date is deliberately left non-unique.
+func addCreativityKinds(tx *sql.Tx) error {
+	for _, query := range []string{
+		`ALTER TABLE creativity_entries ADD COLUMN title TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE creativity_entries ADD COLUMN kind TEXT NOT NULL DEFAULT ''`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addAttachments links files in the attachments directory to entries. The
+// files aren't synced, so neither are the links.
+func addAttachments(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE attachments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT NOT NULL UNIQUE,
+		entity_type TEXT NOT NULL,
+		entity_uuid TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		name TEXT NOT NULL,
+		mime_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		width INTEGER NOT NULL DEFAULT 0,
+		height INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`CREATE INDEX idx_attachments_entity ON attachments(entity_type, entity_uuid)`,
+		`CREATE INDEX idx_attachments_hash ON attachments(hash)`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTags indexes the #hashtags written in entries. Tags are worked out
+// from the content, which is synced, so they aren't synced themselves.
+func addTags(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE tags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+	CREATE TABLE entry_tags (
+		tag_id INTEGER NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id INTEGER NOT NULL,
+		entry_date TEXT NOT NULL,
+		PRIMARY KEY (tag_id, entity_type, entity_id),
+		FOREIGN KEY (tag_id) REFERENCES tags(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX idx_entry_tags_entity ON entry_tags(entity_type, entity_id)`); err != nil {
+		return err
+	}
+
+	// Deleted entries lose their tags however they are deleted, including by sync
+	for entityType, table := range map[string]string{
+		"answer":      "answers",
+		"gratitude":   "gratitude_items",
+		"creativity":  "creativity_entries",
+		"affirmation": "affirmations",
+	} {
+		_, err := tx.Exec(fmt.Sprintf(`
+		CREATE TRIGGER untag_%[1]s AFTER DELETE ON %[1]s
+		BEGIN
+			DELETE FROM entry_tags WHERE entity_type = '%[2]s' AND entity_id = OLD.id;
+		END`, table, entityType))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addEntryLinks indexes the [[links]] written in entries. Like tags, links
+// are worked out from the synced content, so they aren't synced themselves.
+// Targets are found by UUID so links survive sync renumbering entries.
+func addEntryLinks(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE entry_links (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		source_type TEXT NOT NULL,
+		source_id INTEGER NOT NULL,
+		ref TEXT NOT NULL,
+		target_type TEXT NOT NULL DEFAULT '',
+		target_uuid TEXT
+	)`)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`CREATE INDEX idx_entry_links_source ON entry_links(source_type, source_id)`,
+		`CREATE INDEX idx_entry_links_target ON entry_links(target_uuid)`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	// Deleted entries lose the links written in them however they are deleted
+	for sourceType, table := range map[string]string{
+		"answer":     "answers",
+		"creativity": "creativity_entries",
+	} {
+		_, err := tx.Exec(fmt.Sprintf(`
+		CREATE TRIGGER unlink_%[1]s AFTER DELETE ON %[1]s
+		BEGIN
+			DELETE FROM entry_links WHERE source_type = '%[2]s' AND source_id = OLD.id;
+		END`, table, sourceType))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTemplates lets questions give their answers a structure. Both are
+// stored as JSON, which is empty for questions and answers without one.
+func addTemplates(tx *sql.Tx) error {
+	for _, query := range []string{
+		`ALTER TABLE questions ADD COLUMN template TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE answers ADD COLUMN fields TEXT NOT NULL DEFAULT ''`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Built-in routines have the same UUID in every journal, so they merge when
+// journals are synced
+const (
+	morningRoutineUUID = "9d4e2b61-3c7a-4f08-a5d2-6e1b8c0f7a39"
+	eveningRoutineUUID = "5a8c1f07-e2d4-4b96-8f3a-0c7e9d2b6a14"
+)
+
+// addRoutines adds routines, guided steps done once a day, and the days
+// they were done. Morning and evening routines are built in.
+func addRoutines(tx *sql.Tx) error {
+	for _, query := range []string{`
+	CREATE TABLE routines (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		builtin TEXT UNIQUE,
+		name TEXT NOT NULL,
+		steps TEXT NOT NULL DEFAULT '[]',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`, `
+	CREATE TABLE routine_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		routine_id INTEGER NOT NULL,
+		run_date TEXT NOT NULL,
+		started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		completed_at TIMESTAMP,
+		FOREIGN KEY (routine_id) REFERENCES routines(id)
+	)`, `
+	CREATE TABLE routine_run_steps (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		run_id INTEGER NOT NULL,
+		step_key TEXT NOT NULL,
+		step_type TEXT NOT NULL,
+		entry_uuid TEXT NOT NULL DEFAULT '',
+		note TEXT NOT NULL DEFAULT '',
+		done_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (run_id) REFERENCES routine_runs(id)
+	)`,
+		`CREATE INDEX idx_routine_runs_routine_date ON routine_runs(routine_id, run_date)`,
+		`CREATE INDEX idx_routine_run_steps_run ON routine_run_steps(run_id)`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	builtins := []struct {
+		uuid, builtin, name, steps string
+	}{
+		{morningRoutineUUID, "morning", "Morning intention", `[
+			{"key": "affirmation", "type": "affirmation", "label": "Read your affirmation"},
+			{"key": "intention", "type": "intention", "label": "Set an intention for the day"}]`},
+		{eveningRoutineUUID, "evening", "Evening review", `[
+			{"key": "question", "type": "answer", "label": "Answer a question"},
+			{"key": "gratitude", "type": "gratitude", "label": "Note what you're grateful for"},
+			{"key": "review", "type": "review", "label": "Look back at this morning's intention"}]`},
+	}
+	for _, b := range builtins {
+		_, err := tx.Exec(`INSERT INTO routines (uuid, builtin, name, steps) VALUES (?, ?, ?, ?)`, b.uuid, b.builtin, b.name, b.steps)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"routines", "routine_runs", "routine_run_steps"} {
+		if _, err := tx.Exec(`CREATE UNIQUE INDEX idx_` + table + `_uuid ON ` + table + `(uuid)`); err != nil {
+			return err
+		}
+		if err := trackChanges(tx, table); err != nil {
+			return err
+		}
+	}
+
+	// Every journal starts with the same built-in routines, so they aren't
+	// changes to send to other devices until they are edited
+	_, err := tx.Exec(`
+	UPDATE sync_records SET modified = 0, device = ''
+	WHERE table_name = 'routines'`)
+	return err
+}
+
+// addQuestionSkips records the questions the user passed over, and why, so
+// they can be asked less often
+func addQuestionSkips(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE question_skips (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uuid TEXT,
+		question_id INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		skipped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (question_id) REFERENCES questions(id)
+	)`)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`CREATE INDEX idx_question_skips_question ON question_skips(question_id)`,
+		`CREATE UNIQUE INDEX idx_question_skips_uuid ON question_skips(uuid)`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return trackChanges(tx, "question_skips")
+}
+
+// addQuestionSnoozes keeps skipped and snoozed questions out of selection
+// until a date. Skips recorded before have no date and aren't excluded.
+func addQuestionSnoozes(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE question_skips ADD COLUMN excluded_until TIMESTAMP`)
+	return err
+}
+
+// requireUUIDs replaces the triggers that gave rows inserted without a UUID
+// a random one with triggers that reject them, now that the models generate
+// UUIDs that sort by time
+func requireUUIDs(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT tbl_name FROM sqlite_master WHERE type = 'trigger' AND name = tbl_name || '_uuid'`)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if _, err := tx.Exec(`DROP TRIGGER ` + table + `_uuid`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(requireUUID(table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSyncAliases records the UUIDs of records merged into another record
+// when synced, such as the same question added on two devices, so changes
+// still sent under the merged UUID reach the record it became
+func addSyncAliases(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE sync_aliases (
+		table_name TEXT NOT NULL,
+		uuid TEXT NOT NULL,
+		target TEXT NOT NULL,
+		PRIMARY KEY (table_name, uuid)
+	)`)
+	return err
+}
+
+// addOriginSeqs keeps, for changes made on other devices, their position in
+// the change log of the device that made them, so a change passed on by
+// several devices can be recognised. Local changes use their own seq.
+func addOriginSeqs(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE sync_records ADD COLUMN origin_seq INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// addDraftFields lets drafts of structured answers keep their field values
+func addDraftFields(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE answer_drafts ADD COLUMN fields TEXT NOT NULL DEFAULT ''`)
+	return err
+}
@@ -0,0 +1,148 @@
+// backend/database/db_test.go
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"myproject/backend/ids"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	// Build a database at the version before writing stats existed
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	latest := migrations
+	migrations = latest[:3]
+	err = migrate(db)
+	migrations = latest
+	if err != nil {
+		t.Fatalf("Failed to migrate to version 3: %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO questions (content) VALUES ('How was today?')`)
+	if err != nil {
+		t.Fatalf("Failed to insert question: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO answers (question_id, content) VALUES (1, 'Busy but good')`)
+	if err != nil {
+		t.Fatalf("Failed to insert answer: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO creativity_entries (content, entry_date) VALUES ('Sketched a fox', '2024-01-01')`)
+	if err != nil {
+		t.Fatalf("Failed to insert creativity entry: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO gratitude_items (content, entry_date) VALUES ('A wonderful friend', '2024-01-01')`)
+	if err != nil {
+		t.Fatalf("Failed to insert gratitude item: %v", err)
+	}
+	db.Close()
+
+	// Test that opening upgrades the schema and backfills counts
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	version, err := SchemaVersion(db)
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+
+	if version != LatestSchemaVersion() {
+		t.Errorf("Expected schema version %d, got %d", LatestSchemaVersion(), version)
+	}
+
+	var words, chars int
+	db.QueryRow(`SELECT word_count, char_count FROM answers`).Scan(&words, &chars)
+	if words != 3 || chars != 13 {
+		t.Errorf("Expected answer backfilled with 3 words and 13 characters, got %d and %d", words, chars)
+	}
+
+	db.QueryRow(`SELECT word_count FROM creativity_entries`).Scan(&words)
+	if words != 3 {
+		t.Errorf("Expected creativity entry backfilled with 3 words, got %d", words)
+	}
+
+	var score float64
+	db.QueryRow(`SELECT sentiment FROM gratitude_items`).Scan(&score)
+	if score <= 0 {
+		t.Errorf("Expected gratitude item backfilled with a positive sentiment, got %v", score)
+	}
+
+	// Test that existing rows get UUIDs and change records
+	var questionUUID string
+	var created time.Time
+	db.QueryRow(`SELECT uuid, created_at FROM questions`).Scan(&questionUUID, &created)
+	parsed, err := uuid.Parse(questionUUID)
+	if err != nil {
+		t.Fatalf("Failed to parse question UUID: %v", err)
+	}
+	sec, _ := parsed.Time().UnixTime()
+	if parsed.Version() != 7 || sec != created.Unix() {
+		t.Errorf("Expected a version 7 UUID from when the question was created, got %q", questionUUID)
+	}
+
+	// Built-in records are the same in every journal, so they aren't
+	// recorded as changes made on this device
+	var records, lastSeq int64
+	db.QueryRow(`SELECT COUNT(*) FROM sync_records WHERE device != ''`).Scan(&records)
+	db.QueryRow(`SELECT MAX(seq) FROM sync_records`).Scan(&lastSeq)
+	if records != 4 {
+		t.Errorf("Expected 4 sync records, got %d", records)
+	}
+
+	var prompts int
+	db.QueryRow(`SELECT COUNT(*) FROM creativity_prompts WHERE builtin = 1`).Scan(&prompts)
+	if prompts == 0 {
+		t.Error("Expected the built-in creativity prompts")
+	}
+
+	var habit string
+	db.QueryRow(`SELECT name FROM habits WHERE builtin = 'affirmation'`).Scan(&habit)
+	if habit != "Affirmation" {
+		t.Errorf("Expected the built-in affirmation habit, got %q", habit)
+	}
+
+	// Test that triggers track new rows, updates and deletes
+	if _, err := db.Exec(`INSERT INTO affirmations (content) VALUES ('I am calm')`); err == nil {
+		t.Error("Expected a row without a UUID to be rejected")
+	}
+	var random int
+	db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = tbl_name || '_uuid' AND sql LIKE '%randomblob%'`).Scan(&random)
+	if random != 0 {
+		t.Errorf("Expected every table to reject rows without a UUID, got %d still given a random one", random)
+	}
+	_, err = db.Exec(`INSERT INTO affirmations (uuid, content) VALUES (?, 'I am calm')`, ids.New())
+	if err != nil {
+		t.Fatalf("Failed to insert affirmation: %v", err)
+	}
+	var affirmationUUID string
+	var seq int64
+	db.QueryRow(`SELECT uuid FROM affirmations`).Scan(&affirmationUUID)
+	db.QueryRow(`SELECT seq FROM sync_records WHERE uuid = ?`, affirmationUUID).Scan(&seq)
+	if len(affirmationUUID) != 36 || seq != lastSeq+1 {
+		t.Errorf("Expected new affirmation to get a UUID and seq %d, got %q and %d", lastSeq+1, affirmationUUID, seq)
+	}
+
+	if _, err := db.Exec(`DELETE FROM affirmations`); err != nil {
+		t.Fatalf("Failed to delete affirmation: %v", err)
+	}
+	var deleted bool
+	db.QueryRow(`SELECT seq, deleted FROM sync_records WHERE uuid = ?`, affirmationUUID).Scan(&seq, &deleted)
+	if !deleted || seq != lastSeq+2 {
+		t.Errorf("Expected deleted affirmation recorded with seq %d, got %v and %d", lastSeq+2, deleted, seq)
+	}
+}
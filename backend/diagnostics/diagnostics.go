@@ -0,0 +1,167 @@
+// backend/diagnostics/diagnostics.go
+package diagnostics
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"myproject/backend/database"
+)
+
+// Report describes the state of an installation without including any journal content
+type Report struct {
+	GeneratedAt         time.Time      `json:"generatedAt"`
+	GoVersion           string         `json:"goVersion"`
+	OS                  string         `json:"os"`
+	Arch                string         `json:"arch"`
+	DatabaseOpen        bool           `json:"databaseOpen"`
+	SchemaVersion       int            `json:"schemaVersion"`
+	LatestSchemaVersion int            `json:"latestSchemaVersion"`
+	IntegrityCheck      []string       `json:"integrityCheck"`
+	RowCounts           map[string]int `json:"rowCounts"`
+	StartupError        string         `json:"startupError,omitempty"`
+	Errors              []string       `json:"errors,omitempty"` // Problems hit while collecting the report
+}
+
+// Collect gathers the schema version, row counts and integrity check from db.
+// db may be nil when the database failed to open.
+func Collect(db *sql.DB) *Report {
+	report := &Report{
+		GeneratedAt:         time.Now(),
+		GoVersion:           runtime.Version(),
+		OS:                  runtime.GOOS,
+		Arch:                runtime.GOARCH,
+		LatestSchemaVersion: database.LatestSchemaVersion(),
+		RowCounts:           map[string]int{},
+	}
+	if db == nil {
+		return report
+	}
+	report.DatabaseOpen = true
+
+	fail := func(err error) {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	version, err := database.SchemaVersion(db)
+	if err != nil {
+		fail(err)
+	}
+	report.SchemaVersion = version
+
+	rows, err := db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		fail(err)
+	} else {
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				fail(err)
+				break
+			}
+			report.IntegrityCheck = append(report.IntegrityCheck, line)
+		}
+		rows.Close()
+	}
+
+	tables, err := tableNames(db)
+	if err != nil {
+		fail(err)
+	}
+	for _, table := range tables {
+		var count int
+		// Table names come from sqlite_master, not user input
+		err := db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, table)).Scan(&count)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		report.RowCounts[table] = count
+	}
+
+	return report
+}
+
+func tableNames(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT name
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// WriteBundle writes a zip containing the report as diagnostics.json and
+// every file in logDir under logs/
+func WriteBundle(path string, report *Report, logDir string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	w, err := zw.Create("diagnostics.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+
+	logs, err := os.ReadDir(logDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range logs {
+		if entry.IsDir() {
+			continue
+		}
+		if err := addFile(zw, filepath.Join(logDir, entry.Name()), "logs/"+entry.Name()); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func addFile(zw *zip.Writer, path string, name string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
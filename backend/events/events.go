@@ -0,0 +1,132 @@
+// backend/events/events.go
+package events
+
+import "sync"
+
+// Event types published when data changes
+const (
+	QuestionCreated = "question.created"
+	QuestionUpdated = "question.updated"
+	QuestionDeleted = "question.deleted"
+	QuestionSkipped = "question.skipped"
+
+	// Question packs add and remove many questions at once, so they publish
+	// one event for the pack instead of one per question
+	QuestionPackInstalled   = "question_pack.installed"
+	QuestionPackUninstalled = "question_pack.uninstalled"
+
+	AnswerCreated = "answer.created"
+	AnswerUpdated = "answer.updated"
+	AnswerDeleted = "answer.deleted"
+
+	DraftSaved   = "draft.saved"
+	DraftDeleted = "draft.deleted" // Also published when a draft is committed as an answer
+
+	AffirmationCreated    = "affirmation.created"
+	AffirmationUpdated    = "affirmation.updated"
+	AffirmationDeleted    = "affirmation.deleted"
+	AffirmationLogged     = "affirmation.logged"
+	AffirmationLogDeleted = "affirmation_log.deleted"
+
+	GratitudeCreated = "gratitude.created"
+	GratitudeUpdated = "gratitude.updated"
+	GratitudeDeleted = "gratitude.deleted"
+
+	CreativityCreated = "creativity.created"
+	CreativityUpdated = "creativity.updated"
+	CreativityDeleted = "creativity.deleted"
+
+	PromptCreated = "creativity_prompt.created"
+	PromptUpdated = "creativity_prompt.updated" // Also published when a prompt is archived or restored
+	PromptDeleted = "creativity_prompt.deleted"
+
+	HabitCreated    = "habit.created"
+	HabitUpdated    = "habit.updated" // Also published when a habit is archived or restored
+	HabitDeleted    = "habit.deleted"
+	HabitLogged     = "habit.logged" // ID is the habit's, not the check-in's
+	HabitLogDeleted = "habit_log.deleted"
+
+	GoalCreated   = "goal.created"
+	GoalUpdated   = "goal.updated" // Also published for milestone and link changes
+	GoalDeleted   = "goal.deleted"
+	GoalCheckedIn = "goal.checked_in"
+
+	RoutineCreated = "routine.created"
+	RoutineUpdated = "routine.updated"
+	RoutineDeleted = "routine.deleted"
+	// RoutineProgressed is published when a routine is started or a step
+	// is done. ID is the routine's, and Date the day of the run.
+	RoutineProgressed = "routine.progressed"
+
+	// TagsChanged is published when a tag is renamed or merged, which
+	// rewrites the entries using it. Key is the new name.
+	TagsChanged = "tags.changed"
+
+	// Key is the type of entry the attachment belongs to
+	AttachmentAdded   = "attachment.added"
+	AttachmentDeleted = "attachment.deleted"
+
+	// StreakChanged is published whenever the data behind a streak changes.
+	// Kind names the streak: "affirmation", "gratitude", "creativity" or
+	// "habit", with the habit's ID.
+	StreakChanged = "streak.changed"
+)
+
+// Event describes a change to the journal's data
+type Event struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id,omitempty"`   // ID of the changed record, if any
+	Key  string `json:"key,omitempty"`  // Text key of the changed record, such as a pack ID
+	Date string `json:"date,omitempty"` // Entry date (YYYY-MM-DD) the change belongs to, if known
+	Kind string `json:"kind,omitempty"` // Streak kind for streak.changed
+}
+
+// Handler receives published events
+type Handler func(Event)
+
+// Bus delivers published events to its subscribers
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]Handler
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{handlers: map[int]Handler{}}
+}
+
+// Subscribe registers a handler and returns a function that removes it
+func (b *Bus) Subscribe(h Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish delivers an event to every subscriber synchronously.
+// Publishing on a nil bus does nothing.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
@@ -0,0 +1,56 @@
+// backend/events/events_test.go
+package events
+
+import "testing"
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+
+	var first, second []Event
+	unsubscribeFirst := bus.Subscribe(func(e Event) { first = append(first, e) })
+	bus.Subscribe(func(e Event) { second = append(second, e) })
+
+	// Test delivery to every subscriber
+	t.Run("Publish", func(t *testing.T) {
+		bus.Publish(Event{Type: AnswerCreated, ID: 1, Date: "2025-03-18"})
+
+		if len(first) != 1 || len(second) != 1 {
+			t.Fatalf("Expected both subscribers to receive the event, got %d and %d", len(first), len(second))
+		}
+
+		if first[0].Type != AnswerCreated || first[0].ID != 1 {
+			t.Errorf("Unexpected event %+v", first[0])
+		}
+	})
+
+	// Test removing a subscriber
+	t.Run("Unsubscribe", func(t *testing.T) {
+		unsubscribeFirst()
+		bus.Publish(Event{Type: StreakChanged, Kind: "gratitude"})
+
+		if len(first) != 1 {
+			t.Errorf("Expected unsubscribed handler to receive nothing, got %d events", len(first))
+		}
+
+		if len(second) != 2 {
+			t.Errorf("Expected 2 events, got %d", len(second))
+		}
+	})
+
+	// Test that a handler may unsubscribe while handling an event
+	t.Run("UnsubscribeDuringPublish", func(t *testing.T) {
+		var unsubscribe func()
+		calls := 0
+		unsubscribe = bus.Subscribe(func(e Event) {
+			calls++
+			unsubscribe()
+		})
+
+		bus.Publish(Event{Type: GratitudeDeleted, ID: 2})
+		bus.Publish(Event{Type: GratitudeDeleted, ID: 3})
+
+		if calls != 1 {
+			t.Errorf("Expected 1 call, got %d", calls)
+		}
+	})
+}
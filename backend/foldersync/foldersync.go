@@ -0,0 +1,451 @@
+// backend/foldersync/foldersync.go
+package foldersync
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"myproject/backend/models"
+	"myproject/backend/replication"
+)
+
+// Settings keys used to remember progress between runs
+const (
+	FolderSetting    = "sync.folder"
+	exportedSetting  = "sync.folder.exported"
+	positionsSetting = "sync.folder.positions"
+	originsSetting   = "sync.folder.origins"
+)
+
+// maxSegmentSize is the size at which a device starts a new log file
+const maxSegmentSize = 1 << 20
+
+// stateFile is written by each device next to its log files
+const stateFile = "state.json"
+
+// Result reports what a folder sync did
+type Result struct {
+	Exported  int                    `json:"exported"` // Changes written to this device's log
+	Imported  int                    `json:"imported"` // Other devices' changes applied here
+	Devices   int                    `json:"devices"`  // Other devices found in the folder
+	Conflicts []replication.Conflict `json:"conflicts"`
+	At        time.Time              `json:"at"`
+}
+
+// state tells other devices how far this device has read their logs, so
+// they can tell which of their changes it hadn't seen
+type state struct {
+	DeviceID string           `json:"deviceId"`
+	Name     string           `json:"name"`
+	Seen     map[string]int64 `json:"seen"` // Last change applied from each device
+}
+
+// position is how far another device's log has been read
+type position struct {
+	File   string `json:"file"`
+	Offset int64  `json:"offset"`
+	Seq    int64  `json:"seq"` // Last change read, in the other device's numbering
+}
+
+// Syncer replicates a journal through a shared folder. Each device appends
+// its own changes to JSON-lines files in a folder named after its device
+// ID, and replays the files of the other devices. No file is written by
+// more than one device, so the folder can be synced by any file sync tool.
+type Syncer struct {
+	db       *sql.DB
+	name     string
+	settings models.SettingsStore
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a folder syncer for db. name is recorded for other devices.
+func New(db *sql.DB, name string) *Syncer {
+	return &Syncer{db: db, name: name, settings: models.NewSettingsStore(db)}
+}
+
+// Folder returns the configured sync folder, or "" when folder sync is off
+func (s *Syncer) Folder() (string, error) {
+	dir, _, err := s.settings.Get(FolderSetting)
+	return dir, err
+}
+
+// SetFolder configures the sync folder. An empty dir turns folder sync off.
+// Changing the folder starts over: the whole journal is written to the new
+// folder and every log in it is replayed.
+func (s *Syncer) SetFolder(dir string) error {
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a folder", dir)
+		}
+	}
+
+	if err := s.settings.Set(FolderSetting, dir); err != nil {
+		return err
+	}
+	if err := s.settings.Set(exportedSetting, "0"); err != nil {
+		return err
+	}
+	if err := s.settings.SetJSON(originsSetting, map[string]int64{}); err != nil {
+		return err
+	}
+	return s.settings.SetJSON(positionsSetting, map[string]position{})
+}
+
+// Sync writes local changes to the folder, then applies the changes other
+// devices have written since the last sync
+func (s *Syncer) Sync() (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir, err := s.Folder()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("no sync folder is configured")
+	}
+	deviceID, err := replication.DeviceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Conflicts: []replication.Conflict{}}
+	if result.Exported, err = s.export(dir, deviceID); err != nil {
+		return nil, fmt.Errorf("writing changes: %w", err)
+	}
+	if err := s.replay(dir, deviceID, result); err != nil {
+		return nil, fmt.Errorf("reading changes: %w", err)
+	}
+	result.At = time.Now()
+	return result, nil
+}
+
+// export appends the changes since the last export that the folder doesn't
+// have yet. Besides this device's own changes, that includes changes from
+// devices that don't use the folder, received through LAN sync.
+func (s *Syncer) export(dir string, deviceID string) (int, error) {
+	var exported int64
+	if _, err := s.settings.GetJSON(exportedSetting, &exported); err != nil {
+		return 0, err
+	}
+	origins := map[string]int64{}
+	if _, err := s.settings.GetJSON(originsSetting, &origins); err != nil {
+		return 0, err
+	}
+
+	latest, err := replication.LatestSeq(s.db)
+	if err != nil {
+		return 0, err
+	}
+	all, err := replication.Changes(s.db, exported, "")
+	if err != nil {
+		return 0, err
+	}
+
+	// Changes read from the folder, or already written to it, are left out
+	var changes []replication.Change
+	for _, c := range all {
+		if c.Origin > origins[c.Device] {
+			changes = append(changes, c)
+		}
+	}
+	for _, c := range changes {
+		origins[c.Device] = max(origins[c.Device], c.Origin)
+	}
+
+	if len(changes) > 0 {
+		var buf bytes.Buffer
+		for _, c := range changes {
+			line, err := json.Marshal(c)
+			if err != nil {
+				return 0, err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		if err := appendLog(filepath.Join(dir, deviceID), buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.settings.SetJSON(exportedSetting, latest); err != nil {
+		return 0, err
+	}
+	if err := s.settings.SetJSON(originsSetting, origins); err != nil {
+		return 0, err
+	}
+	return len(changes), nil
+}
+
+// appendLog appends complete lines to the newest log file in deviceDir,
+// starting a new file once it has grown past maxSegmentSize
+func appendLog(deviceDir string, lines []byte) error {
+	if err := os.MkdirAll(deviceDir, 0755); err != nil {
+		return err
+	}
+
+	segments, err := logFiles(deviceDir)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%08d.jsonl", 1)
+	if n := len(segments); n > 0 {
+		name = segments[n-1]
+		info, err := os.Stat(filepath.Join(deviceDir, name))
+		if err != nil {
+			return err
+		}
+		if info.Size() >= maxSegmentSize {
+			var last int
+			fmt.Sscanf(name, "%08d.jsonl", &last)
+			name = fmt.Sprintf("%08d.jsonl", last+1)
+		}
+	}
+
+	f, err := os.OpenFile(filepath.Join(deviceDir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(lines); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// logFiles lists the log files in a device folder, oldest first
+func logFiles(deviceDir string) ([]string, error) {
+	entries, err := os.ReadDir(deviceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// replay applies the changes in other devices' logs that haven't been read yet
+func (s *Syncer) replay(dir string, deviceID string, result *Result) error {
+	positions := map[string]position{}
+	if _, err := s.settings.GetJSON(positionsSetting, &positions); err != nil {
+		return err
+	}
+	origins := map[string]int64{}
+	if _, err := s.settings.GetJSON(originsSetting, &origins); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		other := e.Name()
+		if !e.IsDir() || other == deviceID {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, other, stateFile)); err != nil {
+			// Not a device folder, or one still being copied
+			continue
+		}
+		result.Devices++
+
+		changes, pos, err := readLog(filepath.Join(dir, other), positions[other])
+		if err != nil {
+			return fmt.Errorf("device %s: %w", other, err)
+		}
+
+		// Local changes the other device had read when it last synced
+		// aren't conflicts. Exported changes keep their local seq, so what
+		// it reports having seen is in the local numbering.
+		var theirs state
+		if data, err := os.ReadFile(filepath.Join(dir, other, stateFile)); err == nil {
+			json.Unmarshal(data, &theirs)
+		}
+
+		applied, err := replication.Apply(s.db, changes, theirs.Seen[deviceID])
+		if err != nil {
+			return fmt.Errorf("device %s: %w", other, err)
+		}
+		result.Imported += applied.Applied
+		result.Conflicts = append(result.Conflicts, applied.Conflicts...)
+		positions[other] = pos
+
+		// The folder has these changes, so they aren't written back to it
+		for _, c := range changes {
+			origins[c.Device] = max(origins[c.Device], c.Origin)
+		}
+	}
+
+	if err := s.settings.SetJSON(positionsSetting, positions); err != nil {
+		return err
+	}
+	if err := s.settings.SetJSON(originsSetting, origins); err != nil {
+		return err
+	}
+
+	// Tell the other devices how far this one has read
+	own := state{DeviceID: deviceID, Name: s.name, Seen: map[string]int64{}}
+	for other, pos := range positions {
+		own.Seen[other] = pos.Seq
+	}
+	return writeState(filepath.Join(dir, deviceID), own)
+}
+
+// readLog reads the complete lines in a device's log files after pos
+func readLog(deviceDir string, pos position) ([]replication.Change, position, error) {
+	files, err := logFiles(deviceDir)
+	if err != nil {
+		return nil, pos, err
+	}
+
+	var changes []replication.Change
+	for _, name := range files {
+		if name < pos.File {
+			continue
+		}
+		offset := int64(0)
+		if name == pos.File {
+			offset = pos.Offset
+		}
+
+		f, err := os.Open(filepath.Join(deviceDir, name))
+		if err != nil {
+			return nil, pos, err
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, pos, err
+		}
+
+		reader := bufio.NewReader(f)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if errors.Is(err, io.EOF) {
+				// A partial line is still being written or copied, so it
+				// is read again next time
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, pos, err
+			}
+
+			var c replication.Change
+			if err := json.Unmarshal(line, &c); err != nil {
+				f.Close()
+				return nil, pos, fmt.Errorf("%s at offset %d: %w", name, offset, err)
+			}
+			changes = append(changes, c)
+			offset += int64(len(line))
+			pos = position{File: name, Offset: offset, Seq: c.Seq}
+		}
+		f.Close()
+	}
+
+	return changes, pos, nil
+}
+
+// writeState replaces a device's state file without leaving it half written
+func writeState(deviceDir string, st state) error {
+	if err := os.MkdirAll(deviceDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(deviceDir, stateFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(deviceDir, stateFile))
+}
+
+// Start syncs now and then on the given interval until Stop is called
+func (s *Syncer) Start(interval time.Duration, onResult func(*Result), onError func(error)) {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	run := func() {
+		result, err := s.Sync()
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+
+	go func() {
+		defer close(done)
+
+		run()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+}
+
+// Stop stops syncing in the background and waits for a running sync to finish
+func (s *Syncer) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
@@ -0,0 +1,253 @@
+// backend/foldersync/foldersync_test.go
+package foldersync
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"myproject/backend/database"
+	"myproject/backend/models"
+	"myproject/backend/replication"
+)
+
+// device is one installation of the app with its own copy of the sync folder
+type device struct {
+	db     *sql.DB
+	stores *models.Stores
+	syncer *Syncer
+	dir    string
+	id     string
+}
+
+func newDevice(t *testing.T, name string) *device {
+	t.Helper()
+
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	d := &device{db: db, stores: models.NewStores(db, nil), syncer: New(db, name), dir: t.TempDir()}
+	if err := d.syncer.SetFolder(d.dir); err != nil {
+		t.Fatalf("Failed to set sync folder: %v", err)
+	}
+	if d.id, err = replication.DeviceID(db); err != nil {
+		t.Fatalf("Failed to get device ID: %v", err)
+	}
+	return d
+}
+
+func (d *device) sync(t *testing.T) *Result {
+	t.Helper()
+
+	result, err := d.syncer.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+	return result
+}
+
+// mirror copies the files in one folder to another, as a file sync tool would
+func mirror(t *testing.T, from string, to string) {
+	t.Helper()
+
+	err := filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(from, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		t.Fatalf("Failed to mirror folders: %v", err)
+	}
+}
+
+// snapshot describes every synced record, for comparing two journals
+func snapshot(t *testing.T, d *device) string {
+	t.Helper()
+
+	changes, err := replication.Changes(d.db, 0, "")
+	if err != nil {
+		t.Fatalf("Failed to read changes: %v", err)
+	}
+
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		lines = append(lines, fmt.Sprintf("%s %s %d %s %v %v", c.Table, c.UUID, c.Modified, c.Device, c.Deleted, c.Data))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func TestFolderSync(t *testing.T) {
+	t.Parallel()
+
+	// Test that changes travel through the folder in both directions
+	t.Run("Replicate", func(t *testing.T) {
+		laptop, desktop := newDevice(t, "Laptop"), newDevice(t, "Desktop")
+
+		question, err := laptop.stores.Questions.Add("What did you learn?")
+		if err != nil {
+			t.Fatalf("Failed to add question: %v", err)
+		}
+		if _, err := laptop.stores.Answers.Create(question.ID, "How to bake bread"); err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+
+		result := laptop.sync(t)
+		if result.Exported != 2 || result.Devices != 0 {
+			t.Errorf("Expected 2 changes exported and no other devices, got %+v", result)
+		}
+
+		mirror(t, laptop.dir, desktop.dir)
+		result = desktop.sync(t)
+		if result.Imported != 2 || result.Devices != 1 {
+			t.Errorf("Expected 2 changes imported from 1 device, got %+v", result)
+		}
+
+		if _, err := desktop.stores.Gratitude.Add("Fresh bread"); err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+		desktop.sync(t)
+		mirror(t, desktop.dir, laptop.dir)
+		result = laptop.sync(t)
+		if result.Imported != 1 {
+			t.Errorf("Expected 1 change imported, got %+v", result)
+		}
+
+		if snapshot(t, laptop) != snapshot(t, desktop) {
+			t.Fatal("Expected journals to match after syncing")
+		}
+
+		// Replaying again reads nothing new
+		result = laptop.sync(t)
+		if result.Exported != 0 || result.Imported != 0 {
+			t.Errorf("Expected nothing to sync, got %+v", result)
+		}
+	})
+
+	// Test that a record edited on both devices converges on the later edit
+	t.Run("Conflict", func(t *testing.T) {
+		laptop, desktop := newDevice(t, "Laptop"), newDevice(t, "Desktop")
+
+		item, err := laptop.stores.Gratitude.Add("Rain")
+		if err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+		laptop.sync(t)
+		mirror(t, laptop.dir, desktop.dir)
+		desktop.sync(t)
+		mirror(t, desktop.dir, laptop.dir)
+		laptop.sync(t)
+
+		items, _ := desktop.stores.Gratitude.GetToday()
+		if len(items) != 1 {
+			t.Fatalf("Expected 1 synced gratitude item, got %d", len(items))
+		}
+
+		if err := laptop.stores.Gratitude.Update(item.ID, "Rain on the roof"); err != nil {
+			t.Fatalf("Failed to update gratitude item: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if err := desktop.stores.Gratitude.Update(items[0].ID, "Rain at night"); err != nil {
+			t.Fatalf("Failed to update gratitude item: %v", err)
+		}
+
+		laptop.sync(t)
+		desktop.sync(t)
+		mirror(t, laptop.dir, desktop.dir)
+		mirror(t, desktop.dir, laptop.dir)
+
+		conflicts := len(laptop.sync(t).Conflicts) + len(desktop.sync(t).Conflicts)
+		if conflicts == 0 {
+			t.Error("Expected the conflicting edits to be reported")
+		}
+
+		if snapshot(t, laptop) != snapshot(t, desktop) {
+			t.Fatal("Expected journals to match after syncing")
+		}
+		items, _ = laptop.stores.Gratitude.GetToday()
+		if len(items) != 1 || items[0].Content != "Rain at night" {
+			t.Errorf("Expected the later edit to win, got %+v", items)
+		}
+	})
+
+	// Test that changes from a device that doesn't use the folder are passed
+	// on by a device that does, and not written back once read
+	t.Run("Relay", func(t *testing.T) {
+		laptop, desktop := newDevice(t, "Laptop"), newDevice(t, "Desktop")
+		phone := newDevice(t, "Phone")
+
+		if _, err := phone.stores.Gratitude.Add("A quiet train"); err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+		changes, err := replication.Changes(phone.db, 0, "")
+		if err != nil {
+			t.Fatalf("Failed to read changes: %v", err)
+		}
+		if _, err := replication.Apply(laptop.db, changes, 0); err != nil {
+			t.Fatalf("Failed to apply changes: %v", err)
+		}
+
+		if result := laptop.sync(t); result.Exported != 1 {
+			t.Errorf("Expected the phone's change exported, got %+v", result)
+		}
+		mirror(t, laptop.dir, desktop.dir)
+		if result := desktop.sync(t); result.Imported != 1 {
+			t.Errorf("Expected the phone's change imported, got %+v", result)
+		}
+		if result := desktop.sync(t); result.Exported != 0 {
+			t.Errorf("Expected a change read from the folder not written back, got %+v", result)
+		}
+		if snapshot(t, laptop) != snapshot(t, desktop) {
+			t.Fatal("Expected journals to match after syncing")
+		}
+	})
+
+	// Test that a line still being copied is read once it is complete
+	t.Run("PartialLine", func(t *testing.T) {
+		laptop, desktop := newDevice(t, "Laptop"), newDevice(t, "Desktop")
+
+		if _, err := laptop.stores.Affirmations.Save("I am patient"); err != nil {
+			t.Fatalf("Failed to save affirmation: %v", err)
+		}
+		laptop.sync(t)
+
+		logPath := filepath.Join(laptop.dir, laptop.id, "00000001.jsonl")
+		data, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("Failed to read log: %v", err)
+		}
+
+		mirror(t, laptop.dir, desktop.dir)
+		copied := filepath.Join(desktop.dir, laptop.id, "00000001.jsonl")
+		if err := os.WriteFile(copied, data[:len(data)/2], 0644); err != nil {
+			t.Fatalf("Failed to truncate log: %v", err)
+		}
+
+		if result := desktop.sync(t); result.Imported != 0 {
+			t.Errorf("Expected a partial line to be skipped, got %+v", result)
+		}
+
+		mirror(t, laptop.dir, desktop.dir)
+		if result := desktop.sync(t); result.Imported != 1 {
+			t.Errorf("Expected the completed line to be imported, got %+v", result)
+		}
+	})
+}
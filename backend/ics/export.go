@@ -0,0 +1,130 @@
+// backend/ics/export.go
+package ics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"myproject/backend/models"
+)
+
+// Activity types that can be exported
+const (
+	ActivityAnswers    = "answers"
+	ActivityGratitude  = "gratitude"
+	ActivityCreativity = "creativity"
+)
+
+// ExportOptions filter and shape a journaling calendar export
+type ExportOptions struct {
+	Types          []string `json:"types"`          // Activity types to include, all when empty
+	From           string   `json:"from"`           // YYYY-MM-DD, open when empty
+	To             string   `json:"to"`             // YYYY-MM-DD, open when empty
+	IncludeContent bool     `json:"includeContent"` // Put entry text in event descriptions
+}
+
+// Validate checks the activity types and date range
+func (o ExportOptions) Validate() error {
+	for _, t := range o.Types {
+		if t != ActivityAnswers && t != ActivityGratitude && t != ActivityCreativity {
+			return fmt.Errorf("unknown activity type %q", t)
+		}
+	}
+	for _, d := range []string{o.From, o.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return fmt.Errorf("invalid date %q", d)
+		}
+	}
+	if o.From != "" && o.To != "" && o.From > o.To {
+		return fmt.Errorf("start date %s is after end date %s", o.From, o.To)
+	}
+	return nil
+}
+
+func (o ExportOptions) includes(activity string) bool {
+	if len(o.Types) == 0 {
+		return true
+	}
+	for _, t := range o.Types {
+		if t == activity {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildEvents turns daily journaling activity into one all-day event per activity type per day
+func BuildEvents(days []models.DayActivity, opts ExportOptions) ([]Event, error) {
+	var events []Event
+
+	for _, day := range days {
+		date, err := time.ParseInLocation("2006-01-02", day.Date, time.Local)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.includes(ActivityAnswers) && len(day.Answers) > 0 {
+			e := Event{
+				UID:        day.Date + "-answers@daily-reflection",
+				Date:       date,
+				Summary:    plural(len(day.Answers), "Journal answer", "Journal answers"),
+				Categories: []string{"Journal"},
+			}
+			if opts.IncludeContent {
+				parts := make([]string, len(day.Answers))
+				for i, a := range day.Answers {
+					parts[i] = a.Content
+				}
+				e.Description = strings.Join(parts, "\n\n")
+			}
+			events = append(events, e)
+		}
+
+		if opts.includes(ActivityGratitude) && len(day.Gratitude) > 0 {
+			e := Event{
+				UID:        day.Date + "-gratitude@daily-reflection",
+				Date:       date,
+				Summary:    plural(len(day.Gratitude), "Gratitude item", "Gratitude items"),
+				Categories: []string{"Gratitude"},
+			}
+			if opts.IncludeContent {
+				parts := make([]string, len(day.Gratitude))
+				for i, item := range day.Gratitude {
+					parts[i] = "- " + item.Content
+				}
+				e.Description = strings.Join(parts, "\n")
+			}
+			events = append(events, e)
+		}
+
+		if opts.includes(ActivityCreativity) && len(day.Creativity) > 0 {
+			e := Event{
+				UID:        day.Date + "-creativity@daily-reflection",
+				Date:       date,
+				Summary:    "Creativity journal",
+				Categories: []string{"Creativity"},
+			}
+			if opts.IncludeContent {
+				parts := make([]string, len(day.Creativity))
+				for i, c := range day.Creativity {
+					parts[i] = c.Content
+				}
+				e.Description = strings.Join(parts, "\n\n")
+			}
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+func plural(n int, singular string, pluralForm string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
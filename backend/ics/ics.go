@@ -0,0 +1,90 @@
+// backend/ics/ics.go
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is an all-day calendar event
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	Categories  []string
+}
+
+// Write writes the events as an iCalendar (RFC 5545) document
+func Write(w io.Writer, events []Event, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//Daily Reflection//Journaling Desktop//EN")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "X-WR-CALNAME:Daily Reflection")
+
+	for _, e := range events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escape(e.UID))
+		writeLine(bw, "DTSTAMP:"+dtstamp)
+		writeLine(bw, "DTSTART;VALUE=DATE:"+e.Date.Format("20060102"))
+		writeLine(bw, "DTEND;VALUE=DATE:"+e.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(bw, "SUMMARY:"+escape(e.Summary))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escape(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			escaped := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				escaped[i] = escape(c)
+			}
+			writeLine(bw, "CATEGORIES:"+strings.Join(escaped, ","))
+		}
+		writeLine(bw, "TRANSP:TRANSPARENT")
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escape escapes text values as required by RFC 5545
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// writeLine writes a content line folded to 75 octets, without splitting UTF-8 characters
+func writeLine(w *bufio.Writer, line string) {
+	const limit = 75
+
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		width = limit - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
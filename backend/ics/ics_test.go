@@ -0,0 +1,122 @@
+// backend/ics/ics_test.go
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"myproject/backend/models"
+)
+
+func TestICSExport(t *testing.T) {
+	days := []models.DayActivity{
+		{
+			Date:    "2025-03-18",
+			Answers: []models.Answer{{ID: 1, Content: "Learned about; commas, and\nnewlines"}},
+			Gratitude: []models.GratitudeItem{
+				{ID: 1, Content: "Coffee", EntryDate: "2025-03-18"},
+				{ID: 2, Content: "Sunshine", EntryDate: "2025-03-18"},
+			},
+		},
+		{
+			Date:       "2025-03-19",
+			Creativity: []models.CreativityEntry{{ID: 1, Content: strings.Repeat("ä", 60), EntryDate: "2025-03-19"}},
+		},
+	}
+
+	// Test building events for every activity type
+	t.Run("BuildEvents", func(t *testing.T) {
+		events, err := BuildEvents(days, ExportOptions{})
+		if err != nil {
+			t.Fatalf("Failed to build events: %v", err)
+		}
+
+		if len(events) != 3 {
+			t.Fatalf("Expected 3 events, got %d", len(events))
+		}
+
+		if events[1].Summary != "2 Gratitude items" {
+			t.Errorf("Expected '2 Gratitude items', got '%s'", events[1].Summary)
+		}
+
+		// Content is left out unless requested
+		for _, e := range events {
+			if e.Description != "" {
+				t.Errorf("Expected no description, got '%s'", e.Description)
+			}
+		}
+	})
+
+	// Test filtering by activity type
+	t.Run("FilterTypes", func(t *testing.T) {
+		events, err := BuildEvents(days, ExportOptions{Types: []string{ActivityCreativity}, IncludeContent: true})
+		if err != nil {
+			t.Fatalf("Failed to build events: %v", err)
+		}
+
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, got %d", len(events))
+		}
+
+		if events[0].UID != "2025-03-19-creativity@daily-reflection" {
+			t.Errorf("Unexpected UID '%s'", events[0].UID)
+		}
+
+		if events[0].Description == "" {
+			t.Errorf("Expected content in description")
+		}
+	})
+
+	// Test option validation
+	t.Run("Validate", func(t *testing.T) {
+		if err := (ExportOptions{Types: []string{"moods"}}).Validate(); err == nil {
+			t.Errorf("Expected error for unknown activity type")
+		}
+
+		if err := (ExportOptions{From: "2025-04-01", To: "2025-03-01"}).Validate(); err == nil {
+			t.Errorf("Expected error for reversed date range")
+		}
+
+		if err := (ExportOptions{From: "2025-03-01", To: "2025-04-01"}).Validate(); err != nil {
+			t.Errorf("Expected valid options, got %v", err)
+		}
+	})
+
+	// Test the iCalendar output
+	t.Run("Write", func(t *testing.T) {
+		events, _ := BuildEvents(days, ExportOptions{IncludeContent: true})
+
+		var buf bytes.Buffer
+		stamp := time.Date(2025, 3, 20, 8, 0, 0, 0, time.UTC)
+		if err := Write(&buf, events, stamp); err != nil {
+			t.Fatalf("Failed to write calendar: %v", err)
+		}
+		out := buf.String()
+
+		for _, want := range []string{
+			"BEGIN:VCALENDAR\r\n",
+			"DTSTAMP:20250320T080000Z\r\n",
+			"DTSTART;VALUE=DATE:20250318\r\n",
+			"DTEND;VALUE=DATE:20250319\r\n",
+			`DESCRIPTION:Learned about\; commas\, and\nnewlines`,
+			"END:VCALENDAR\r\n",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected output to contain %q", want)
+			}
+		}
+
+		// Lines are folded to 75 octets without splitting characters
+		for _, line := range strings.Split(out, "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("Line longer than 75 octets: %q", line)
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("Line contains split UTF-8 character: %q", line)
+			}
+		}
+	})
+}
@@ -0,0 +1,37 @@
+// backend/ids/ids.go
+package ids
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"myproject/backend/textstats"
+)
+
+// promptSpace is the namespace of the name-based IDs of built-in creativity prompts
+var promptSpace = uuid.MustParse("c4e2a9d8-1b7f-4e63-8d05-92f1a6b3e7c4")
+
+// New returns a new record ID. IDs are UUIDv7, so they sort by the time
+// they were created.
+func New() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// At returns a new record ID that sorts as if it was created at t, for
+// records that existed before they had IDs
+func At(t time.Time) string {
+	id := uuid.Must(uuid.NewV7())
+	ms := uint64(t.UnixMilli())
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (40 - 8*i))
+	}
+	return id.String()
+}
+
+// ForPrompt returns the ID every installation gives a built-in creativity
+// prompt, so built-in prompts merge when journals are synced instead of
+// being duplicated. These name-based IDs don't sort by time.
+func ForPrompt(content string) string {
+	return uuid.NewSHA1(promptSpace, []byte(textstats.Normalize(content))).String()
+}
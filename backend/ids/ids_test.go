@@ -0,0 +1,58 @@
+// backend/ids/ids_test.go
+package ids
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIDs(t *testing.T) {
+	t.Run("New", func(t *testing.T) {
+		first, second := New(), New()
+		if first == second {
+			t.Fatalf("Expected distinct IDs, got %s twice", first)
+		}
+
+		id, err := uuid.Parse(first)
+		if err != nil {
+			t.Fatalf("Failed to parse ID: %v", err)
+		}
+		if id.Version() != 7 {
+			t.Errorf("Expected a version 7 UUID, got version %d", id.Version())
+		}
+
+		// IDs created later sort after earlier ones
+		if second <= first {
+			t.Errorf("Expected %s to sort after %s", second, first)
+		}
+	})
+
+	t.Run("At", func(t *testing.T) {
+		created := time.Date(2023, 3, 14, 9, 26, 53, 0, time.UTC)
+		id, err := uuid.Parse(At(created))
+		if err != nil {
+			t.Fatalf("Failed to parse ID: %v", err)
+		}
+		if id.Version() != 7 {
+			t.Errorf("Expected a version 7 UUID, got version %d", id.Version())
+		}
+		sec, nsec := id.Time().UnixTime()
+		if got := time.Unix(sec, nsec); !got.Equal(created) {
+			t.Errorf("Expected the ID to carry %v, got %v", created, got)
+		}
+		if At(created) >= New() {
+			t.Error("Expected an ID for an earlier time to sort first")
+		}
+	})
+
+	t.Run("ForPrompt", func(t *testing.T) {
+		if ForPrompt("Moonlight") != ForPrompt("  moonlight ") {
+			t.Error("Expected the same ID for the same prompt written differently")
+		}
+		if ForPrompt("Moonlight") == ForPrompt("Sunrise") {
+			t.Error("Expected different IDs for different prompts")
+		}
+	})
+}
@@ -0,0 +1,116 @@
+// backend/importer/csv.go
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// CSVMapping names the CSV columns that hold each field
+type CSVMapping struct {
+	DateColumn     string `json:"dateColumn"`
+	ContentColumn  string `json:"contentColumn"`
+	QuestionColumn string `json:"questionColumn"` // Optional
+	DateFormat     string `json:"dateFormat"`     // Go layout, defaults to YYYY-MM-DD or RFC 3339
+}
+
+// CSVSource reads a CSV file with a header row
+type CSVSource struct {
+	path    string
+	mapping CSVMapping
+}
+
+// NewCSVSource creates a source for a CSV file using the given column mapping
+func NewCSVSource(path string, mapping CSVMapping) *CSVSource {
+	return &CSVSource{path: path, mapping: mapping}
+}
+
+// Name returns the source format name
+func (s *CSVSource) Name() string {
+	return "csv"
+}
+
+// Records reads every row after the header
+func (s *CSVSource) Records() ([]Record, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	dateIdx, ok := columns[s.mapping.DateColumn]
+	if !ok {
+		return nil, fmt.Errorf("date column %q not found", s.mapping.DateColumn)
+	}
+	contentIdx, ok := columns[s.mapping.ContentColumn]
+	if !ok {
+		return nil, fmt.Errorf("content column %q not found", s.mapping.ContentColumn)
+	}
+	questionIdx := -1
+	if s.mapping.QuestionColumn != "" {
+		idx, ok := columns[s.mapping.QuestionColumn]
+		if !ok {
+			return nil, fmt.Errorf("question column %q not found", s.mapping.QuestionColumn)
+		}
+		questionIdx = idx
+	}
+
+	records := make([]Record, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		var record Record
+
+		if value := field(row, dateIdx); value != "" {
+			date, err := s.parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", i+2, err)
+			}
+			record.Date = date
+		}
+		record.Content = field(row, contentIdx)
+		record.Question = field(row, questionIdx)
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (s *CSVSource) parseDate(value string) (time.Time, error) {
+	layouts := []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04:05"}
+	if s.mapping.DateFormat != "" {
+		layouts = []string{s.mapping.DateFormat}
+	}
+
+	for _, layout := range layouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func field(row []string, idx int) string {
+	if idx < 0 || idx >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[idx])
+}
@@ -0,0 +1,72 @@
+// backend/importer/dayone.go
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DayOneSource reads the JSON file from a Day One export
+type DayOneSource struct {
+	path string
+}
+
+type dayOneExport struct {
+	Entries []dayOneEntry `json:"entries"`
+}
+
+type dayOneEntry struct {
+	CreationDate string `json:"creationDate"`
+	TimeZone     string `json:"timeZone"`
+	Text         string `json:"text"`
+}
+
+// NewDayOneSource creates a source for a Day One JSON export
+func NewDayOneSource(path string) *DayOneSource {
+	return &DayOneSource{path: path}
+}
+
+// Name returns the source format name
+func (s *DayOneSource) Name() string {
+	return "dayone"
+}
+
+// Records reads all entries from the export
+func (s *DayOneSource) Records() ([]Record, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export dayOneExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid Day One export: %w", err)
+	}
+
+	records := make([]Record, 0, len(export.Entries))
+	for _, e := range export.Entries {
+		var date time.Time
+		if e.CreationDate != "" {
+			date, err = time.Parse(time.RFC3339, e.CreationDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Day One creation date %q: %w", e.CreationDate, err)
+			}
+
+			// Day One stores UTC timestamps, so the entry's own time zone
+			// decides which calendar day it belongs to
+			loc := time.Local
+			if e.TimeZone != "" {
+				if l, err := time.LoadLocation(e.TimeZone); err == nil {
+					loc = l
+				}
+			}
+			date = date.In(loc)
+		}
+
+		records = append(records, Record{Date: date, Content: e.Text})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,273 @@
+// backend/importer/importer.go
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"myproject/backend/models"
+	"myproject/backend/textstats"
+)
+
+// Targets an imported record can be mapped into
+const (
+	TargetAnswer     = "answer"
+	TargetCreativity = "creativity"
+)
+
+// Record is a single entry read from an external source
+type Record struct {
+	Date     time.Time
+	Content  string
+	Question string // Only used when the record is imported as an answer
+}
+
+// Source reads records from an external journaling format
+type Source interface {
+	Name() string
+	Records() ([]Record, error)
+}
+
+// Options configure how an import is read and mapped
+type Options struct {
+	Format          string     `json:"format"`          // Registered source name, e.g. "dayone"
+	Path            string     `json:"path"`            // File or folder to import from
+	Target          string     `json:"target"`          // "answer" or "creativity"
+	DefaultQuestion string     `json:"defaultQuestion"` // Question used for answers without their own
+	CSV             CSVMapping `json:"csv"`
+}
+
+// PlannedItem describes one entry an import will create
+type PlannedItem struct {
+	Target   string `json:"target"`
+	Date     string `json:"date"`
+	Question string `json:"question,omitempty"`
+	Preview  string `json:"preview"`
+}
+
+// SkippedRecord describes a record that will not be imported
+type SkippedRecord struct {
+	Index  int    `json:"index"`
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+// Report summarises what an import created, or would create on a dry run
+type Report struct {
+	Source       string          `json:"source"`
+	DryRun       bool            `json:"dryRun"`
+	Total        int             `json:"total"`
+	Answers      int             `json:"answers"`
+	Creativity   int             `json:"creativity"`
+	NewQuestions []string        `json:"newQuestions"`
+	Items        []PlannedItem   `json:"items"`
+	Skipped      []SkippedRecord `json:"skipped"`
+}
+
+// SourceFactory builds a source from import options
+type SourceFactory func(opts Options) (Source, error)
+
+var sources = map[string]SourceFactory{}
+
+// Register makes a source format available to NewSource
+func Register(format string, factory SourceFactory) {
+	sources[format] = factory
+}
+
+// Formats lists the registered source formats
+func Formats() []string {
+	formats := make([]string, 0, len(sources))
+	for name := range sources {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// NewSource builds the source registered for opts.Format
+func NewSource(opts Options) (Source, error) {
+	factory, ok := sources[opts.Format]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q", opts.Format)
+	}
+	return factory(opts)
+}
+
+func init() {
+	Register("dayone", func(opts Options) (Source, error) {
+		return NewDayOneSource(opts.Path), nil
+	})
+	Register("markdown", func(opts Options) (Source, error) {
+		return NewMarkdownFolderSource(opts.Path), nil
+	})
+	Register("csv", func(opts Options) (Source, error) {
+		return NewCSVSource(opts.Path, opts.CSV), nil
+	})
+}
+
+// Importer writes records from external sources into the journal
+type Importer struct {
+	stores *models.Stores
+}
+
+// New creates an importer writing to stores
+func New(stores *models.Stores) *Importer {
+	return &Importer{stores: stores}
+}
+
+// previewLength is how many characters of an entry a report shows
+const previewLength = 80
+
+// plannedEntry is an entry ready to be written to the database
+type plannedEntry struct {
+	target    string
+	date      string
+	createdAt time.Time
+	question  string
+	content   string
+}
+
+// Plan reads the source and reports what an import would create without writing anything
+func (im *Importer) Plan(src Source, opts Options) (*Report, error) {
+	report, _, err := im.plan(src, opts)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = true
+	return report, nil
+}
+
+// Apply imports the records from the source into the database. Nothing is
+// imported if any record fails, so a failed import can be run again.
+func (im *Importer) Apply(src Source, opts Options) (*Report, error) {
+	report, entries, err := im.plan(src, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make([]models.ImportedEntry, len(entries))
+	for i, e := range entries {
+		entryType := models.EntryAnswer
+		if e.target == TargetCreativity {
+			entryType = models.EntryCreativity
+		}
+		imported[i] = models.ImportedEntry{Type: entryType, Question: e.question, Content: e.content, Date: e.date, CreatedAt: e.createdAt}
+	}
+	if err := im.stores.Imports.Import(imported); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (im *Importer) plan(src Source, opts Options) (*Report, []plannedEntry, error) {
+	if opts.Target != TargetAnswer && opts.Target != TargetCreativity {
+		return nil, nil, fmt.Errorf("unknown import target %q", opts.Target)
+	}
+
+	records, err := src.Records()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &Report{
+		Source:       src.Name(),
+		Total:        len(records),
+		NewQuestions: []string{},
+		Items:        []PlannedItem{},
+		Skipped:      []SkippedRecord{},
+	}
+
+	var entries []plannedEntry
+	creativityByDate := map[string]int{}
+	newQuestions := map[string]bool{}
+
+	onePerDay := false
+	if opts.Target == TargetCreativity {
+		if onePerDay, err = im.stores.Creativity.OnePerDay(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i, r := range records {
+		date := r.Date.Format("2006-01-02")
+		content := strings.TrimSpace(r.Content)
+
+		if r.Date.IsZero() {
+			report.Skipped = append(report.Skipped, SkippedRecord{Index: i, Reason: "missing date"})
+			continue
+		}
+		if content == "" {
+			report.Skipped = append(report.Skipped, SkippedRecord{Index: i, Date: date, Reason: "empty content"})
+			continue
+		}
+
+		if opts.Target == TargetCreativity && !onePerDay {
+			entries = append(entries, plannedEntry{target: TargetCreativity, date: date, createdAt: r.Date, content: content})
+			continue
+		}
+		if opts.Target == TargetCreativity {
+			// In one-per-day mode records sharing a date are merged into a
+			// single entry, and dates that already have one are skipped
+			if idx, ok := creativityByDate[date]; ok {
+				entries[idx].content += "\n\n" + content
+				continue
+			}
+
+			exists, err := im.stores.Creativity.HasForDate(date)
+			if err != nil {
+				return nil, nil, err
+			}
+			if exists {
+				report.Skipped = append(report.Skipped, SkippedRecord{Index: i, Date: date, Reason: "creativity entry already exists for this date"})
+				continue
+			}
+
+			creativityByDate[date] = len(entries)
+			entries = append(entries, plannedEntry{target: TargetCreativity, date: date, createdAt: r.Date, content: content})
+			continue
+		}
+
+		question := strings.TrimSpace(r.Question)
+		if question == "" {
+			question = strings.TrimSpace(opts.DefaultQuestion)
+		}
+		if question == "" {
+			report.Skipped = append(report.Skipped, SkippedRecord{Index: i, Date: date, Reason: "no question for answer"})
+			continue
+		}
+
+		if !newQuestions[question] {
+			_, err := im.stores.Questions.GetByContent(question)
+			if errors.Is(err, sql.ErrNoRows) {
+				newQuestions[question] = true
+				report.NewQuestions = append(report.NewQuestions, question)
+			} else if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		entries = append(entries, plannedEntry{target: TargetAnswer, date: date, createdAt: r.Date, question: question, content: content})
+	}
+
+	for _, e := range entries {
+		switch e.target {
+		case TargetAnswer:
+			report.Answers++
+		case TargetCreativity:
+			report.Creativity++
+		}
+		report.Items = append(report.Items, PlannedItem{
+			Target:   e.target,
+			Date:     e.date,
+			Question: e.question,
+			Preview:  textstats.Preview(e.content, previewLength),
+		})
+	}
+
+	return report, entries, nil
+}
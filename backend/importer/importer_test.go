@@ -0,0 +1,242 @@
+// backend/importer/importer_test.go
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myproject/backend/database"
+	"myproject/backend/models"
+)
+
+func TestImporter(t *testing.T) {
+	// Set up an in-memory test database
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	stores := models.NewStores(db, nil)
+	im := New(stores)
+
+	dir := t.TempDir()
+
+	// Test Day One JSON export in one-per-day mode
+	t.Run("DayOne", func(t *testing.T) {
+		if err := stores.Creativity.SetOnePerDay(true); err != nil {
+			t.Fatalf("Failed to enable one-per-day mode: %v", err)
+		}
+		defer stores.Creativity.SetOnePerDay(false)
+
+		path := filepath.Join(dir, "Journal.json")
+		os.WriteFile(path, []byte(`{
+			"metadata": {"version": "1.0"},
+			"entries": [
+				{"creationDate": "2021-02-28T23:30:00Z", "timeZone": "Asia/Tokyo", "text": "First poem"},
+				{"creationDate": "2021-03-02T10:00:00Z", "timeZone": "UTC", "text": "Second poem"},
+				{"creationDate": "2021-03-02T12:00:00Z", "timeZone": "UTC", "text": "Another verse"}
+			]
+		}`), 0644)
+
+		opts := Options{Format: "dayone", Path: path, Target: TargetCreativity}
+		src, err := NewSource(opts)
+		if err != nil {
+			t.Fatalf("Failed to create source: %v", err)
+		}
+
+		// A dry run reports entries without writing them
+		report, err := im.Plan(src, opts)
+		if err != nil {
+			t.Fatalf("Failed to plan import: %v", err)
+		}
+
+		if !report.DryRun {
+			t.Errorf("Expected dry run report")
+		}
+
+		// Entries on the same day are merged into one creativity entry
+		if report.Creativity != 2 {
+			t.Errorf("Expected 2 creativity entries, got %d", report.Creativity)
+		}
+
+		// The first entry is on 1 March in Tokyo's time zone
+		if report.Items[0].Date != "2021-03-01" {
+			t.Errorf("Expected first entry on 2021-03-01, got %s", report.Items[0].Date)
+		}
+
+		entries, _ := stores.Creativity.GetAll()
+		if len(entries) != 0 {
+			t.Errorf("Expected dry run to create no entries, got %d", len(entries))
+		}
+
+		report, err = im.Apply(src, opts)
+		if err != nil {
+			t.Fatalf("Failed to apply import: %v", err)
+		}
+
+		entries, err = stores.Creativity.GetAll()
+		if err != nil {
+			t.Fatalf("Failed to get creativity entries: %v", err)
+		}
+
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 creativity entries, got %d", len(entries))
+		}
+
+		// Importing again skips days that already have an entry
+		report, err = im.Plan(src, opts)
+		if err != nil {
+			t.Fatalf("Failed to plan import: %v", err)
+		}
+
+		if report.Creativity != 0 || len(report.Skipped) != 3 {
+			t.Errorf("Expected all records skipped, got %d planned and %d skipped", report.Creativity, len(report.Skipped))
+		}
+	})
+
+	// Test that records are neither merged nor skipped when a day can hold
+	// several creativity entries
+	t.Run("SeveralPerDay", func(t *testing.T) {
+		path := filepath.Join(dir, "Sketches.json")
+		os.WriteFile(path, []byte(`{
+			"metadata": {"version": "1.0"},
+			"entries": [
+				{"creationDate": "2021-03-02T14:00:00Z", "timeZone": "UTC", "text": "A sketch"},
+				{"creationDate": "2021-03-02T16:00:00Z", "timeZone": "UTC", "text": "Another sketch"},
+				{"creationDate": "2021-03-05T09:00:00Z", "timeZone": "UTC", "text": "A haiku"}
+			]
+		}`), 0644)
+
+		opts := Options{Format: "dayone", Path: path, Target: TargetCreativity}
+		src, err := NewSource(opts)
+		if err != nil {
+			t.Fatalf("Failed to create source: %v", err)
+		}
+
+		report, err := im.Apply(src, opts)
+		if err != nil {
+			t.Fatalf("Failed to apply import: %v", err)
+		}
+		if report.Creativity != 3 || len(report.Skipped) != 0 {
+			t.Errorf("Expected 3 records planned, got %d planned and %d skipped", report.Creativity, len(report.Skipped))
+		}
+
+		// 2 March already had an entry from the Day One import
+		entries, err := stores.Creativity.GetByDate("2021-03-02")
+		if err != nil {
+			t.Fatalf("Failed to get creativity entries: %v", err)
+		}
+		if len(entries) != 3 {
+			t.Errorf("Expected 3 creativity entries on 2021-03-02, got %d", len(entries))
+		}
+	})
+
+	// Test a folder of Markdown files imported as answers
+	t.Run("Markdown", func(t *testing.T) {
+		mdDir := filepath.Join(dir, "markdown")
+		os.Mkdir(mdDir, 0755)
+		os.WriteFile(filepath.Join(mdDir, "2020-01-05.md"), []byte("# What went well?\n\nShipped the release."), 0644)
+		os.WriteFile(filepath.Join(mdDir, "notes.md"), []byte("---\ndate: 2020-01-06\n---\nA quiet day."), 0644)
+		os.WriteFile(filepath.Join(mdDir, "ignore.txt"), []byte("not markdown"), 0644)
+
+		opts := Options{Format: "markdown", Path: mdDir, Target: TargetAnswer, DefaultQuestion: "Imported reflection"}
+		src, err := NewSource(opts)
+		if err != nil {
+			t.Fatalf("Failed to create source: %v", err)
+		}
+
+		report, err := im.Apply(src, opts)
+		if err != nil {
+			t.Fatalf("Failed to apply import: %v", err)
+		}
+
+		if report.Answers != 2 {
+			t.Errorf("Expected 2 answers, got %d", report.Answers)
+		}
+
+		if len(report.NewQuestions) != 2 {
+			t.Errorf("Expected 2 new questions, got %d", len(report.NewQuestions))
+		}
+
+		question, err := stores.Questions.GetByContent("What went well?")
+		if err != nil {
+			t.Fatalf("Failed to find imported question: %v", err)
+		}
+
+		history, err := stores.Answers.GetHistoryByQuestionID(question.ID)
+		if err != nil {
+			t.Fatalf("Failed to get answer history: %v", err)
+		}
+
+		if len(history) != 1 {
+			t.Fatalf("Expected 1 answer, got %d", len(history))
+		}
+
+		// The original date is preserved
+		if history[0].CreatedAt.Format("2006-01-02") != "2020-01-05" {
+			t.Errorf("Expected answer dated 2020-01-05, got %s", history[0].CreatedAt.Format("2006-01-02"))
+		}
+
+		if history[0].Content != "Shipped the release." {
+			t.Errorf("Expected heading to be stripped from content, got '%s'", history[0].Content)
+		}
+	})
+
+	// Test a CSV file with a column mapping
+	t.Run("CSV", func(t *testing.T) {
+		path := filepath.Join(dir, "entries.csv")
+		os.WriteFile(path, []byte("When,Prompt,Text\n03/04/2019,What went well?,Finished the book\n03/05/2019,,\n"), 0644)
+
+		opts := Options{
+			Format: "csv",
+			Path:   path,
+			Target: TargetAnswer,
+			CSV: CSVMapping{
+				DateColumn:     "When",
+				ContentColumn:  "Text",
+				QuestionColumn: "Prompt",
+				DateFormat:     "01/02/2006",
+			},
+		}
+		src, err := NewSource(opts)
+		if err != nil {
+			t.Fatalf("Failed to create source: %v", err)
+		}
+
+		report, err := im.Plan(src, opts)
+		if err != nil {
+			t.Fatalf("Failed to plan import: %v", err)
+		}
+
+		if report.Answers != 1 {
+			t.Errorf("Expected 1 answer, got %d", report.Answers)
+		}
+
+		if len(report.Skipped) != 1 || report.Skipped[0].Reason != "empty content" {
+			t.Errorf("Expected the empty row to be skipped, got %+v", report.Skipped)
+		}
+
+		// The question already exists from the Markdown import
+		if len(report.NewQuestions) != 0 {
+			t.Errorf("Expected no new questions, got %v", report.NewQuestions)
+		}
+
+		if report.Items[0].Date != "2019-03-04" {
+			t.Errorf("Expected answer dated 2019-03-04, got %s", report.Items[0].Date)
+		}
+	})
+
+	// Test unknown formats and targets
+	t.Run("InvalidOptions", func(t *testing.T) {
+		if _, err := NewSource(Options{Format: "unknown"}); err == nil {
+			t.Errorf("Expected error for unknown format")
+		}
+
+		src := NewCSVSource(filepath.Join(dir, "entries.csv"), CSVMapping{DateColumn: "When", ContentColumn: "Text"})
+		if _, err := im.Plan(src, Options{Target: "gratitude"}); err == nil {
+			t.Errorf("Expected error for unknown target")
+		}
+	})
+}
@@ -0,0 +1,113 @@
+// backend/importer/markdown.go
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MarkdownFolderSource reads a folder of dated Markdown files.
+// Each file's name must start with its date (2024-01-31.md or
+// 2024-01-31-evening.md), or the file must have a "date:" line in
+// its front matter. A leading "# Heading" is used as the question
+// when the file is imported as an answer.
+type MarkdownFolderSource struct {
+	dir string
+}
+
+var markdownDatePrefix = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})`)
+
+// NewMarkdownFolderSource creates a source for a folder of Markdown files
+func NewMarkdownFolderSource(dir string) *MarkdownFolderSource {
+	return &MarkdownFolderSource{dir: dir}
+}
+
+// Name returns the source format name
+func (s *MarkdownFolderSource) Name() string {
+	return "markdown"
+}
+
+// Records reads every Markdown file in the folder, ordered by file name
+func (s *MarkdownFolderSource) Records() ([]Record, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		ext := strings.ToLower(filepath.Ext(f.Name()))
+		if f.IsDir() || (ext != ".md" && ext != ".markdown") {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	records := make([]Record, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := parseMarkdownEntry(name, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func parseMarkdownEntry(name string, text string) (Record, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var dateStr string
+	if m := markdownDatePrefix.FindStringSubmatch(name); m != nil {
+		dateStr = m[1]
+	}
+
+	// Strip YAML front matter, picking up the date if the file name has none
+	if strings.HasPrefix(text, "---\n") {
+		if end := strings.Index(text[4:], "\n---"); end >= 0 {
+			frontMatter := text[4 : 4+end]
+			text = strings.TrimPrefix(text[4+end+4:], "\n")
+
+			for _, line := range strings.Split(frontMatter, "\n") {
+				key, value, ok := strings.Cut(line, ":")
+				if ok && strings.TrimSpace(key) == "date" && dateStr == "" {
+					dateStr = strings.Trim(strings.TrimSpace(value), `"'`)
+				}
+			}
+		}
+	}
+
+	var record Record
+	if dateStr != "" {
+		if len(dateStr) > 10 {
+			dateStr = dateStr[:10]
+		}
+		date, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			return record, fmt.Errorf("invalid date %q", dateStr)
+		}
+		record.Date = date
+	}
+
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "# ") {
+		heading, rest, _ := strings.Cut(text, "\n")
+		record.Question = strings.TrimSpace(strings.TrimPrefix(heading, "# "))
+		text = strings.TrimSpace(rest)
+	}
+	record.Content = text
+
+	return record, nil
+}
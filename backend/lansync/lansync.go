@@ -0,0 +1,594 @@
+// backend/lansync/lansync.go
+package lansync
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"myproject/backend/replication"
+)
+
+// PairingCodeLifetime is how long a pairing code can be used
+const PairingCodeLifetime = 5 * time.Minute
+
+// maxPairingAttempts is how many wrong codes invalidate a pairing code
+const maxPairingAttempts = 5
+
+// The largest request bodies read from the network. Pushed changes carry
+// whole records, so a first sync of a large journal needs room.
+const (
+	maxPairBody    = 64 << 10
+	maxChangesBody = 64 << 20
+)
+
+// ErrUnknownPeer is returned when syncing with a device that isn't paired
+var ErrUnknownPeer = errors.New("device is not paired")
+
+// Peer is a paired device
+type Peer struct {
+	DeviceID    string     `json:"deviceId"`
+	Name        string     `json:"name"`
+	Address     string     `json:"address"`     // host:port of the peer's sync server, if known
+	SentSeq     int64      `json:"sentSeq"`     // Last local change the peer has applied
+	ReceivedSeq int64      `json:"receivedSeq"` // Last change of the peer's applied here
+	PairedAt    time.Time  `json:"pairedAt"`
+	LastSyncAt  *time.Time `json:"lastSyncAt"`
+}
+
+// PairingCode lets another device pair with this one
+type PairingCode struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SyncResult reports a completed sync with a peer
+type SyncResult struct {
+	DeviceID  string                 `json:"deviceId"`
+	Received  int                    `json:"received"` // Changes applied here
+	Sent      int                    `json:"sent"`     // Changes applied by the peer
+	Conflicts []replication.Conflict `json:"conflicts"`
+	At        time.Time              `json:"at"`
+}
+
+// pairRequest is sent to a device's sync server to pair with it
+type pairRequest struct {
+	Code     string `json:"code"`
+	DeviceID string `json:"deviceId"`
+	Name     string `json:"name"`
+	Port     int    `json:"port"` // Port of the requester's sync server, 0 if it has none
+}
+
+// pairResponse identifies the paired device and carries the shared token
+type pairResponse struct {
+	DeviceID string `json:"deviceId"`
+	Name     string `json:"name"`
+	Token    string `json:"token"`
+}
+
+// changesResponse lists changes made on the serving device
+type changesResponse struct {
+	Changes []replication.Change `json:"changes"`
+	Latest  int64                `json:"latest"`
+}
+
+// pushRequest sends changes to a device and acknowledges the ones received from it
+type pushRequest struct {
+	Changes  []replication.Change `json:"changes"`
+	Latest   int64                `json:"latest"`   // Last change of the sender's included
+	Received int64                `json:"received"` // Last change of the receiver's applied by the sender
+}
+
+// Service syncs a journal database with paired devices over HTTP. It serves
+// the sync endpoints when listening, and syncs with peers on request.
+type Service struct {
+	db      *sql.DB
+	name    string
+	client  *http.Client
+	applied func(SyncResult)
+
+	mu       sync.Mutex
+	syncing  sync.Mutex
+	code     *PairingCode
+	attempts int
+	server   *http.Server
+	port     int
+}
+
+// New creates a sync service for db. name is shown to paired devices.
+// applied, which may be nil, is called after a peer that started a sync
+// sent its changes here.
+func New(db *sql.DB, name string, applied func(SyncResult)) *Service {
+	return &Service{
+		db:      db,
+		name:    name,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		applied: applied,
+	}
+}
+
+// DeviceID returns this journal's device ID
+func (s *Service) DeviceID() (string, error) {
+	return replication.DeviceID(s.db)
+}
+
+// Handler returns the HTTP handler serving the sync endpoints
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sync/pair", s.handlePair)
+	mux.HandleFunc("/sync/changes", s.handleChanges)
+	return mux
+}
+
+// Listen starts serving the sync endpoints on addr, such as ":0" for any
+// free port, and returns the address it listens on
+func (s *Service) Listen(addr string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server != nil {
+		return "", fmt.Errorf("sync server is already running")
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	s.server = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	s.port = ln.Addr().(*net.TCPAddr).Port
+	go s.server.Serve(ln)
+
+	return ln.Addr().String(), nil
+}
+
+// Listening reports whether the sync server is running, and its port
+func (s *Service) Listening() (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.server != nil, s.port
+}
+
+// Close stops the sync server, if it is running
+func (s *Service) Close() error {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.port = 0
+	s.code = nil
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// StartPairing creates a new pairing code, replacing any earlier one. The
+// code can be used once, until it expires.
+func (s *Service) StartPairing() (*PairingCode, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.code = &PairingCode{
+		Code:      fmt.Sprintf("%06d", n.Int64()),
+		ExpiresAt: time.Now().Add(PairingCodeLifetime),
+	}
+	s.attempts = 0
+	code := *s.code
+	return &code, nil
+}
+
+// useCode checks a pairing code, invalidating it once used or guessed too often
+func (s *Service) useCode(code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.code == nil || time.Now().After(s.code.ExpiresAt) {
+		s.code = nil
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(code), []byte(s.code.Code)) != 1 {
+		s.attempts++
+		if s.attempts >= maxPairingAttempts {
+			s.code = nil
+		}
+		return false
+	}
+	s.code = nil
+	return true
+}
+
+// newToken returns a random secret shared by two paired devices
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Pair pairs with the device whose sync server is at addr (host:port),
+// using the pairing code it displays
+func (s *Service) Pair(addr string, code string) (*Peer, error) {
+	deviceID, err := s.DeviceID()
+	if err != nil {
+		return nil, err
+	}
+	_, port := s.Listening()
+
+	var resp pairResponse
+	req := pairRequest{Code: strings.TrimSpace(code), DeviceID: deviceID, Name: s.name, Port: port}
+	if err := s.call(http.MethodPost, addr, "/sync/pair", "", req, &resp); err != nil {
+		return nil, err
+	}
+
+	if err := s.savePeer(resp.DeviceID, resp.Name, addr, resp.Token); err != nil {
+		return nil, err
+	}
+	return s.GetPeer(resp.DeviceID)
+}
+
+// savePeer records a paired device, replacing an earlier pairing with it
+func (s *Service) savePeer(deviceID string, name string, addr string, token string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO sync_peers (device_id, name, address, token)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(device_id) DO UPDATE SET
+			name = excluded.name,
+			address = excluded.address,
+			token = excluded.token,
+			paired_at = CURRENT_TIMESTAMP`, deviceID, name, addr, token)
+	return err
+}
+
+// Peers lists the paired devices
+func (s *Service) Peers() ([]Peer, error) {
+	rows, err := s.db.Query(`
+		SELECT device_id, name, address, sent_seq, received_seq, paired_at, last_sync_at
+		FROM sync_peers
+		ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	peers := []Peer{}
+	for rows.Next() {
+		p, err := scanPeer(rows)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, *p)
+	}
+	return peers, rows.Err()
+}
+
+// GetPeer retrieves a paired device
+func (s *Service) GetPeer(deviceID string) (*Peer, error) {
+	p, err := scanPeer(s.db.QueryRow(`
+		SELECT device_id, name, address, sent_seq, received_seq, paired_at, last_sync_at
+		FROM sync_peers
+		WHERE device_id = ?`, deviceID))
+	if err == sql.ErrNoRows {
+		return nil, ErrUnknownPeer
+	}
+	return p, err
+}
+
+// scanPeer reads a peer row selected by Peers or GetPeer
+func scanPeer(row interface{ Scan(...any) error }) (*Peer, error) {
+	var p Peer
+	var lastSync sql.NullTime
+	err := row.Scan(&p.DeviceID, &p.Name, &p.Address, &p.SentSeq, &p.ReceivedSeq, &p.PairedAt, &lastSync)
+	if err != nil {
+		return nil, err
+	}
+	if lastSync.Valid {
+		p.LastSyncAt = &lastSync.Time
+	}
+	return &p, nil
+}
+
+// RemovePeer forgets a paired device. It can no longer sync until paired again.
+func (s *Service) RemovePeer(deviceID string) error {
+	res, err := s.db.Exec(`DELETE FROM sync_peers WHERE device_id = ?`, deviceID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUnknownPeer
+	}
+	return nil
+}
+
+// Sync exchanges changes with a paired device: it applies the peer's
+// changes here, then sends the peer the local changes it hasn't seen
+func (s *Service) Sync(deviceID string) (*SyncResult, error) {
+	s.syncing.Lock()
+	defer s.syncing.Unlock()
+
+	peer, err := s.GetPeer(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if peer.Address == "" {
+		return nil, fmt.Errorf("no address is known for %s", peer.Name)
+	}
+	var token string
+	if err := s.db.QueryRow(`SELECT token FROM sync_peers WHERE device_id = ?`, deviceID).Scan(&token); err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{DeviceID: deviceID, Conflicts: []replication.Conflict{}}
+
+	// Pull
+	var pulled changesResponse
+	query := "/sync/changes?since=" + strconv.FormatInt(peer.ReceivedSeq, 10)
+	if err := s.call(http.MethodGet, peer.Address, query, token, nil, &pulled); err != nil {
+		return nil, err
+	}
+	applied, err := replication.Apply(s.db, pulled.Changes, peer.SentSeq)
+	if err != nil {
+		return nil, err
+	}
+	result.Received = applied.Applied
+	result.Conflicts = append(result.Conflicts, applied.Conflicts...)
+
+	_, err = s.db.Exec(`UPDATE sync_peers SET received_seq = ? WHERE device_id = ?`, pulled.Latest, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Push
+	latest, err := replication.LatestSeq(s.db)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := replication.Changes(s.db, peer.SentSeq, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	var pushed replication.Result
+	req := pushRequest{Changes: changes, Latest: latest, Received: pulled.Latest}
+	if err := s.call(http.MethodPost, peer.Address, "/sync/changes", token, req, &pushed); err != nil {
+		return nil, err
+	}
+	result.Sent = pushed.Applied
+	result.Conflicts = append(result.Conflicts, pushed.Conflicts...)
+
+	result.At = time.Now()
+	_, err = s.db.Exec(`
+		UPDATE sync_peers
+		SET sent_seq = ?, last_sync_at = ?
+		WHERE device_id = ?`, latest, result.At, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// call sends a request to a peer's sync server and decodes the JSON reply into out
+func (s *Service) call(method string, addr string, path string, token string, in any, out any) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, "http://"+addr+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Replies are read only up to the limit the server puts on requests
+	reply := io.LimitReader(resp.Body, maxChangesBody)
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(reply).Decode(&e)
+		if e.Error == "" {
+			e.Error = resp.Status
+		}
+		return fmt.Errorf("sync with %s failed: %s", addr, e.Error)
+	}
+	return json.NewDecoder(reply).Decode(out)
+}
+
+// writeJSON sends v as the response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeBody decodes a JSON request body of at most limit bytes into v
+func decodeBody(w http.ResponseWriter, r *http.Request, limit int64, v any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(v)
+}
+
+// bodyStatus is the status for a request whose body couldn't be decoded
+func bodyStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// writeError sends an error message as the response body
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// handlePair pairs a device that presents this device's pairing code
+func (s *Service) handlePair(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req pairRequest
+	if err := decodeBody(w, r, maxPairBody, &req); err != nil || req.DeviceID == "" {
+		writeError(w, bodyStatus(err), "invalid pairing request")
+		return
+	}
+	if !s.useCode(req.Code) {
+		writeError(w, http.StatusForbidden, "invalid or expired pairing code")
+		return
+	}
+
+	deviceID, err := s.DeviceID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.DeviceID == deviceID {
+		writeError(w, http.StatusBadRequest, "a journal can't pair with itself")
+		return
+	}
+
+	// The requester can be reached on the address it connected from
+	addr := ""
+	if req.Port > 0 {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err == nil {
+			addr = net.JoinHostPort(host, strconv.Itoa(req.Port))
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := s.savePeer(req.DeviceID, req.Name, addr, token); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pairResponse{DeviceID: deviceID, Name: s.name, Token: token})
+}
+
+// authenticate returns the paired device presenting the request's token
+func (s *Service) authenticate(r *http.Request) (*Peer, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, false
+	}
+
+	var deviceID string
+	err := s.db.QueryRow(`SELECT device_id FROM sync_peers WHERE token = ?`, token).Scan(&deviceID)
+	if err != nil {
+		return nil, false
+	}
+	peer, err := s.GetPeer(deviceID)
+	if err != nil {
+		return nil, false
+	}
+	return peer, true
+}
+
+// handleChanges serves local changes to a peer (GET) or applies a peer's changes (POST)
+func (s *Service) handleChanges(w http.ResponseWriter, r *http.Request) {
+	peer, ok := s.authenticate(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unknown device")
+		return
+	}
+
+	// Changes are exchanged one sync at a time, whichever side started it.
+	// A peer that finds a sync running is turned away rather than kept
+	// waiting, as the running sync may be waiting on that peer.
+	if !s.syncing.TryLock() {
+		writeError(w, http.StatusServiceUnavailable, "a sync is already in progress")
+		return
+	}
+	defer s.syncing.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		since, err := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid since")
+			return
+		}
+		latest, err := replication.LatestSeq(s.db)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		changes, err := replication.Changes(s.db, since, peer.DeviceID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, changesResponse{Changes: changes, Latest: latest})
+
+	case http.MethodPost:
+		var req pushRequest
+		if err := decodeBody(w, r, maxChangesBody, &req); err != nil {
+			writeError(w, bodyStatus(err), "invalid changes")
+			return
+		}
+		result, err := replication.Apply(s.db, req.Changes, req.Received)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		now := time.Now()
+		_, err = s.db.Exec(`
+			UPDATE sync_peers
+			SET sent_seq = ?, received_seq = ?, last_sync_at = ?
+			WHERE device_id = ?`, req.Received, req.Latest, now, peer.DeviceID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+
+		if s.applied != nil {
+			s.applied(SyncResult{DeviceID: peer.DeviceID, Received: result.Applied, Conflicts: result.Conflicts, At: now})
+		}
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,351 @@
+// backend/lansync/lansync_test.go
+package lansync
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"myproject/backend/database"
+	"myproject/backend/models"
+	"myproject/backend/profiles"
+	"myproject/backend/replication"
+)
+
+// instance is one installation of the app: a journal and its sync service
+type instance struct {
+	db      *sql.DB
+	stores  *models.Stores
+	service *Service
+	addr    string
+}
+
+func newInstance(t *testing.T, name string) *instance {
+	t.Helper()
+
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	service := New(db, name, nil)
+	addr, err := service.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start sync server: %v", err)
+	}
+	t.Cleanup(func() {
+		service.Close()
+		db.Close()
+	})
+
+	return &instance{db: db, stores: models.NewStores(db, nil), service: service, addr: addr}
+}
+
+// snapshot describes every synced record, for comparing two journals
+func snapshot(t *testing.T, in *instance) string {
+	t.Helper()
+
+	changes, err := replication.Changes(in.db, 0, "")
+	if err != nil {
+		t.Fatalf("Failed to read changes: %v", err)
+	}
+
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		lines = append(lines, fmt.Sprintf("%s %s %d %s %v %v", c.Table, c.UUID, c.Modified, c.Device, c.Deleted, c.Data))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// pair pairs a with b using a code displayed by b
+func pair(t *testing.T, a *instance, b *instance) *Peer {
+	t.Helper()
+
+	code, err := b.service.StartPairing()
+	if err != nil {
+		t.Fatalf("Failed to start pairing: %v", err)
+	}
+	peer, err := a.service.Pair(b.addr, code.Code)
+	if err != nil {
+		t.Fatalf("Failed to pair: %v", err)
+	}
+	return peer
+}
+
+func syncWith(t *testing.T, a *instance, b *instance) *SyncResult {
+	t.Helper()
+
+	id, err := b.service.DeviceID()
+	if err != nil {
+		t.Fatalf("Failed to get device ID: %v", err)
+	}
+	result, err := a.service.Sync(id)
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+	return result
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	// Test that pairing needs the current code, and only works once
+	t.Run("Pairing", func(t *testing.T) {
+		a, b := newInstance(t, "Laptop"), newInstance(t, "Desktop")
+
+		code, err := b.service.StartPairing()
+		if err != nil {
+			t.Fatalf("Failed to start pairing: %v", err)
+		}
+
+		if _, err := a.service.Pair(b.addr, "not-the-code"); err == nil {
+			t.Error("Expected pairing with a wrong code to fail")
+		}
+
+		peer, err := a.service.Pair(b.addr, code.Code)
+		if err != nil {
+			t.Fatalf("Failed to pair: %v", err)
+		}
+		if peer.Name != "Desktop" {
+			t.Errorf("Expected peer named Desktop, got %q", peer.Name)
+		}
+
+		if _, err := a.service.Pair(b.addr, code.Code); err == nil {
+			t.Error("Expected a used pairing code to be rejected")
+		}
+
+		// Both sides know each other, so either can start a sync
+		peers, err := b.service.Peers()
+		if err != nil {
+			t.Fatalf("Failed to list peers: %v", err)
+		}
+		if len(peers) != 1 || peers[0].Name != "Laptop" || peers[0].Address == "" {
+			t.Errorf("Expected the laptop paired with a known address, got %+v", peers)
+		}
+	})
+
+	// Test that a device that isn't paired can't read changes
+	t.Run("Unauthorized", func(t *testing.T) {
+		b := newInstance(t, "Desktop")
+
+		req, _ := http.NewRequest(http.MethodGet, "http://"+b.addr+"/sync/changes?since=0", nil)
+		req.Header.Set("Authorization", "Bearer guessed")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", resp.StatusCode)
+		}
+
+		// Request bodies are read only up to a limit
+		body := `{"deviceId": "` + strings.Repeat("x", maxPairBody) + `"}`
+		resp, err = http.Post("http://"+b.addr+"/sync/pair", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413, got %d", resp.StatusCode)
+		}
+	})
+
+	// Test that two journals seeded with the same questions converge
+	// without duplicating them
+	t.Run("Converge", func(t *testing.T) {
+		a, b := newInstance(t, "Laptop"), newInstance(t, "Desktop")
+
+		for _, in := range []*instance{a, b} {
+			if err := profiles.Seed(in.stores, profiles.SeedDefault); err != nil {
+				t.Fatalf("Failed to seed questions: %v", err)
+			}
+		}
+
+		question, err := a.stores.Questions.GetRandom()
+		if err != nil {
+			t.Fatalf("Failed to get question: %v", err)
+		}
+		if _, err := a.stores.Answers.Create(question.ID, "Slept well and went running"); err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+		if _, err := b.stores.Gratitude.Add("Coffee with an old friend"); err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+		affirmation, err := b.stores.Affirmations.Save("I am enough")
+		if err != nil {
+			t.Fatalf("Failed to save affirmation: %v", err)
+		}
+		if err := b.stores.Affirmations.LogCompletion(affirmation.ID); err != nil {
+			t.Fatalf("Failed to log affirmation: %v", err)
+		}
+
+		pair(t, a, b)
+		result := syncWith(t, a, b)
+		if len(result.Conflicts) != 0 {
+			t.Errorf("Expected no conflicts, got %+v", result.Conflicts)
+		}
+		if snapshot(t, a) != snapshot(t, b) {
+			t.Fatal("Expected journals to match after syncing")
+		}
+
+		questionsA, _ := a.stores.Questions.GetAll()
+		questionsB, _ := b.stores.Questions.GetAll()
+		defaults, _ := profiles.SeedQuestions(profiles.SeedDefault)
+		if len(questionsA) != len(defaults) || len(questionsB) != len(defaults) {
+			t.Errorf("Expected %d questions on both, got %d and %d", len(defaults), len(questionsA), len(questionsB))
+		}
+
+		synced, err := b.stores.Questions.GetByContent(question.Content)
+		if err != nil {
+			t.Fatalf("Failed to get synced question: %v", err)
+		}
+		history, _ := b.stores.Answers.GetHistoryByQuestionID(synced.ID)
+		if len(history) != 1 {
+			t.Errorf("Expected the answer synced with its question, got %+v", history)
+		}
+
+		logs, _ := a.stores.Affirmations.GetAllLogs()
+		if len(logs) != 1 {
+			t.Errorf("Expected the affirmation log synced, got %d", len(logs))
+		}
+
+		// Syncing again has nothing to do
+		result = syncWith(t, a, b)
+		if result.Received != 0 || result.Sent != 0 {
+			t.Errorf("Expected nothing to sync, got %d received and %d sent", result.Received, result.Sent)
+		}
+	})
+
+	// Test that a device already syncing turns away a peer's sync
+	t.Run("Busy", func(t *testing.T) {
+		a, b := newInstance(t, "Laptop"), newInstance(t, "Desktop")
+		pair(t, a, b)
+
+		id, err := b.service.DeviceID()
+		if err != nil {
+			t.Fatalf("Failed to get device ID: %v", err)
+		}
+		b.service.syncing.Lock()
+		_, err = a.service.Sync(id)
+		b.service.syncing.Unlock()
+		if err == nil || !strings.Contains(err.Error(), "already in progress") {
+			t.Errorf("Expected the sync turned away, got %v", err)
+		}
+
+		syncWith(t, a, b)
+	})
+
+	// Test that a record changed on both devices is reported, and the later
+	// change wins on both
+	t.Run("Conflict", func(t *testing.T) {
+		a, b := newInstance(t, "Laptop"), newInstance(t, "Desktop")
+
+		item, err := a.stores.Gratitude.Add("Sunshine")
+		if err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+		pair(t, a, b)
+		syncWith(t, a, b)
+
+		items, _ := b.stores.Gratitude.GetToday()
+		if len(items) != 1 {
+			t.Fatalf("Expected 1 synced gratitude item, got %d", len(items))
+		}
+
+		if err := a.stores.Gratitude.Update(item.ID, "Sunshine in the morning"); err != nil {
+			t.Fatalf("Failed to update gratitude item: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if err := b.stores.Gratitude.Update(items[0].ID, "Sunshine after rain"); err != nil {
+			t.Fatalf("Failed to update gratitude item: %v", err)
+		}
+
+		// The desktop's edit is later, so it wins when the desktop syncs
+		result := syncWith(t, b, a)
+		if len(result.Conflicts) != 1 || result.Conflicts[0].Winner != "local" {
+			t.Errorf("Expected 1 conflict won by the desktop, got %+v", result.Conflicts)
+		}
+		if snapshot(t, a) != snapshot(t, b) {
+			t.Fatal("Expected journals to match after syncing")
+		}
+
+		items, _ = a.stores.Gratitude.GetToday()
+		if len(items) != 1 || items[0].Content != "Sunshine after rain" {
+			t.Errorf("Expected the later edit to win, got %+v", items)
+		}
+	})
+
+	// Test that deletes reach the other device, with what belonged to the record
+	t.Run("Delete", func(t *testing.T) {
+		a, b := newInstance(t, "Laptop"), newInstance(t, "Desktop")
+
+		question, err := a.stores.Questions.Add("What surprised you?")
+		if err != nil {
+			t.Fatalf("Failed to add question: %v", err)
+		}
+		if _, err := a.stores.Answers.Create(question.ID, "The snow"); err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+		pair(t, a, b)
+		syncWith(t, a, b)
+
+		synced, err := b.stores.Questions.GetByContent("What surprised you?")
+		if err != nil {
+			t.Fatalf("Failed to get synced question: %v", err)
+		}
+		if err := b.stores.Questions.Delete(synced.ID); err != nil {
+			t.Fatalf("Failed to delete question: %v", err)
+		}
+
+		syncWith(t, a, b)
+		if snapshot(t, a) != snapshot(t, b) {
+			t.Fatal("Expected journals to match after syncing")
+		}
+		if _, err := a.stores.Questions.GetByID(question.ID); err != sql.ErrNoRows {
+			t.Errorf("Expected question deleted, got %v", err)
+		}
+		answers, _ := a.stores.Answers.GetAll()
+		if len(answers) != 0 {
+			t.Errorf("Expected answers deleted with the question, got %d", len(answers))
+		}
+
+		// A routine takes its runs with it, and the steps done in them, even
+		// runs the deleting device hasn't seen
+		routine, err := a.stores.Routines.Create(models.Routine{Name: "Wind down", Steps: []models.RoutineStep{{Key: "plan", Type: models.StepIntention, Label: "Plan"}}})
+		if err != nil {
+			t.Fatalf("Failed to create routine: %v", err)
+		}
+		syncWith(t, a, b)
+		run, err := a.stores.Routines.Start(routine.ID, "2024-05-06")
+		if err != nil {
+			t.Fatalf("Failed to start routine: %v", err)
+		}
+		if _, err := a.stores.Routines.CompleteStep(run.ID, "plan", 0, "Read a book"); err != nil {
+			t.Fatalf("Failed to complete step: %v", err)
+		}
+
+		var syncedID int64
+		if err := b.db.QueryRow(`SELECT id FROM routines WHERE uuid = ?`, routine.UUID).Scan(&syncedID); err != nil {
+			t.Fatalf("Failed to find synced routine: %v", err)
+		}
+		if err := b.stores.Routines.Delete(syncedID); err != nil {
+			t.Fatalf("Failed to delete routine: %v", err)
+		}
+
+		syncWith(t, a, b)
+		var steps int
+		if err := a.db.QueryRow(`SELECT COUNT(*) FROM routine_run_steps`).Scan(&steps); err != nil {
+			t.Fatalf("Failed to count steps: %v", err)
+		}
+		if steps != 0 {
+			t.Errorf("Expected run steps deleted with the routine, got %d", steps)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+// backend/logging/logging.go
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileName is the name of the active log file inside the log directory
+const FileName = "daily-reflection.log"
+
+// Options configure the application logger
+type Options struct {
+	Dir        string     // Directory for log files
+	Level      slog.Level // Minimum level written
+	MaxSize    int64      // Bytes before the file is rotated
+	MaxBackups int        // Rotated files kept
+}
+
+// Dir returns the log directory inside a data directory
+func Dir(dataDir string) string {
+	return filepath.Join(dataDir, "logs")
+}
+
+// ParseLevel parses a level name such as "debug" or "warn", defaulting to info
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// New creates a JSON logger writing to a rotating file in opts.Dir.
+// Warnings and errors are also written to stderr.
+func New(opts Options) (*slog.Logger, io.Closer, error) {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 5 << 20
+	}
+	if opts.MaxBackups <= 0 {
+		opts.MaxBackups = 3
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	file, err := NewRotatingFile(filepath.Join(opts.Dir, FileName), opts.MaxSize, opts.MaxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger := slog.New(fanout{
+		slog.NewJSONHandler(file, &slog.HandlerOptions{Level: opts.Level}),
+		slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	})
+
+	return logger, file, nil
+}
+
+// RotatingFile is an io.Writer that rotates the file once it reaches a size limit.
+// Rotated files are named path.1 (newest) to path.N (oldest).
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFile opens path for appending
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write writes p to the file, rotating first if p would push it over the limit
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+// Close closes the underlying file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,82 @@
+// backend/logging/logging_test.go
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogging(t *testing.T) {
+	// Test that files rotate at the size limit and old ones are dropped
+	t.Run("Rotation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), FileName)
+
+		file, err := NewRotatingFile(path, 10, 2)
+		if err != nil {
+			t.Fatalf("Failed to open rotating file: %v", err)
+		}
+		defer file.Close()
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			if _, err := file.Write([]byte(line)); err != nil {
+				t.Fatalf("Failed to write: %v", err)
+			}
+		}
+
+		current, _ := os.ReadFile(path)
+		if string(current) != "fourth\n" {
+			t.Errorf("Expected current file to hold the last line, got %q", current)
+		}
+
+		newest, _ := os.ReadFile(path + ".1")
+		if string(newest) != "third\n" {
+			t.Errorf("Expected newest backup to hold 'third', got %q", newest)
+		}
+
+		if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+			t.Errorf("Expected only 2 backups to be kept")
+		}
+	})
+
+	// Test the logger writes JSON at the configured level
+	t.Run("Logger", func(t *testing.T) {
+		dir := t.TempDir()
+
+		logger, closer, err := New(Options{Dir: dir, Level: ParseLevel("warn")})
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+
+		logger.Info("not written")
+		logger.Warn("written", "count", 3)
+		closer.Close()
+
+		data, err := os.ReadFile(filepath.Join(dir, FileName))
+		if err != nil {
+			t.Fatalf("Failed to read log file: %v", err)
+		}
+		out := string(data)
+
+		if strings.Contains(out, "not written") {
+			t.Errorf("Expected info message to be filtered out")
+		}
+
+		if !strings.Contains(out, `"msg":"written"`) || !strings.Contains(out, `"count":3`) {
+			t.Errorf("Expected JSON warning in log, got %q", out)
+		}
+	})
+
+	// Test level parsing
+	t.Run("ParseLevel", func(t *testing.T) {
+		if ParseLevel("debug") != slog.LevelDebug {
+			t.Errorf("Expected debug level")
+		}
+
+		if ParseLevel("nonsense") != slog.LevelInfo {
+			t.Errorf("Expected unknown levels to default to info")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+// backend/models/activity.go
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// DayActivity collects everything written on a single date
+type DayActivity struct {
+	Date       string            `json:"date"` // YYYY-MM-DD
+	Answers    []Answer          `json:"answers"`
+	Gratitude  []GratitudeItem   `json:"gratitude"`
+	Creativity []CreativityEntry `json:"creativity"`
+}
+
+// ActivityByDate returns the answers, gratitude items and creativity entries
+// written between from and to (inclusive, YYYY-MM-DD), grouped by date in
+// ascending order. An empty from or to leaves that end of the range open.
+func (s *Stores) ActivityByDate(from string, to string) ([]DayActivity, error) {
+	days := map[string]*DayActivity{}
+	day := func(date string) *DayActivity {
+		d, ok := days[date]
+		if !ok {
+			d = &DayActivity{Date: date}
+			days[date] = d
+		}
+		return d
+	}
+	inRange := func(date string) bool {
+		return (from == "" || date >= from) && (to == "" || date <= to)
+	}
+
+	// Answers have no entry date, so they are grouped by their local creation date
+	answers, err := s.Answers.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(answers) - 1; i >= 0; i-- {
+		date := answers[i].CreatedAt.In(time.Local).Format("2006-01-02")
+		if inRange(date) {
+			d := day(date)
+			d.Answers = append(d.Answers, answers[i])
+		}
+	}
+
+	gratitude, err := s.Gratitude.GetAllEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range gratitude {
+		if inRange(entry.Date) {
+			d := day(entry.Date)
+			d.Gratitude = append(d.Gratitude, entry.Items...)
+		}
+	}
+
+	entries, err := s.Creativity.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if inRange(entries[i].EntryDate) {
+			d := day(entries[i].EntryDate)
+			d.Creativity = append(d.Creativity, entries[i])
+		}
+	}
+
+	result := make([]DayActivity, 0, len(days))
+	for _, d := range days {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+
+	return result, nil
+}
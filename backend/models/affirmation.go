@@ -2,28 +2,56 @@
 package models
 
 import (
+	"database/sql"
 	"time"
 
-	"myproject/backend/database"
+	"myproject/backend/events"
+	"myproject/backend/ids"
 )
 
 type Affirmation struct {
 	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
-// GetActiveAffirmation gets the most recently created affirmation
-func GetActiveAffirmation() (*Affirmation, error) {
+// AffirmationStore manages affirmations and their completion logs
+type AffirmationStore interface {
+	GetActive() (*Affirmation, error)
+	Save(content string) (*Affirmation, error)
+	Update(id int64, content string) error
+	Delete(id int64) error
+	DeleteLog(id int64) error
+	LogCompletion(affirmationID int64) error
+	CheckToday(affirmationID int64) (bool, error)
+	CheckDate(date string) (bool, error)
+	GetStreak() (int, error)
+	GetAll() ([]Affirmation, error)
+	GetAllLogs() ([]AffirmationLog, error)
+}
+
+type sqlAffirmationStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewAffirmationStore creates a AffirmationStore backed by db
+func NewAffirmationStore(db *sql.DB, bus *events.Bus) AffirmationStore {
+	return &sqlAffirmationStore{db: db, bus: bus}
+}
+
+// GetActive gets the most recently created affirmation
+func (s *sqlAffirmationStore) GetActive() (*Affirmation, error) {
 	var affirmation Affirmation
 
-	err := database.DB.QueryRow(`
-		SELECT id, content, created_at, updated_at 
+	err := s.db.QueryRow(`
+		SELECT id, uuid, content, created_at, updated_at 
 		FROM affirmations 
 		ORDER BY created_at DESC 
 		LIMIT 1`).Scan(
-		&affirmation.ID, &affirmation.Content, &affirmation.CreatedAt, &affirmation.UpdatedAt)
+		&affirmation.ID, &affirmation.UUID, &affirmation.Content, &affirmation.CreatedAt, &affirmation.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -32,14 +60,15 @@ func GetActiveAffirmation() (*Affirmation, error) {
 	return &affirmation, nil
 }
 
-// SaveAffirmation creates or updates the active affirmation
-func SaveAffirmation(content string) (*Affirmation, error) {
+// Save creates or updates the active affirmation
+func (s *sqlAffirmationStore) Save(content string) (*Affirmation, error) {
 	now := time.Now()
+	uuid := ids.New()
 
 	// We'll create a new affirmation record each time
-	res, err := database.DB.Exec(`
-		INSERT INTO affirmations (content, created_at, updated_at) 
-		VALUES (?, ?, ?)`, content, now, now)
+	res, err := s.db.Exec(`
+		INSERT INTO affirmations (uuid, content, created_at, updated_at) 
+		VALUES (?, ?, ?, ?)`, uuid, content, now, now)
 
 	if err != nil {
 		return nil, err
@@ -50,102 +79,142 @@ func SaveAffirmation(content string) (*Affirmation, error) {
 		return nil, err
 	}
 
+	if err := indexEntry(s.db, EntryAffirmation, id); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AffirmationCreated, ID: id})
+
 	return &Affirmation{
 		ID:        id,
+		UUID:      uuid,
 		Content:   content,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
 }
 
-// LogAffirmationCompletion records that the user completed their affirmation
-func LogAffirmationCompletion(affirmationID int64) error {
-	_, err := database.DB.Exec(`
-		INSERT INTO affirmation_logs (affirmation_id, completed_at) 
-		VALUES (?, datetime('now', 'localtime'))`, affirmationID)
+// Update updates an affirmation in the database
+func (s *sqlAffirmationStore) Update(id int64, content string) error {
+	now := time.Now()
+	_, err := s.db.Exec(`
+		UPDATE affirmations 
+		SET content = ?, updated_at = ? 
+		WHERE id = ?`, content, now, id)
+	if err != nil {
+		return err
+	}
+	if err := indexEntry(s.db, EntryAffirmation, id); err != nil {
+		return err
+	}
 
-	return err
+	s.bus.Publish(events.Event{Type: events.AffirmationUpdated, ID: id})
+	return nil
 }
 
-// CheckTodayAffirmation checks if the affirmation was completed today
-func CheckTodayAffirmation(affirmationID int64) (bool, error) {
-	var count int
-
-	// Use the local timezone date for today's comparison
-	today := time.Now().Format("2006-01-02")
+// Delete deletes an affirmation and its associated logs
+func (s *sqlAffirmationStore) Delete(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
 
-	err := database.DB.QueryRow(`
-		SELECT COUNT(*) 
-		FROM affirmation_logs 
-		WHERE date(completed_at, 'localtime') = ?`, today).Scan(&count)
+	// Delete associated logs first
+	_, err = tx.Exec(`DELETE FROM affirmation_logs WHERE affirmation_id = ?`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
+	// Delete the affirmation
+	_, err = tx.Exec(`DELETE FROM affirmations WHERE id = ?`, id)
 	if err != nil {
-		return false, err
+		tx.Rollback()
+		return err
 	}
 
-	return count > 0, nil
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AffirmationDeleted, ID: id})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "affirmation"})
+	return nil
 }
 
-// GetAffirmationStreak returns the current streak of consecutive days
-func GetAffirmationStreak() (int, error) {
-	// This is a simplified version - a more robust implementation would
-	// handle gaps in the streak better
-	var streak int
+// DeleteLog deletes an affirmation log from the database
+func (s *sqlAffirmationStore) DeleteLog(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM affirmation_logs WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AffirmationLogDeleted, ID: id})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "affirmation"})
+	return nil
+}
 
-	rows, err := database.DB.Query(`
-		SELECT date(completed_at, 'localtime') as log_date
-		FROM affirmation_logs
-		GROUP BY log_date
-		ORDER BY log_date DESC`)
+// LogCompletion records that the user completed their affirmation
+func (s *sqlAffirmationStore) LogCompletion(affirmationID int64) error {
+	res, err := s.db.Exec(`
+		INSERT INTO affirmation_logs (uuid, affirmation_id, completed_at) 
+		VALUES (?, ?, datetime('now', 'localtime'))`, ids.New(), affirmationID)
 
 	if err != nil {
-		return 0, err
+		return err
 	}
-	defer rows.Close()
 
-	var dates []string
-	for rows.Next() {
-		var date string
-		err := rows.Scan(&date)
-		if err != nil {
-			return 0, err
-		}
-		dates = append(dates, date)
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
 	}
 
-	// Count consecutive days
-	if len(dates) == 0 {
-		return 0, nil
-	}
+	s.bus.Publish(events.Event{Type: events.AffirmationLogged, ID: id, Date: time.Now().Format("2006-01-02")})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "affirmation"})
+	return nil
+}
 
-	streak = 1
-	today := time.Now()
+// CheckToday checks if the affirmation was completed today
+func (s *sqlAffirmationStore) CheckToday(affirmationID int64) (bool, error) {
+	// Use the local timezone date for today's comparison
+	return s.CheckDate(time.Now().Format("2006-01-02"))
+}
 
-	for i := 0; i < len(dates); i++ {
-		logDate, err := time.Parse("2006-01-02", dates[i])
-		if err != nil {
-			return 0, err
-		}
+// CheckDate checks if an affirmation was completed on a date (YYYY-MM-DD)
+func (s *sqlAffirmationStore) CheckDate(date string) (bool, error) {
+	var count int
 
-		expectedDate := today.AddDate(0, 0, -i)
-		expectedDateStr := expectedDate.Format("2006-01-02")
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) 
+		FROM affirmation_logs 
+		WHERE date(completed_at, 'localtime') = ?`, date).Scan(&count)
 
-		if logDate.Format("2006-01-02") == expectedDateStr {
-			if i > 0 {
-				streak++
-			}
-		} else {
-			break
-		}
+	if err != nil {
+		return false, err
 	}
 
-	return streak, nil
+	return count > 0, nil
+}
+
+// GetStreak returns the current streak of the built-in affirmation habit:
+// consecutive days completed by default
+func (s *sqlAffirmationStore) GetStreak() (int, error) {
+	habit, err := scanHabit(s.db.QueryRow(`SELECT `+habitColumns+` FROM habits WHERE builtin = ?`, BuiltinAffirmation))
+	if err != nil {
+		return 0, err
+	}
+
+	stats, err := habitStats(s.db, habit, DateRange{}, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return stats.CurrentStreak, nil
 }
 
-// GetAllAffirmations retrieves all affirmations from the database
-func GetAllAffirmations() ([]Affirmation, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, content, created_at, updated_at 
+// GetAll retrieves all affirmations from the database
+func (s *sqlAffirmationStore) GetAll() ([]Affirmation, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, content, created_at, updated_at 
 		FROM affirmations 
 		ORDER BY created_at DESC`)
 
@@ -157,7 +226,7 @@ func GetAllAffirmations() ([]Affirmation, error) {
 	var affirmations []Affirmation
 	for rows.Next() {
 		var a Affirmation
-		err := rows.Scan(&a.ID, &a.Content, &a.CreatedAt, &a.UpdatedAt)
+		err := rows.Scan(&a.ID, &a.UUID, &a.Content, &a.CreatedAt, &a.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -170,14 +239,15 @@ func GetAllAffirmations() ([]Affirmation, error) {
 // Add a new type for AffirmationLog
 type AffirmationLog struct {
 	ID            int64     `json:"id"`
+	UUID          string    `json:"uuid"`
 	AffirmationID int64     `json:"affirmationId"`
 	CompletedAt   time.Time `json:"completedAt"`
 }
 
-// GetAllAffirmationLogs retrieves all affirmation logs from the database
-func GetAllAffirmationLogs() ([]AffirmationLog, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, affirmation_id, completed_at 
+// GetAllLogs retrieves all affirmation logs from the database
+func (s *sqlAffirmationStore) GetAllLogs() ([]AffirmationLog, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, affirmation_id, completed_at 
 		FROM affirmation_logs 
 		ORDER BY completed_at DESC`)
 
@@ -189,7 +259,7 @@ func GetAllAffirmationLogs() ([]AffirmationLog, error) {
 	var logs []AffirmationLog
 	for rows.Next() {
 		var log AffirmationLog
-		err := rows.Scan(&log.ID, &log.AffirmationID, &log.CompletedAt)
+		err := rows.Scan(&log.ID, &log.UUID, &log.AffirmationID, &log.CompletedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -2,35 +2,22 @@
 package models
 
 import (
-	"myproject/backend/database"
-	"os"
 	"testing"
 	"time"
+
+	"myproject/backend/ids"
 )
 
 func TestAffirmationModel(t *testing.T) {
-	// Set up test database
-	testDB := "./test.db"
-
-	// Clean up any existing test database
-	os.Remove(testDB)
-
-	// Initialize test database
-	err := database.Initialize(testDB)
-	if err != nil {
-		t.Fatalf("Failed to initialize test database: %v", err)
-	}
+	t.Parallel()
 
-	// Clean up after test
-	defer func() {
-		database.Close()
-		os.Remove(testDB)
-	}()
+	// Set up an in-memory test database
+	stores, _ := newTestStores(t)
 
 	// Test SaveAffirmation
 	t.Run("SaveAffirmation", func(t *testing.T) {
 		content := "I am capable of achieving my goals"
-		affirmation, err := SaveAffirmation(content)
+		affirmation, err := stores.Affirmations.Save(content)
 
 		if err != nil {
 			t.Fatalf("Failed to save affirmation: %v", err)
@@ -48,12 +35,12 @@ func TestAffirmationModel(t *testing.T) {
 	// Test GetActiveAffirmation
 	t.Run("GetActiveAffirmation", func(t *testing.T) {
 		expectedContent := "I am worthy of love and respect"
-		_, err := SaveAffirmation(expectedContent)
+		_, err := stores.Affirmations.Save(expectedContent)
 		if err != nil {
 			t.Fatalf("Failed to save affirmation: %v", err)
 		}
 
-		affirmation, err := GetActiveAffirmation()
+		affirmation, err := stores.Affirmations.GetActive()
 		if err != nil {
 			t.Fatalf("Failed to get active affirmation: %v", err)
 		}
@@ -65,13 +52,13 @@ func TestAffirmationModel(t *testing.T) {
 
 	// Test LogAffirmationCompletion and CheckTodayAffirmation
 	t.Run("AffirmationLogging", func(t *testing.T) {
-		affirmation, err := SaveAffirmation("Test affirmation")
+		affirmation, err := stores.Affirmations.Save("Test affirmation")
 		if err != nil {
 			t.Fatalf("Failed to save affirmation: %v", err)
 		}
 
 		// Check before logging
-		completed, err := CheckTodayAffirmation(affirmation.ID)
+		completed, err := stores.Affirmations.CheckToday(affirmation.ID)
 		if err != nil {
 			t.Fatalf("Failed to check today's affirmation: %v", err)
 		}
@@ -81,13 +68,13 @@ func TestAffirmationModel(t *testing.T) {
 		}
 
 		// Log completion
-		err = LogAffirmationCompletion(affirmation.ID)
+		err = stores.Affirmations.LogCompletion(affirmation.ID)
 		if err != nil {
 			t.Fatalf("Failed to log affirmation completion: %v", err)
 		}
 
 		// Check after logging
-		completed, err = CheckTodayAffirmation(affirmation.ID)
+		completed, err = stores.Affirmations.CheckToday(affirmation.ID)
 		if err != nil {
 			t.Fatalf("Failed to check today's affirmation: %v", err)
 		}
@@ -95,36 +82,45 @@ func TestAffirmationModel(t *testing.T) {
 		if !completed {
 			t.Errorf("Expected affirmation to be completed")
 		}
+
+		// Other days are checked by date
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		completed, err = stores.Affirmations.CheckDate(yesterday)
+		if err != nil {
+			t.Fatalf("Failed to check affirmation for %s: %v", yesterday, err)
+		}
+
+		if completed {
+			t.Errorf("Expected affirmation not completed on %s", yesterday)
+		}
 	})
 
 	// Test GetAffirmationStreak
 	t.Run("GetAffirmationStreak", func(t *testing.T) {
-		// Reset DB for this test
-		database.Close()
-		os.Remove(testDB)
-		database.Initialize(testDB)
+		// Use a fresh database
+		stores, db := newTestStores(t)
 
-		affirmation, err := SaveAffirmation("Streak test")
+		affirmation, err := stores.Affirmations.Save("Streak test")
 		if err != nil {
 			t.Fatalf("Failed to save affirmation: %v", err)
 		}
 
 		// Log today
-		err = LogAffirmationCompletion(affirmation.ID)
+		err = stores.Affirmations.LogCompletion(affirmation.ID)
 		if err != nil {
 			t.Fatalf("Failed to log affirmation: %v", err)
 		}
 
 		// Insert a record for yesterday manually
 		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-		_, err = database.DB.Exec(`
-			INSERT INTO affirmation_logs (affirmation_id, completed_at) 
-			VALUES (?, ?)`, affirmation.ID, yesterday)
+		_, err = db.Exec(`
+			INSERT INTO affirmation_logs (uuid, affirmation_id, completed_at) 
+			VALUES (?, ?, ?)`, ids.New(), affirmation.ID, yesterday)
 		if err != nil {
 			t.Fatalf("Failed to insert yesterday's log: %v", err)
 		}
 
-		streak, err := GetAffirmationStreak()
+		streak, err := stores.Affirmations.GetStreak()
 		if err != nil {
 			t.Fatalf("Failed to get streak: %v", err)
 		}
@@ -138,20 +134,20 @@ func TestAffirmationModel(t *testing.T) {
 	t.Run("AffirmationCRUD", func(t *testing.T) {
 		// Create
 		content := "Original affirmation"
-		affirmation, err := SaveAffirmation(content)
+		affirmation, err := stores.Affirmations.Save(content)
 		if err != nil {
 			t.Fatalf("Failed to save affirmation: %v", err)
 		}
 
 		// Update
 		newContent := "Updated affirmation"
-		err = UpdateAffirmation(affirmation.ID, newContent)
+		err = stores.Affirmations.Update(affirmation.ID, newContent)
 		if err != nil {
 			t.Fatalf("Failed to update affirmation: %v", err)
 		}
 
 		// Get to verify update
-		affirmations, err := GetAllAffirmations()
+		affirmations, err := stores.Affirmations.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get affirmations: %v", err)
 		}
@@ -172,13 +168,13 @@ func TestAffirmationModel(t *testing.T) {
 		}
 
 		// Delete
-		err = DeleteAffirmation(affirmation.ID)
+		err = stores.Affirmations.Delete(affirmation.ID)
 		if err != nil {
 			t.Fatalf("Failed to delete affirmation: %v", err)
 		}
 
 		// Verify deletion
-		affirmations, err = GetAllAffirmations()
+		affirmations, err = stores.Affirmations.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get affirmations: %v", err)
 		}
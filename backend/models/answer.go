@@ -2,33 +2,67 @@
 package models
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
-	"myproject/backend/database"
+	"myproject/backend/events"
+	"myproject/backend/ids"
+	"myproject/backend/sentiment"
+	"myproject/backend/textstats"
 )
 
 type Answer struct {
-	ID         int64     `json:"id"`
-	QuestionID int64     `json:"questionId"`
-	Content    string    `json:"content"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID         int64        `json:"id"`
+	UUID       string       `json:"uuid"`
+	QuestionID int64        `json:"questionId"`
+	Content    string       `json:"content"`
+	Fields     []FieldValue `json:"fields,omitempty"` // Values for the question's template, rendered into Content
+	CreatedAt  time.Time    `json:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
 }
 
 // AnswerHistory combines the answer with the date it was created
 type AnswerHistory struct {
-	ID         int64     `json:"id"`
-	QuestionID int64     `json:"questionId"`
-	Content    string    `json:"content"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID         int64        `json:"id"`
+	UUID       string       `json:"uuid"`
+	QuestionID int64        `json:"questionId"`
+	Content    string       `json:"content"`
+	Fields     []FieldValue `json:"fields,omitempty"` // Values for the question's template, rendered into Content
+	CreatedAt  time.Time    `json:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
 }
 
-// GetAnswerHistoryByQuestionID retrieves all answers for a specific question
-func GetAnswerHistoryByQuestionID(questionID int64) ([]AnswerHistory, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, question_id, content, created_at, updated_at 
+// AnswerStore manages answers to questions
+type AnswerStore interface {
+	GetHistoryByQuestionID(questionID int64) ([]AnswerHistory, error)
+	Create(questionID int64, content string) (*Answer, error)
+	CreateStructured(questionID int64, fields []FieldValue) (*Answer, error)
+	GetAll() ([]Answer, error)
+	GetRecent(daysRange int) ([]Answer, error)
+	HasForDate(date string) (bool, error)
+	Update(id int64, content string) error
+	UpdateStructured(id int64, fields []FieldValue) error
+	Delete(id int64) error
+}
+
+type sqlAnswerStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewAnswerStore creates a AnswerStore backed by db
+func NewAnswerStore(db *sql.DB, bus *events.Bus) AnswerStore {
+	return &sqlAnswerStore{db: db, bus: bus}
+}
+
+// GetHistoryByQuestionID retrieves all answers for a specific question
+func (s *sqlAnswerStore) GetHistoryByQuestionID(questionID int64) ([]AnswerHistory, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, question_id, content, fields, created_at, updated_at 
 		FROM answers 
 		WHERE question_id = ? 
 		ORDER BY created_at DESC`, questionID)
@@ -41,11 +75,15 @@ func GetAnswerHistoryByQuestionID(questionID int64) ([]AnswerHistory, error) {
 	var answers []AnswerHistory
 	for rows.Next() {
 		var a AnswerHistory
+		var fields string
 
-		err := rows.Scan(&a.ID, &a.QuestionID, &a.Content, &a.CreatedAt, &a.UpdatedAt)
+		err := rows.Scan(&a.ID, &a.UUID, &a.QuestionID, &a.Content, &fields, &a.CreatedAt, &a.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
+		if a.Fields, err = decodeFields(fields); err != nil {
+			return nil, err
+		}
 
 		answers = append(answers, a)
 	}
@@ -53,15 +91,53 @@ func GetAnswerHistoryByQuestionID(questionID int64) ([]AnswerHistory, error) {
 	return answers, nil
 }
 
-// CreateNewAnswer creates a new answer entry
-func CreateNewAnswer(questionID int64, content string) (*Answer, error) {
-	now := time.Now()
+// Create creates a new answer entry
+func (s *sqlAnswerStore) Create(questionID int64, content string) (*Answer, error) {
+	return s.create(questionID, content, nil)
+}
 
-	// Create new answer
-	res, err := database.DB.Exec(`
-		INSERT INTO answers (question_id, content, created_at, updated_at) 
-		VALUES (?, ?, ?, ?)`, questionID, content, now, now)
+// CreateStructured creates an answer from values for its question's
+// template, keeping the values rendered as Markdown as its content
+func (s *sqlAnswerStore) CreateStructured(questionID int64, fields []FieldValue) (*Answer, error) {
+	content, checked, err := structure(s.db, questionID, fields)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(questionID, content, checked)
+}
+
+func (s *sqlAnswerStore) create(questionID int64, content string, fields []FieldValue) (*Answer, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	answer, err := createAnswer(tx, questionID, content, fields, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AnswerCreated, ID: answer.ID, Date: answer.CreatedAt.Format("2006-01-02")})
+	return answer, nil
+}
+
+// createAnswer writes and indexes an answer within tx. Every way of adding
+// an answer goes through it, so they all store the same values.
+func createAnswer(tx *sql.Tx, questionID int64, content string, fields []FieldValue, createdAt time.Time) (*Answer, error) {
+	data, err := encodeFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	uuid := ids.New()
+	words, chars := textstats.Count(content)
+	res, err := tx.Exec(`
+		INSERT INTO answers (uuid, question_id, content, fields, word_count, char_count, sentiment, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, uuid, questionID, content, data, words, chars, sentiment.Score(content), createdAt, createdAt)
 	if err != nil {
 		return nil, err
 	}
@@ -70,22 +146,61 @@ func CreateNewAnswer(questionID int64, content string) (*Answer, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := indexEntryTx(tx, EntryAnswer, id); err != nil {
+		return nil, err
+	}
 
-	result := Answer{
+	return &Answer{
 		ID:         id,
+		UUID:       uuid,
 		QuestionID: questionID,
 		Content:    content,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		Fields:     fields,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}, nil
+}
+
+// structure checks values against a question's template, returning them
+// rendered as Markdown along with the checked values
+func structure(q querier, questionID int64, fields []FieldValue) (string, []FieldValue, error) {
+	var stored string
+	err := q.QueryRow(`SELECT template FROM questions WHERE id = ?`, questionID).Scan(&stored)
+	if err != nil {
+		return "", nil, err
+	}
+	template, err := decodeTemplate(stored)
+	if err != nil {
+		return "", nil, err
+	}
+	if template == nil {
+		return "", nil, fmt.Errorf("question %d has no template", questionID)
 	}
 
-	return &result, nil
+	checked, err := template.Check(fields)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(checked) == 0 {
+		return "", nil, errors.New("the answer is empty")
+	}
+	return template.Render(checked), checked, nil
 }
 
-// GetAllAnswers retrieves all answers from the database
-func GetAllAnswers() ([]Answer, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, question_id, content, created_at, updated_at 
+// encodeFields encodes field values for storing, as empty for answers
+// without them
+func encodeFields(fields []FieldValue) (string, error) {
+	if fields == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(fields)
+	return string(data), err
+}
+
+// GetAll retrieves all answers from the database
+func (s *sqlAnswerStore) GetAll() ([]Answer, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, question_id, content, fields, created_at, updated_at 
 		FROM answers 
 		ORDER BY created_at DESC`)
 
@@ -97,32 +212,35 @@ func GetAllAnswers() ([]Answer, error) {
 	var answers []Answer
 	for rows.Next() {
 		var a Answer
-		err := rows.Scan(&a.ID, &a.QuestionID, &a.Content, &a.CreatedAt, &a.UpdatedAt)
+		var fields string
+		err := rows.Scan(&a.ID, &a.UUID, &a.QuestionID, &a.Content, &fields, &a.CreatedAt, &a.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
+		if a.Fields, err = decodeFields(fields); err != nil {
+			return nil, err
+		}
 		answers = append(answers, a)
 	}
 
 	return answers, nil
 }
 
-// GetRecentAnswers retrieves answers from the last few days
-func GetRecentAnswers(daysRange int) ([]Answer, error) {
+// GetRecent retrieves answers from the last few days
+func (s *sqlAnswerStore) GetRecent(daysRange int) ([]Answer, error) {
 	// Get answers from the past daysRange days
 	// Using string formatting is safe here since daysRange is an integer
 	query := fmt.Sprintf(`
-		SELECT id, question_id, content, created_at, updated_at 
+		SELECT id, uuid, question_id, content, fields, created_at, updated_at 
 		FROM answers 
 		WHERE created_at >= datetime('now', '-%d days')
 		ORDER BY created_at DESC`, daysRange)
 
-	println("Executing query for recent answers with range:", daysRange, "days")
-	println("Query:", query)
+	slog.Debug("querying recent answers", "days", daysRange)
 
-	rows, err := database.DB.Query(query)
+	rows, err := s.db.Query(query)
 	if err != nil {
-		println("Error querying recent answers:", err.Error())
+		slog.Error("querying recent answers", "days", daysRange, "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -130,9 +248,13 @@ func GetRecentAnswers(daysRange int) ([]Answer, error) {
 	var answers []Answer
 	for rows.Next() {
 		var a Answer
-		err := rows.Scan(&a.ID, &a.QuestionID, &a.Content, &a.CreatedAt, &a.UpdatedAt)
+		var fields string
+		err := rows.Scan(&a.ID, &a.UUID, &a.QuestionID, &a.Content, &fields, &a.CreatedAt, &a.UpdatedAt)
 		if err != nil {
-			println("Error scanning answer row:", err.Error())
+			slog.Error("scanning recent answer", "error", err)
+			return nil, err
+		}
+		if a.Fields, err = decodeFields(fields); err != nil {
 			return nil, err
 		}
 		answers = append(answers, a)
@@ -140,27 +262,104 @@ func GetRecentAnswers(daysRange int) ([]Answer, error) {
 
 	// Check for errors during iteration
 	if err = rows.Err(); err != nil {
-		println("Error iterating through answer rows:", err.Error())
+		slog.Error("iterating recent answers", "error", err)
 		return nil, err
 	}
 
-	println("Successfully retrieved", len(answers), "recent answers")
+	slog.Debug("retrieved recent answers", "count", len(answers))
 	return answers, nil
 }
 
-// GetQuestionById retrieves a specific question by its ID
-func GetQuestionById(id int64) (*Question, error) {
-	var question Question
+// HasForDate checks if any answer was written on the given local date (YYYY-MM-DD)
+func (s *sqlAnswerStore) HasForDate(date string) (bool, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return false, err
+	}
+
+	// Answers only carry a timestamp, so look at the day's neighbours
+	// and compare local dates in Go
+	rows, err := s.db.Query(`
+		SELECT created_at 
+		FROM answers 
+		WHERE created_at >= ? AND created_at < ?`,
+		day.AddDate(0, 0, -1).Format("2006-01-02"), day.AddDate(0, 0, 2).Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var createdAt time.Time
+		if err := rows.Scan(&createdAt); err != nil {
+			return false, err
+		}
+		if createdAt.In(time.Local).Format("2006-01-02") == date {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
+
+// Update updates an answer in the database. Any template values are
+// dropped, as they no longer match the content.
+func (s *sqlAnswerStore) Update(id int64, content string) error {
+	now := time.Now()
+	words, chars := textstats.Count(content)
+	_, err := s.db.Exec(`
+		UPDATE answers 
+		SET content = ?, fields = '', word_count = ?, char_count = ?, sentiment = ?, updated_at = ? 
+		WHERE id = ?`, content, words, chars, sentiment.Score(content), now, id)
+	if err != nil {
+		return err
+	}
+	if err := indexEntry(s.db, EntryAnswer, id); err != nil {
+		return err
+	}
 
-	err := database.DB.QueryRow(`
-		SELECT id, content, created_at 
-		FROM questions 
-		WHERE id = ?`, id).Scan(
-		&question.ID, &question.Content, &question.CreatedAt)
+	s.bus.Publish(events.Event{Type: events.AnswerUpdated, ID: id})
+	return nil
+}
 
+// UpdateStructured updates an answer's values for its question's template
+func (s *sqlAnswerStore) UpdateStructured(id int64, fields []FieldValue) error {
+	var questionID int64
+	if err := s.db.QueryRow(`SELECT question_id FROM answers WHERE id = ?`, id).Scan(&questionID); err != nil {
+		return err
+	}
+	content, checked, err := structure(s.db, questionID, fields)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	data, err := encodeFields(checked)
+	if err != nil {
+		return err
+	}
+
+	words, chars := textstats.Count(content)
+	_, err = s.db.Exec(`
+		UPDATE answers 
+		SET content = ?, fields = ?, word_count = ?, char_count = ?, sentiment = ?, updated_at = ? 
+		WHERE id = ?`, content, data, words, chars, sentiment.Score(content), time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if err := indexEntry(s.db, EntryAnswer, id); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.AnswerUpdated, ID: id})
+	return nil
+}
+
+// Delete deletes an answer from the database
+func (s *sqlAnswerStore) Delete(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM answers WHERE id = ?`, id)
+	if err != nil {
+		return err
 	}
 
-	return &question, nil
+	s.bus.Publish(events.Event{Type: events.AnswerDeleted, ID: id})
+	return nil
 }
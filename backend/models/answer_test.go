@@ -2,34 +2,20 @@
 package models
 
 import (
-	"myproject/backend/database"
-	"os"
+	"myproject/backend/events"
 	"testing"
 )
 
 func TestAnswerModel(t *testing.T) {
-	// Set up test database
-	testDB := "./test_answer.db"
+	t.Parallel()
 
-	// Clean up any existing test database
-	os.Remove(testDB)
-
-	// Initialize test database
-	err := database.Initialize(testDB)
-	if err != nil {
-		t.Fatalf("Failed to initialize test database: %v", err)
-	}
-
-	// Clean up after test
-	defer func() {
-		database.Close()
-		os.Remove(testDB)
-	}()
+	// Set up an in-memory test database
+	stores, db := newTestStores(t)
 
 	// Create a question to use for answers
 	var questionID int64
 	t.Run("Setup", func(t *testing.T) {
-		question, err := AddQuestion("Test question for answers")
+		question, err := stores.Questions.Add("Test question for answers")
 		if err != nil {
 			t.Fatalf("Failed to create test question: %v", err)
 		}
@@ -39,7 +25,7 @@ func TestAnswerModel(t *testing.T) {
 	// Test CreateNewAnswer
 	t.Run("CreateNewAnswer", func(t *testing.T) {
 		content := "This is my answer to the question"
-		answer, err := CreateNewAnswer(questionID, content)
+		answer, err := stores.Answers.Create(questionID, content)
 
 		if err != nil {
 			t.Fatalf("Failed to create answer: %v", err)
@@ -68,14 +54,14 @@ func TestAnswerModel(t *testing.T) {
 		}
 
 		for _, a := range expectedAnswers {
-			_, err := CreateNewAnswer(questionID, a)
+			_, err := stores.Answers.Create(questionID, a)
 			if err != nil {
 				t.Fatalf("Failed to create answer: %v", err)
 			}
 		}
 
 		// Get answer history
-		answers, err := GetAnswerHistoryByQuestionID(questionID)
+		answers, err := stores.Answers.GetHistoryByQuestionID(questionID)
 		if err != nil {
 			t.Fatalf("Failed to get answer history: %v", err)
 		}
@@ -104,20 +90,20 @@ func TestAnswerModel(t *testing.T) {
 	// Test GetAllAnswers
 	t.Run("GetAllAnswers", func(t *testing.T) {
 		// Create a new question
-		newQuestion, err := AddQuestion("Another test question")
+		newQuestion, err := stores.Questions.Add("Another test question")
 		if err != nil {
 			t.Fatalf("Failed to create question: %v", err)
 		}
 
 		// Add answer to the new question
 		newContent := "Answer to another question"
-		_, err = CreateNewAnswer(newQuestion.ID, newContent)
+		_, err = stores.Answers.Create(newQuestion.ID, newContent)
 		if err != nil {
 			t.Fatalf("Failed to create answer: %v", err)
 		}
 
 		// Get all answers
-		answers, err := GetAllAnswers()
+		answers, err := stores.Answers.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get all answers: %v", err)
 		}
@@ -145,20 +131,20 @@ func TestAnswerModel(t *testing.T) {
 	t.Run("AnswerCRUD", func(t *testing.T) {
 		// Create
 		content := "Original answer"
-		answer, err := CreateNewAnswer(questionID, content)
+		answer, err := stores.Answers.Create(questionID, content)
 		if err != nil {
 			t.Fatalf("Failed to create answer: %v", err)
 		}
 
 		// Update
 		newContent := "Updated answer"
-		err = UpdateAnswer(answer.ID, newContent)
+		err = stores.Answers.Update(answer.ID, newContent)
 		if err != nil {
 			t.Fatalf("Failed to update answer: %v", err)
 		}
 
 		// Get all to verify update
-		answers, err := GetAllAnswers()
+		answers, err := stores.Answers.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get answers: %v", err)
 		}
@@ -179,13 +165,13 @@ func TestAnswerModel(t *testing.T) {
 		}
 
 		// Delete
-		err = DeleteAnswer(answer.ID)
+		err = stores.Answers.Delete(answer.ID)
 		if err != nil {
 			t.Fatalf("Failed to delete answer: %v", err)
 		}
 
 		// Verify deletion
-		answers, err = GetAllAnswers()
+		answers, err = stores.Answers.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get answers: %v", err)
 		}
@@ -197,4 +183,38 @@ func TestAnswerModel(t *testing.T) {
 			}
 		}
 	})
+
+	// Test that changes are published on the event bus
+	t.Run("PublishesEvents", func(t *testing.T) {
+		bus := events.NewBus()
+		answers := NewAnswerStore(db, bus)
+
+		var received []events.Event
+		unsubscribe := bus.Subscribe(func(e events.Event) {
+			received = append(received, e)
+		})
+		defer unsubscribe()
+
+		answer, err := answers.Create(questionID, "Answer with events")
+		if err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+
+		err = answers.Delete(answer.ID)
+		if err != nil {
+			t.Fatalf("Failed to delete answer: %v", err)
+		}
+
+		if len(received) != 2 {
+			t.Fatalf("Expected 2 events, got %d", len(received))
+		}
+
+		if received[0].Type != events.AnswerCreated || received[0].ID != answer.ID {
+			t.Errorf("Expected answer.created for %d, got %+v", answer.ID, received[0])
+		}
+
+		if received[1].Type != events.AnswerDeleted || received[1].ID != answer.ID {
+			t.Errorf("Expected answer.deleted for %d, got %+v", answer.ID, received[1])
+		}
+	})
 }
@@ -2,137 +2,416 @@
 package models
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
-	"myproject/backend/database"
+	"myproject/backend/events"
+	"myproject/backend/ids"
+	"myproject/backend/sentiment"
+	"myproject/backend/textstats"
 )
 
+// Kinds of creativity entry. An empty kind means none was chosen.
+const (
+	CreativityPoem        = "poem"
+	CreativityStory       = "story"
+	CreativitySketchNotes = "sketch_notes"
+	CreativityList        = "list"
+)
+
+// CreativityOnePerDaySetting is the setting that limits the journal to one
+// entry per day
+const CreativityOnePerDaySetting = "creativity.one_per_day"
+
+// ErrCreativityEntryExists is returned in one-per-day mode when the date
+// already has an entry
+var ErrCreativityEntryExists = errors.New("there is already a creativity entry for this date")
+
 type CreativityEntry struct {
 	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
+	Title     string    `json:"title"`
+	Kind      string    `json:"kind"` // "poem", "story", "sketch_notes", "list" or empty
 	Content   string    `json:"content"`
 	EntryDate string    `json:"entryDate"` // Store the date in YYYY-MM-DD format
+	PromptID  *int64    `json:"promptId"`  // The prompt the entry responds to, if any
+	WordCount int       `json:"wordCount"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
-// SaveCreativityEntry creates or updates a creativity journal entry for a specific date
-func SaveCreativityEntry(content string, entryDate string) (*CreativityEntry, error) {
-	// Check if an entry already exists for this date
-	var existingID int64
-	var existingCount int
+// CreativityStore manages creativity journal entries
+type CreativityStore interface {
+	Create(entry CreativityEntry) (*CreativityEntry, error)
+	Save(content string, entryDate string) (*CreativityEntry, error)
+	SaveForPrompt(content string, entryDate string, promptID *int64) (*CreativityEntry, error)
+	GetByID(id int64) (*CreativityEntry, error)
+	GetByDate(entryDate string) ([]CreativityEntry, error)
+	GetAll() ([]CreativityEntry, error)
+	GetByPrompt(promptID int64) ([]CreativityEntry, error)
+	Edit(entry CreativityEntry) error
+	Update(id int64, content string) error
+	Delete(id int64) error
+	HasForDate(entryDate string) (bool, error)
+	GetStreak() (int, error)
+	OnePerDay() (bool, error)
+	SetOnePerDay(enabled bool) error
+}
 
-	err := database.DB.QueryRow(`
-		SELECT COUNT(*), id FROM creativity_entries 
-		WHERE entry_date = ? 
-		LIMIT 1`, entryDate).Scan(&existingCount, &existingID)
+type sqlCreativityStore struct {
+	db       *sql.DB
+	bus      *events.Bus
+	settings SettingsStore
+}
 
-	now := time.Now()
+// NewCreativityStore creates a CreativityStore backed by db
+func NewCreativityStore(db *sql.DB, bus *events.Bus) CreativityStore {
+	return &sqlCreativityStore{db: db, bus: bus, settings: NewSettingsStore(db)}
+}
 
-	if err != nil || existingCount == 0 {
-		// Create a new entry
-		res, err := database.DB.Exec(`
-			INSERT INTO creativity_entries (content, entry_date, created_at, updated_at) 
-			VALUES (?, ?, ?, ?)`, content, entryDate, now, now)
+const creativityColumns = `id, uuid, title, kind, content, entry_date, prompt_id, word_count, created_at, updated_at`
 
-		if err != nil {
-			return nil, err
-		}
+func scanCreativityEntry(row interface{ Scan(...any) error }) (*CreativityEntry, error) {
+	var entry CreativityEntry
+	err := row.Scan(&entry.ID, &entry.UUID, &entry.Title, &entry.Kind, &entry.Content, &entry.EntryDate,
+		&entry.PromptID, &entry.WordCount, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
 
-		id, err := res.LastInsertId()
+func scanCreativityEntries(rows *sql.Rows) ([]CreativityEntry, error) {
+	entries := []CreativityEntry{}
+	for rows.Next() {
+		entry, err := scanCreativityEntry(rows)
 		if err != nil {
 			return nil, err
 		}
+		entries = append(entries, *entry)
+	}
 
-		return &CreativityEntry{
-			ID:        id,
-			Content:   content,
-			EntryDate: entryDate,
-			CreatedAt: now,
-			UpdatedAt: now,
-		}, nil
-	} else {
-		// Update existing entry
-		_, err := database.DB.Exec(`
-			UPDATE creativity_entries 
-			SET content = ?, updated_at = ? 
-			WHERE id = ?`, content, now, existingID)
+	return entries, rows.Err()
+}
 
-		if err != nil {
-			return nil, err
-		}
+// validateCreativityEntry checks an entry's kind and date
+func validateCreativityEntry(entry CreativityEntry) error {
+	switch entry.Kind {
+	case "", CreativityPoem, CreativityStory, CreativitySketchNotes, CreativityList:
+	default:
+		return fmt.Errorf("unknown creativity entry kind %q", entry.Kind)
+	}
+	if _, err := time.Parse("2006-01-02", entry.EntryDate); err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", entry.EntryDate)
+	}
+	if strings.TrimSpace(entry.Content) == "" {
+		return fmt.Errorf("creativity entry content is required")
+	}
+	return nil
+}
+
+// checkOnePerDay returns ErrCreativityEntryExists in one-per-day mode if
+// an entry other than excludeID is already on the date
+func checkOnePerDay(tx *sql.Tx, entryDate string, excludeID int64) error {
+	// Read within the transaction, as the database has a single connection
+	var onePerDay string
+	err := tx.QueryRow(`SELECT value FROM settings WHERE key = ?`, CreativityOnePerDaySetting).Scan(&onePerDay)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil || onePerDay != "true" {
+		return err
+	}
 
-		return &CreativityEntry{
-			ID:        existingID,
-			Content:   content,
-			EntryDate: entryDate,
-			CreatedAt: now, // This will be overwritten below
-			UpdatedAt: now,
-		}, nil
+	var id int64
+	err = tx.QueryRow(`
+		SELECT id FROM creativity_entries
+		WHERE entry_date = ? AND id != ?
+		LIMIT 1`, entryDate, excludeID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
 	}
+	if err != nil {
+		return err
+	}
+	return ErrCreativityEntryExists
 }
 
-// GetCreativityEntryByDate retrieves the creativity entry for a specific date
-func GetCreativityEntryByDate(entryDate string) (*CreativityEntry, error) {
-	var entry CreativityEntry
+// Create adds a creativity entry. Days can have several entries unless
+// one-per-day mode is on.
+func (s *sqlCreativityStore) Create(entry CreativityEntry) (*CreativityEntry, error) {
+	entry.Title = strings.TrimSpace(entry.Title)
+	if err := validateCreativityEntry(entry); err != nil {
+		return nil, err
+	}
 
-	err := database.DB.QueryRow(`
-		SELECT id, content, entry_date, created_at, updated_at 
-		FROM creativity_entries 
-		WHERE entry_date = ?`, entryDate).Scan(
-		&entry.ID, &entry.Content, &entry.EntryDate, &entry.CreatedAt, &entry.UpdatedAt)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	if err := checkOnePerDay(tx, entry.EntryDate, 0); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	words, chars := textstats.Count(entry.Content)
+	entry.UUID = ids.New()
+	entry.WordCount = words
+	entry.CreatedAt, entry.UpdatedAt = now, now
+
+	res, err := tx.Exec(`
+		INSERT INTO creativity_entries (uuid, title, kind, content, entry_date, prompt_id, word_count, char_count, sentiment, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.UUID, entry.Title, entry.Kind, entry.Content, entry.EntryDate, entry.PromptID,
+		words, chars, sentiment.Score(entry.Content), now, now)
 	if err != nil {
 		return nil, err
 	}
 
+	entry.ID, err = res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if err := indexEntry(s.db, EntryCreativity, entry.ID); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.CreativityCreated, ID: entry.ID, Date: entry.EntryDate})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "creativity"})
 	return &entry, nil
 }
 
-// GetAllCreativityEntries retrieves all creativity entries
-func GetAllCreativityEntries() ([]CreativityEntry, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, content, entry_date, created_at, updated_at 
+// Save creates or updates a creativity journal entry for a specific date,
+// keeping the prompt an existing entry responds to
+func (s *sqlCreativityStore) Save(content string, entryDate string) (*CreativityEntry, error) {
+	return s.SaveForPrompt(content, entryDate, nil)
+}
+
+// SaveForPrompt saves the content of the first creativity entry on a date,
+// creating it if the date has none. A nil promptID keeps the prompt of an
+// existing entry.
+func (s *sqlCreativityStore) SaveForPrompt(content string, entryDate string, promptID *int64) (*CreativityEntry, error) {
+	existing, err := scanCreativityEntry(s.db.QueryRow(`
+		SELECT `+creativityColumns+`
+		FROM creativity_entries
+		WHERE entry_date = ?
+		ORDER BY id
+		LIMIT 1`, entryDate))
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.Create(CreativityEntry{Content: content, EntryDate: entryDate, PromptID: promptID})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Content = content
+	if promptID != nil {
+		existing.PromptID = promptID
+	}
+	if err := s.Edit(*existing); err != nil {
+		return nil, err
+	}
+	return s.GetByID(existing.ID)
+}
+
+// importCreativity writes and indexes an imported creativity entry within
+// tx. Imported entries have no title or kind, and follow one-per-day mode
+// like entries written in the app.
+func importCreativity(tx *sql.Tx, content string, entryDate string, createdAt time.Time) (*CreativityEntry, error) {
+	if err := checkOnePerDay(tx, entryDate, 0); err != nil {
+		return nil, err
+	}
+
+	uuid := ids.New()
+	words, chars := textstats.Count(content)
+	res, err := tx.Exec(`
+		INSERT INTO creativity_entries (uuid, title, kind, content, entry_date, word_count, char_count, sentiment, created_at, updated_at) 
+		VALUES (?, '', '', ?, ?, ?, ?, ?, ?, ?)`, uuid, content, entryDate, words, chars, sentiment.Score(content), createdAt, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	if err := indexEntryTx(tx, EntryCreativity, id); err != nil {
+		return nil, err
+	}
+
+	return &CreativityEntry{
+		ID:        id,
+		UUID:      uuid,
+		Content:   content,
+		EntryDate: entryDate,
+		WordCount: words,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}, nil
+}
+
+// GetByID retrieves a creativity entry
+func (s *sqlCreativityStore) GetByID(id int64) (*CreativityEntry, error) {
+	return scanCreativityEntry(s.db.QueryRow(`SELECT `+creativityColumns+` FROM creativity_entries WHERE id = ?`, id))
+}
+
+// GetByDate retrieves the creativity entries for a specific date in the order they were written
+func (s *sqlCreativityStore) GetByDate(entryDate string) ([]CreativityEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT `+creativityColumns+`
 		FROM creativity_entries 
-		ORDER BY entry_date DESC`)
+		WHERE entry_date = ?
+		ORDER BY id`, entryDate)
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var entries []CreativityEntry
-	for rows.Next() {
-		var entry CreativityEntry
-		err := rows.Scan(&entry.ID, &entry.Content, &entry.EntryDate, &entry.CreatedAt, &entry.UpdatedAt)
+	return scanCreativityEntries(rows)
+}
+
+// GetAll retrieves all creativity entries
+func (s *sqlCreativityStore) GetAll() ([]CreativityEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + creativityColumns + `
+		FROM creativity_entries 
+		ORDER BY entry_date DESC, id DESC`)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCreativityEntries(rows)
+}
+
+// GetByPrompt retrieves the entries written in response to a prompt, newest first
+func (s *sqlCreativityStore) GetByPrompt(promptID int64) ([]CreativityEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT `+creativityColumns+`
+		FROM creativity_entries 
+		WHERE prompt_id = ?
+		ORDER BY entry_date DESC, id DESC`, promptID)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCreativityEntries(rows)
+}
+
+// Edit changes a creativity entry's title, kind, content, date and prompt
+func (s *sqlCreativityStore) Edit(entry CreativityEntry) error {
+	entry.Title = strings.TrimSpace(entry.Title)
+	if err := validateCreativityEntry(entry); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkOnePerDay(tx, entry.EntryDate, entry.ID); err != nil {
+		return err
+	}
+
+	var uuid, oldTitle string
+	err = tx.QueryRow(`SELECT uuid, title FROM creativity_entries WHERE id = ?`, entry.ID).Scan(&uuid, &oldTitle)
+	if err != nil {
+		return err
+	}
+
+	words, chars := textstats.Count(entry.Content)
+	res, err := tx.Exec(`
+		UPDATE creativity_entries
+		SET title = ?, kind = ?, content = ?, entry_date = ?, prompt_id = ?,
+			word_count = ?, char_count = ?, sentiment = ?, updated_at = ?
+		WHERE id = ?`, entry.Title, entry.Kind, entry.Content, entry.EntryDate, entry.PromptID,
+		words, chars, sentiment.Score(entry.Content), time.Now(), entry.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if err := indexEntry(s.db, EntryCreativity, entry.ID); err != nil {
+		return err
+	}
+
+	// Links to the entry by its title follow it to the new one
+	if oldTitle != "" && !strings.EqualFold(oldTitle, entry.Title) {
+		to := entry.Title
+		if to == "" {
+			to = EntryCreativity + ":" + uuid
+		}
+		changed, err := renameLinks(s.db, uuid, oldTitle, to)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for _, e := range changed {
+			if e.Type == EntryAnswer {
+				s.bus.Publish(events.Event{Type: events.AnswerUpdated, ID: e.ID})
+			} else {
+				s.bus.Publish(events.Event{Type: events.CreativityUpdated, ID: e.ID, Date: e.Date})
+			}
 		}
-		entries = append(entries, entry)
 	}
 
-	return entries, nil
+	s.bus.Publish(events.Event{Type: events.CreativityUpdated, ID: entry.ID, Date: entry.EntryDate})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "creativity"})
+	return nil
 }
 
-// UpdateCreativityEntry updates a creativity entry
-func UpdateCreativityEntry(id int64, content string) error {
+// Update updates a creativity entry
+func (s *sqlCreativityStore) Update(id int64, content string) error {
 	now := time.Now()
-	_, err := database.DB.Exec(`
+	words, chars := textstats.Count(content)
+	_, err := s.db.Exec(`
 		UPDATE creativity_entries 
-		SET content = ?, updated_at = ? 
-		WHERE id = ?`, content, now, id)
-	return err
+		SET content = ?, word_count = ?, char_count = ?, sentiment = ?, updated_at = ? 
+		WHERE id = ?`, content, words, chars, sentiment.Score(content), now, id)
+	if err != nil {
+		return err
+	}
+	if err := indexEntry(s.db, EntryCreativity, id); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.CreativityUpdated, ID: id})
+	return nil
 }
 
-// DeleteCreativityEntry deletes a creativity entry
-func DeleteCreativityEntry(id int64) error {
-	_, err := database.DB.Exec(`DELETE FROM creativity_entries WHERE id = ?`, id)
-	return err
+// Delete deletes a creativity entry
+func (s *sqlCreativityStore) Delete(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM creativity_entries WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.CreativityDeleted, ID: id})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "creativity"})
+	return nil
 }
 
-// HasCreativityEntryForDate checks if there is an entry for the given date
-func HasCreativityEntryForDate(entryDate string) (bool, error) {
+// HasForDate checks if there is an entry for the given date
+func (s *sqlCreativityStore) HasForDate(entryDate string) (bool, error) {
 	var count int
-	err := database.DB.QueryRow(`
+	err := s.db.QueryRow(`
 		SELECT COUNT(*) 
 		FROM creativity_entries 
 		WHERE entry_date = ?`, entryDate).Scan(&count)
@@ -144,9 +423,9 @@ func HasCreativityEntryForDate(entryDate string) (bool, error) {
 	return count > 0, nil
 }
 
-// GetCreativityStreak calculates the current streak of consecutive days with creativity entries
-func GetCreativityStreak() (int, error) {
-	rows, err := database.DB.Query(`
+// GetStreak calculates the current streak of consecutive days with creativity entries
+func (s *sqlCreativityStore) GetStreak() (int, error) {
+	rows, err := s.db.Query(`
 		SELECT DISTINCT entry_date
 		FROM creativity_entries
 		ORDER BY entry_date DESC`)
@@ -209,3 +488,16 @@ func GetCreativityStreak() (int, error) {
 
 	return streak, nil
 }
+
+// OnePerDay reports whether the journal is limited to one entry per day
+func (s *sqlCreativityStore) OnePerDay() (bool, error) {
+	var enabled bool
+	_, err := s.settings.GetJSON(CreativityOnePerDaySetting, &enabled)
+	return enabled, err
+}
+
+// SetOnePerDay limits the journal to one entry per day, or allows several.
+// Days that already have several entries keep them.
+func (s *sqlCreativityStore) SetOnePerDay(enabled bool) error {
+	return s.settings.SetJSON(CreativityOnePerDaySetting, enabled)
+}
@@ -0,0 +1,113 @@
+// backend/models/creativity_test.go
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreativityModel(t *testing.T) {
+	t.Parallel()
+
+	// Test that a day can have several titled entries of different kinds
+	t.Run("SeveralPerDay", func(t *testing.T) {
+		stores, _ := newTestStores(t)
+
+		poem, err := stores.Creativity.Create(CreativityEntry{Title: "Rain", Kind: CreativityPoem, Content: "Drops on the window", EntryDate: "2024-05-01"})
+		if err != nil {
+			t.Fatalf("Failed to create creativity entry: %v", err)
+		}
+		if _, err := stores.Creativity.Create(CreativityEntry{Title: "Groceries", Kind: CreativityList, Content: "- bread\n- milk", EntryDate: "2024-05-01"}); err != nil {
+			t.Fatalf("Failed to create creativity entry: %v", err)
+		}
+		if poem.WordCount != 4 || poem.UUID == "" {
+			t.Errorf("Expected a UUID and 4 words, got %+v", poem)
+		}
+
+		if _, err := stores.Creativity.Create(CreativityEntry{Kind: "sonnet", Content: "Shall I compare thee", EntryDate: "2024-05-01"}); err == nil {
+			t.Error("Expected an unknown kind to be rejected")
+		}
+		if _, err := stores.Creativity.Create(CreativityEntry{Content: "Once upon a time", EntryDate: "May 1st"}); err == nil {
+			t.Error("Expected an invalid date to be rejected")
+		}
+
+		entries, err := stores.Creativity.GetByDate("2024-05-01")
+		if err != nil {
+			t.Fatalf("Failed to get creativity entries: %v", err)
+		}
+		if len(entries) != 2 || entries[0].Title != "Rain" || entries[1].Kind != CreativityList {
+			t.Errorf("Expected both entries in the order written, got %+v", entries)
+		}
+
+		// Saving the day's entry updates the first one rather than adding another
+		if _, err := stores.Creativity.Save("Drops on the window pane", "2024-05-01"); err != nil {
+			t.Fatalf("Failed to save creativity entry: %v", err)
+		}
+		entries, _ = stores.Creativity.GetByDate("2024-05-01")
+		if len(entries) != 2 || entries[0].Content != "Drops on the window pane" || entries[0].Title != "Rain" {
+			t.Errorf("Expected the first entry updated in place, got %+v", entries)
+		}
+
+		poem.Title, poem.Kind, poem.Content = "Storm", CreativityStory, "It rained for forty days"
+		if err := stores.Creativity.Edit(*poem); err != nil {
+			t.Fatalf("Failed to edit creativity entry: %v", err)
+		}
+		edited, err := stores.Creativity.GetByID(poem.ID)
+		if err != nil {
+			t.Fatalf("Failed to get creativity entry: %v", err)
+		}
+		if edited.Title != "Storm" || edited.Kind != CreativityStory || edited.WordCount != 5 {
+			t.Errorf("Expected the entry edited, got %+v", edited)
+		}
+	})
+
+	// Test that one-per-day mode rejects a second entry on a date
+	t.Run("OnePerDay", func(t *testing.T) {
+		stores, _ := newTestStores(t)
+
+		if enabled, err := stores.Creativity.OnePerDay(); err != nil || enabled {
+			t.Fatalf("Expected several entries per day allowed by default, got %v (%v)", enabled, err)
+		}
+		if err := stores.Creativity.SetOnePerDay(true); err != nil {
+			t.Fatalf("Failed to turn on one-per-day mode: %v", err)
+		}
+
+		first, err := stores.Creativity.Create(CreativityEntry{Content: "Morning pages", EntryDate: "2024-05-02"})
+		if err != nil {
+			t.Fatalf("Failed to create creativity entry: %v", err)
+		}
+		_, err = stores.Creativity.Create(CreativityEntry{Content: "Evening pages", EntryDate: "2024-05-02"})
+		if !errors.Is(err, ErrCreativityEntryExists) {
+			t.Errorf("Expected a second entry on the day to be rejected, got %v", err)
+		}
+
+		// Moving an entry onto a day that has one is rejected too
+		second, err := stores.Creativity.Create(CreativityEntry{Content: "Evening pages", EntryDate: "2024-05-03"})
+		if err != nil {
+			t.Fatalf("Failed to create creativity entry: %v", err)
+		}
+		second.EntryDate = first.EntryDate
+		if err := stores.Creativity.Edit(*second); !errors.Is(err, ErrCreativityEntryExists) {
+			t.Errorf("Expected moving onto a taken day to be rejected, got %v", err)
+		}
+
+		// Imports follow the mode too
+		imported := ImportedEntry{Type: EntryCreativity, Content: "Imported pages", Date: "2024-05-02", CreatedAt: time.Date(2024, 5, 2, 21, 0, 0, 0, time.UTC)}
+		if err := stores.Imports.Import([]ImportedEntry{imported}); !errors.Is(err, ErrCreativityEntryExists) {
+			t.Errorf("Expected an import onto a taken day to be rejected, got %v", err)
+		}
+
+		// The day's entry can still be saved over
+		if _, err := stores.Creativity.Save("Morning pages, revised", "2024-05-02"); err != nil {
+			t.Fatalf("Failed to save creativity entry: %v", err)
+		}
+
+		if err := stores.Creativity.SetOnePerDay(false); err != nil {
+			t.Fatalf("Failed to turn off one-per-day mode: %v", err)
+		}
+		if err := stores.Creativity.Edit(*second); err != nil {
+			t.Errorf("Expected several entries allowed again, got %v", err)
+		}
+	})
+}
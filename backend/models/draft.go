@@ -0,0 +1,197 @@
+// backend/models/draft.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// Draft is an answer still being written. There is at most one draft per
+// question per day.
+type Draft struct {
+	ID         int64        `json:"id"`
+	QuestionID int64        `json:"questionId"`
+	Content    string       `json:"content"`
+	Fields     []FieldValue `json:"fields"`    // Values for the question's template, for structured drafts
+	DraftDate  string       `json:"draftDate"` // YYYY-MM-DD
+	CreatedAt  time.Time    `json:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
+}
+
+// DraftStore manages answer drafts
+type DraftStore interface {
+	Save(questionID int64, content string) (*Draft, error)
+	SaveStructured(questionID int64, fields []FieldValue) (*Draft, error)
+	GetAll() ([]Draft, error)
+	GetByID(id int64) (*Draft, error)
+	Commit(id int64) (*Answer, error)
+	Delete(id int64) error
+}
+
+type sqlDraftStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewDraftStore creates a DraftStore backed by db
+func NewDraftStore(db *sql.DB, bus *events.Bus) DraftStore {
+	return &sqlDraftStore{db: db, bus: bus}
+}
+
+const draftColumns = `id, question_id, content, fields, draft_date, created_at, updated_at`
+
+func scanDraft(row interface{ Scan(...any) error }) (*Draft, error) {
+	var d Draft
+	var fields string
+	if err := row.Scan(&d.ID, &d.QuestionID, &d.Content, &fields, &d.DraftDate, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	d.Fields, err = decodeFields(fields)
+	return &d, err
+}
+
+// Save creates or replaces today's draft for a question. Saving empty
+// content discards the draft and returns nil.
+func (s *sqlDraftStore) Save(questionID int64, content string) (*Draft, error) {
+	return s.save(questionID, content, nil, strings.TrimSpace(content) == "")
+}
+
+// SaveStructured creates or replaces today's draft for a question with a
+// template. The values are only checked against the template when the draft
+// is committed, so a draft can be left half done. Saving no values discards
+// the draft and returns nil.
+func (s *sqlDraftStore) SaveStructured(questionID int64, fields []FieldValue) (*Draft, error) {
+	return s.save(questionID, "", fields, len(fields) == 0)
+}
+
+func (s *sqlDraftStore) save(questionID int64, content string, fields []FieldValue, empty bool) (*Draft, error) {
+	now := time.Now()
+	date := now.Format("2006-01-02")
+
+	if empty {
+		var id int64
+		err := s.db.QueryRow(`
+			SELECT id FROM answer_drafts
+			WHERE question_id = ? AND draft_date = ?`, questionID, date).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.Delete(id)
+	}
+
+	data, err := encodeFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO answer_drafts (uuid, question_id, content, fields, draft_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(question_id, draft_date) DO UPDATE SET
+			content = excluded.content,
+			fields = excluded.fields,
+			updated_at = excluded.updated_at`, ids.New(), questionID, content, data, date, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := scanDraft(s.db.QueryRow(`
+		SELECT `+draftColumns+`
+		FROM answer_drafts
+		WHERE question_id = ? AND draft_date = ?`, questionID, date))
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.DraftSaved, ID: draft.ID, Date: date})
+	return draft, nil
+}
+
+// GetAll retrieves every draft, most recently edited first
+func (s *sqlDraftStore) GetAll() ([]Draft, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + draftColumns + `
+		FROM answer_drafts
+		ORDER BY updated_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []Draft{}
+	for rows.Next() {
+		d, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, *d)
+	}
+
+	return drafts, rows.Err()
+}
+
+// GetByID retrieves a draft
+func (s *sqlDraftStore) GetByID(id int64) (*Draft, error) {
+	return scanDraft(s.db.QueryRow(`SELECT `+draftColumns+` FROM answer_drafts WHERE id = ?`, id))
+}
+
+// Commit turns a draft into an answer and removes the draft. A structured
+// draft's values are checked against the question's template, as they are
+// for answers created directly.
+func (s *sqlDraftStore) Commit(id int64) (*Answer, error) {
+	draft, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Fields == nil && strings.TrimSpace(draft.Content) == "" {
+		return nil, fmt.Errorf("draft %d is empty", id)
+	}
+
+	// The answer is written and the draft removed together, so a failure
+	// keeps the draft and leaves no answer
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	content, fields := draft.Content, []FieldValue(nil)
+	if draft.Fields != nil {
+		if content, fields, err = structure(tx, draft.QuestionID, draft.Fields); err != nil {
+			return nil, err
+		}
+	}
+	answer, err := createAnswer(tx, draft.QuestionID, content, fields, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM answer_drafts WHERE id = ?`, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.DraftDeleted, ID: id, Date: draft.DraftDate})
+	s.bus.Publish(events.Event{Type: events.AnswerCreated, ID: answer.ID, Date: answer.CreatedAt.Format("2006-01-02")})
+	return answer, nil
+}
+
+// Delete discards a draft
+func (s *sqlDraftStore) Delete(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM answer_drafts WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.DraftDeleted, ID: id})
+	return nil
+}
@@ -0,0 +1,109 @@
+// backend/models/draft_test.go
+package models
+
+import (
+	"testing"
+)
+
+func TestDraftModel(t *testing.T) {
+	t.Parallel()
+
+	stores, _ := newTestStores(t)
+
+	question, err := stores.Questions.Add("What did I learn today?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+
+	// Test that saving repeatedly keeps a single draft
+	t.Run("SaveDraft", func(t *testing.T) {
+		first, err := stores.Drafts.Save(question.ID, "I learned")
+		if err != nil {
+			t.Fatalf("Failed to save draft: %v", err)
+		}
+
+		second, err := stores.Drafts.Save(question.ID, "I learned about drafts")
+		if err != nil {
+			t.Fatalf("Failed to save draft: %v", err)
+		}
+
+		if first.ID != second.ID {
+			t.Errorf("Expected the same draft to be updated, got IDs %d and %d", first.ID, second.ID)
+		}
+
+		drafts, err := stores.Drafts.GetAll()
+		if err != nil {
+			t.Fatalf("Failed to get drafts: %v", err)
+		}
+
+		if len(drafts) != 1 || drafts[0].Content != "I learned about drafts" {
+			t.Errorf("Expected one draft with the latest content, got %+v", drafts)
+		}
+
+		answers, err := stores.Answers.GetAll()
+		if err != nil {
+			t.Fatalf("Failed to get answers: %v", err)
+		}
+
+		if len(answers) != 0 {
+			t.Errorf("Expected drafts not to create answers, got %d", len(answers))
+		}
+	})
+
+	// Test committing a draft as an answer
+	t.Run("CommitDraft", func(t *testing.T) {
+		draft, err := stores.Drafts.Save(question.ID, "Final answer #done")
+		if err != nil {
+			t.Fatalf("Failed to save draft: %v", err)
+		}
+
+		answer, err := stores.Drafts.Commit(draft.ID)
+		if err != nil {
+			t.Fatalf("Failed to commit draft: %v", err)
+		}
+
+		if answer.QuestionID != question.ID || answer.Content != "Final answer #done" {
+			t.Errorf("Unexpected answer %+v", answer)
+		}
+
+		drafts, err := stores.Drafts.GetAll()
+		if err != nil {
+			t.Fatalf("Failed to get drafts: %v", err)
+		}
+
+		if len(drafts) != 0 {
+			t.Errorf("Expected draft to be removed after commit, got %d", len(drafts))
+		}
+
+		// The answer is indexed like any other
+		tagged, _ := stores.Tags.GetEntries("done")
+		if len(tagged) != 1 || tagged[0].ID != answer.ID {
+			t.Errorf("Expected the answer tagged, got %+v", tagged)
+		}
+
+		if _, err := stores.Drafts.Commit(draft.ID); err == nil {
+			t.Errorf("Expected error committing a draft twice")
+		}
+	})
+
+	// Test that saving empty content discards the draft
+	t.Run("DiscardEmpty", func(t *testing.T) {
+		if _, err := stores.Drafts.Save(question.ID, "Something"); err != nil {
+			t.Fatalf("Failed to save draft: %v", err)
+		}
+
+		draft, err := stores.Drafts.Save(question.ID, "   ")
+		if err != nil {
+			t.Fatalf("Failed to save empty draft: %v", err)
+		}
+
+		if draft != nil {
+			t.Errorf("Expected no draft for empty content, got %+v", draft)
+		}
+
+		drafts, _ := stores.Drafts.GetAll()
+		if len(drafts) != 0 {
+			t.Errorf("Expected draft to be discarded, got %d", len(drafts))
+		}
+	})
+}
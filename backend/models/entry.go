@@ -0,0 +1,192 @@
+// backend/models/entry.go
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"myproject/backend/sentiment"
+	"myproject/backend/textstats"
+)
+
+// Types of entry that can be tagged and linked to
+const (
+	EntryAnswer      = "answer"
+	EntryGratitude   = "gratitude"
+	EntryCreativity  = "creativity"
+	EntryAffirmation = "affirmation"
+)
+
+// entryTypes lists the entry types in a fixed order
+var entryTypes = []string{EntryAnswer, EntryGratitude, EntryCreativity, EntryAffirmation}
+
+// entryTables name each entry type's table
+var entryTables = map[string]string{
+	EntryAnswer:      "answers",
+	EntryGratitude:   "gratitude_items",
+	EntryCreativity:  "creativity_entries",
+	EntryAffirmation: "affirmations",
+}
+
+// entryQueries select the id, UUID, content, entry date (empty when it
+// comes from created_at), created_at and a title of each type of entry
+var entryQueries = map[string]string{
+	EntryAnswer: `
+		SELECT a.id, a.uuid, a.content, '', a.created_at, COALESCE(q.content, '')
+		FROM answers a LEFT JOIN questions q ON q.id = a.question_id`,
+	EntryGratitude: `
+		SELECT id, uuid, content, entry_date, created_at, ''
+		FROM gratitude_items`,
+	EntryCreativity: `
+		SELECT id, uuid, content, entry_date, created_at, title
+		FROM creativity_entries`,
+	EntryAffirmation: `
+		SELECT id, uuid, content, '', created_at, ''
+		FROM affirmations`,
+}
+
+// entryColumn names a column of an entry's table in entryQueries, which
+// alias the answers table as they join it with questions
+func entryColumn(entryType string, column string) string {
+	if entryType == EntryAnswer {
+		return "a." + column
+	}
+	return column
+}
+
+// EntrySummary is an entry of any type, as found by its tags or links
+type EntrySummary struct {
+	Type    string    `json:"type"` // "answer", "gratitude", "creativity" or "affirmation"
+	ID      int64     `json:"id"`
+	UUID    string    `json:"uuid"`
+	Date    string    `json:"date"` // YYYY-MM-DD
+	At      time.Time `json:"at"`
+	Title   string    `json:"title"` // The question for answers, the title for creativity entries
+	Content string    `json:"content"`
+}
+
+// querier runs queries on the database or in a transaction
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// loadEntries runs one of entryQueries, with an optional condition
+func loadEntries(q querier, entryType string, where string, args ...any) ([]EntrySummary, error) {
+	query := entryQueries[entryType]
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []EntrySummary
+	for rows.Next() {
+		e := EntrySummary{Type: entryType}
+		if err := rows.Scan(&e.ID, &e.UUID, &e.Content, &e.Date, &e.At, &e.Title); err != nil {
+			return nil, err
+		}
+		if e.Date == "" {
+			e.Date = e.At.In(time.Local).Format("2006-01-02")
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// loadEntry loads one entry
+func loadEntry(q querier, entryType string, id int64) (*EntrySummary, error) {
+	entries, err := loadEntries(q, entryType, entryColumn(entryType, "id")+" = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &entries[0], nil
+}
+
+// indexEntry reads the tags and links in an entry after it is saved
+func indexEntry(db *sql.DB, entryType string, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := indexEntryTx(tx, entryType, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// indexEntryTx is indexEntry within a transaction the caller commits
+func indexEntryTx(tx *sql.Tx, entryType string, id int64) error {
+	entry, err := loadEntry(tx, entryType, id)
+	if err != nil {
+		return err
+	}
+	if err := tagEntry(tx, *entry); err != nil {
+		return err
+	}
+	if err := linkEntry(tx, *entry); err != nil {
+		return err
+	}
+	if err := pruneTags(tx); err != nil {
+		return err
+	}
+
+	// The entry may be the one an earlier link was waiting for
+	return resolveLinks(tx)
+}
+
+// rewriteContent rewrites an entry's content, along with the values worked
+// out from it. The text in a structured answer's fields is rewritten too, so
+// the next change to the fields doesn't undo it.
+func rewriteContent(tx *sql.Tx, e *EntrySummary, rewrite func(string) string) error {
+	e.Content = rewrite(e.Content)
+	content := e.Content
+	now := time.Now()
+	words, chars := textstats.Count(content)
+
+	var err error
+	switch e.Type {
+	case EntryAnswer:
+		var stored string
+		if err := tx.QueryRow(`SELECT fields FROM answers WHERE id = ?`, e.ID).Scan(&stored); err != nil {
+			return err
+		}
+		fields, err := decodeFields(stored)
+		if err != nil {
+			return err
+		}
+		for i := range fields {
+			fields[i].Text = rewrite(fields[i].Text)
+			for j := range fields[i].Items {
+				fields[i].Items[j] = rewrite(fields[i].Items[j])
+			}
+		}
+		data, err := encodeFields(fields)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE answers SET content = ?, fields = ?, word_count = ?, char_count = ?, sentiment = ?, updated_at = ?
+			WHERE id = ?`, content, data, words, chars, sentiment.Score(content), now, e.ID)
+		return err
+	case EntryCreativity:
+		_, err = tx.Exec(`
+			UPDATE creativity_entries SET content = ?, word_count = ?, char_count = ?, sentiment = ?, updated_at = ?
+			WHERE id = ?`, content, words, chars, sentiment.Score(content), now, e.ID)
+	case EntryGratitude:
+		_, err = tx.Exec(`UPDATE gratitude_items SET content = ?, sentiment = ? WHERE id = ?`, content, sentiment.Score(content), e.ID)
+	case EntryAffirmation:
+		_, err = tx.Exec(`UPDATE affirmations SET content = ?, updated_at = ? WHERE id = ?`, content, now, e.ID)
+	}
+	return err
+}
@@ -0,0 +1,650 @@
+// backend/models/goal.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// Goal statuses
+const (
+	GoalActive    = "active"
+	GoalPaused    = "paused"
+	GoalCompleted = "completed"
+	GoalAbandoned = "abandoned"
+)
+
+// Entry types that can be linked to a goal
+const (
+	GoalLinkAnswer     = "answer"
+	GoalLinkCreativity = "creativity"
+	GoalLinkGratitude  = "gratitude"
+)
+
+// goalLinkTables maps the entry types that can be linked to their tables
+var goalLinkTables = map[string]string{
+	GoalLinkAnswer:     "answers",
+	GoalLinkCreativity: "creativity_entries",
+	GoalLinkGratitude:  "gratitude_items",
+}
+
+// Goal is something the user is working towards
+type Goal struct {
+	ID          int64           `json:"id"`
+	UUID        string          `json:"uuid"`
+	Title       string          `json:"title"`
+	Why         string          `json:"why"`
+	TargetDate  string          `json:"targetDate"` // YYYY-MM-DD, or empty for no deadline
+	Status      string          `json:"status"`     // "active", "paused", "completed" or "abandoned"
+	CompletedAt *time.Time      `json:"completedAt"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	Milestones  []GoalMilestone `json:"milestones"`
+}
+
+// GoalMilestone is a step on the way to a goal
+type GoalMilestone struct {
+	ID          int64      `json:"id"`
+	UUID        string     `json:"uuid"`
+	GoalID      int64      `json:"goalId"`
+	Title       string     `json:"title"`
+	TargetDate  string     `json:"targetDate"`
+	Position    int        `json:"position"`
+	CompletedAt *time.Time `json:"completedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// GoalCheckIn records progress towards a goal in one week
+type GoalCheckIn struct {
+	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
+	GoalID    int64     `json:"goalId"`
+	Week      string    `json:"week"`     // Monday of the week, YYYY-MM-DD
+	Progress  int       `json:"progress"` // Percent of the way to the goal, 0 to 100
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// GoalTimelineItem is something written about a goal
+type GoalTimelineItem struct {
+	Type    string    `json:"type"` // "goal", "milestone", "checkin", "answer", "creativity" or "gratitude"
+	ID      int64     `json:"id"`
+	Date    string    `json:"date"` // YYYY-MM-DD
+	At      time.Time `json:"at"`
+	Title   string    `json:"title"`
+	Content string    `json:"content"`
+}
+
+// GoalStore manages goals, their milestones and check-ins, and the entries linked to them
+type GoalStore interface {
+	GetAll(status string) ([]Goal, error)
+	GetByID(id int64) (*Goal, error)
+	Create(goal Goal) (*Goal, error)
+	Update(goal Goal) error
+	SetStatus(id int64, status string) error
+	Delete(id int64) error
+	AddMilestone(goalID int64, title string, targetDate string) (*GoalMilestone, error)
+	SetMilestoneDone(id int64, done bool) error
+	DeleteMilestone(id int64) error
+	CheckIn(goalID int64, progress int, note string) (*GoalCheckIn, error)
+	GetCheckIns(goalID int64) ([]GoalCheckIn, error)
+	Link(goalID int64, entryType string, entryID int64) error
+	Unlink(goalID int64, entryType string, entryID int64) error
+	GetLinkedGoals(entryType string, entryID int64) ([]Goal, error)
+	GetTimeline(goalID int64) ([]GoalTimelineItem, error)
+}
+
+type sqlGoalStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewGoalStore creates a GoalStore backed by db
+func NewGoalStore(db *sql.DB, bus *events.Bus) GoalStore {
+	return &sqlGoalStore{db: db, bus: bus}
+}
+
+const goalColumns = `id, uuid, title, why, target_date, status, completed_at, created_at, updated_at`
+
+func scanGoal(row interface{ Scan(...any) error }) (*Goal, error) {
+	var g Goal
+	var completedAt sql.NullTime
+	err := row.Scan(&g.ID, &g.UUID, &g.Title, &g.Why, &g.TargetDate, &g.Status, &completedAt, &g.CreatedAt, &g.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		g.CompletedAt = &completedAt.Time
+	}
+	g.Milestones = []GoalMilestone{}
+	return &g, nil
+}
+
+// validateGoal checks the fields the user can set
+func validateGoal(g Goal) error {
+	if strings.TrimSpace(g.Title) == "" {
+		return fmt.Errorf("goal title is required")
+	}
+	if g.TargetDate != "" {
+		if _, err := time.Parse("2006-01-02", g.TargetDate); err != nil {
+			return fmt.Errorf("invalid target date %q, expected YYYY-MM-DD", g.TargetDate)
+		}
+	}
+	return validateGoalStatus(g.Status)
+}
+
+func validateGoalStatus(status string) error {
+	switch status {
+	case GoalActive, GoalPaused, GoalCompleted, GoalAbandoned:
+		return nil
+	}
+	return fmt.Errorf("unknown goal status %q", status)
+}
+
+// GetAll returns the goals with a status, or all goals if status is empty,
+// with their milestones
+func (s *sqlGoalStore) GetAll(status string) ([]Goal, error) {
+	rows, err := s.db.Query(`
+		SELECT `+goalColumns+`
+		FROM goals
+		WHERE ? = '' OR status = ?
+		ORDER BY id`, status, status)
+	if err != nil {
+		return nil, err
+	}
+
+	goals := []Goal{}
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		goals = append(goals, *g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range goals {
+		if goals[i].Milestones, err = s.milestones(goals[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return goals, nil
+}
+
+// GetByID returns a goal with its milestones
+func (s *sqlGoalStore) GetByID(id int64) (*Goal, error) {
+	goal, err := scanGoal(s.db.QueryRow(`SELECT `+goalColumns+` FROM goals WHERE id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	if goal.Milestones, err = s.milestones(id); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// milestones returns a goal's milestones in order
+func (s *sqlGoalStore) milestones(goalID int64) ([]GoalMilestone, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, goal_id, title, target_date, position, completed_at, created_at
+		FROM goal_milestones
+		WHERE goal_id = ?
+		ORDER BY position, id`, goalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := []GoalMilestone{}
+	for rows.Next() {
+		var m GoalMilestone
+		var completedAt sql.NullTime
+		if err := rows.Scan(&m.ID, &m.UUID, &m.GoalID, &m.Title, &m.TargetDate, &m.Position, &completedAt, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if completedAt.Valid {
+			m.CompletedAt = &completedAt.Time
+		}
+		milestones = append(milestones, m)
+	}
+	return milestones, rows.Err()
+}
+
+// Create adds a goal. Its status defaults to active.
+func (s *sqlGoalStore) Create(goal Goal) (*Goal, error) {
+	if goal.Status == "" {
+		goal.Status = GoalActive
+	}
+	goal.Title = strings.TrimSpace(goal.Title)
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	goal.UUID = ids.New()
+	goal.CreatedAt, goal.UpdatedAt = now, now
+	goal.CompletedAt = nil
+	if goal.Status == GoalCompleted {
+		goal.CompletedAt = &now
+	}
+	goal.Milestones = []GoalMilestone{}
+
+	res, err := s.db.Exec(`
+		INSERT INTO goals (uuid, title, why, target_date, status, completed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		goal.UUID, goal.Title, goal.Why, goal.TargetDate, goal.Status, goal.CompletedAt, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	goal.ID, err = res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalCreated, ID: goal.ID})
+	return &goal, nil
+}
+
+// Update changes a goal's title, why, target date and status
+func (s *sqlGoalStore) Update(goal Goal) error {
+	goal.Title = strings.TrimSpace(goal.Title)
+	if err := validateGoal(goal); err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(`
+		UPDATE goals
+		SET title = ?, why = ?, target_date = ?, updated_at = ?
+		WHERE id = ?`, goal.Title, goal.Why, goal.TargetDate, time.Now(), goal.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return s.SetStatus(goal.ID, goal.Status)
+}
+
+// SetStatus changes a goal's status, recording when it was completed
+func (s *sqlGoalStore) SetStatus(id int64, status string) error {
+	if err := validateGoalStatus(status); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	res, err := s.db.Exec(`
+		UPDATE goals
+		SET completed_at = CASE
+				WHEN ? != 'completed' THEN NULL
+				WHEN status = 'completed' THEN completed_at
+				ELSE ?
+			END,
+			status = ?, updated_at = ?
+		WHERE id = ?`, status, now, status, now, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalUpdated, ID: id})
+	return nil
+}
+
+// Delete deletes a goal with its milestones, check-ins and links. The
+// linked entries are kept.
+func (s *sqlGoalStore) Delete(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"goal_milestones", "goal_checkins", "goal_links"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE goal_id = ?`, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM goals WHERE id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalDeleted, ID: id})
+	return nil
+}
+
+// AddMilestone adds a milestone after a goal's other milestones
+func (s *sqlGoalStore) AddMilestone(goalID int64, title string, targetDate string) (*GoalMilestone, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("milestone title is required")
+	}
+	if targetDate != "" {
+		if _, err := time.Parse("2006-01-02", targetDate); err != nil {
+			return nil, fmt.Errorf("invalid target date %q, expected YYYY-MM-DD", targetDate)
+		}
+	}
+	if _, err := s.GetByID(goalID); err != nil {
+		return nil, err
+	}
+
+	var position int
+	err := s.db.QueryRow(`SELECT COALESCE(MAX(position), 0) + 1 FROM goal_milestones WHERE goal_id = ?`, goalID).Scan(&position)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	m := GoalMilestone{UUID: ids.New(), GoalID: goalID, Title: title, TargetDate: targetDate, Position: position, CreatedAt: now}
+	res, err := s.db.Exec(`
+		INSERT INTO goal_milestones (uuid, goal_id, title, target_date, position, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`, m.UUID, goalID, title, targetDate, position, now)
+	if err != nil {
+		return nil, err
+	}
+
+	m.ID, err = res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalUpdated, ID: goalID})
+	return &m, nil
+}
+
+// SetMilestoneDone marks a milestone as reached, or not
+func (s *sqlGoalStore) SetMilestoneDone(id int64, done bool) error {
+	var goalID int64
+	if err := s.db.QueryRow(`SELECT goal_id FROM goal_milestones WHERE id = ?`, id).Scan(&goalID); err != nil {
+		return err
+	}
+
+	var completedAt *time.Time
+	if done {
+		now := time.Now()
+		completedAt = &now
+	}
+	_, err := s.db.Exec(`UPDATE goal_milestones SET completed_at = ? WHERE id = ?`, completedAt, id)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalUpdated, ID: goalID})
+	return nil
+}
+
+// DeleteMilestone deletes a milestone
+func (s *sqlGoalStore) DeleteMilestone(id int64) error {
+	var goalID int64
+	if err := s.db.QueryRow(`SELECT goal_id FROM goal_milestones WHERE id = ?`, id).Scan(&goalID); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM goal_milestones WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalUpdated, ID: goalID})
+	return nil
+}
+
+// CheckIn records this week's progress towards a goal. Checking in again
+// in the same week replaces the week's check-in.
+func (s *sqlGoalStore) CheckIn(goalID int64, progress int, note string) (*GoalCheckIn, error) {
+	if progress < 0 || progress > 100 {
+		return nil, fmt.Errorf("progress must be between 0 and 100")
+	}
+	if _, err := s.GetByID(goalID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+	week := habitPeriod(FrequencyWeekly, today).Format("2006-01-02")
+
+	checkIn := GoalCheckIn{GoalID: goalID, Week: week, Progress: progress, Note: note, CreatedAt: now, UpdatedAt: now}
+	err := s.db.QueryRow(`
+		SELECT id, uuid, created_at FROM goal_checkins
+		WHERE goal_id = ? AND week = ?
+		ORDER BY id LIMIT 1`, goalID, week).Scan(&checkIn.ID, &checkIn.UUID, &checkIn.CreatedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		checkIn.UUID = ids.New()
+		res, err := s.db.Exec(`
+			INSERT INTO goal_checkins (uuid, goal_id, week, progress, note, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, checkIn.UUID, goalID, week, progress, note, now, now)
+		if err != nil {
+			return nil, err
+		}
+		if checkIn.ID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		_, err := s.db.Exec(`
+			UPDATE goal_checkins SET progress = ?, note = ?, updated_at = ?
+			WHERE id = ?`, progress, note, now, checkIn.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalCheckedIn, ID: goalID, Date: now.Format("2006-01-02")})
+	return &checkIn, nil
+}
+
+// GetCheckIns returns a goal's check-ins, oldest first
+func (s *sqlGoalStore) GetCheckIns(goalID int64) ([]GoalCheckIn, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, goal_id, week, progress, note, created_at, updated_at
+		FROM goal_checkins
+		WHERE goal_id = ?
+		ORDER BY week, id`, goalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkIns := []GoalCheckIn{}
+	for rows.Next() {
+		var c GoalCheckIn
+		if err := rows.Scan(&c.ID, &c.UUID, &c.GoalID, &c.Week, &c.Progress, &c.Note, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		checkIns = append(checkIns, c)
+	}
+	return checkIns, rows.Err()
+}
+
+// entryUUID returns the UUID of an entry that can be linked to a goal
+func (s *sqlGoalStore) entryUUID(entryType string, entryID int64) (string, error) {
+	table, ok := goalLinkTables[entryType]
+	if !ok {
+		return "", fmt.Errorf("entries of type %q can't be linked to goals", entryType)
+	}
+
+	var uuid string
+	err := s.db.QueryRow(`SELECT uuid FROM `+table+` WHERE id = ?`, entryID).Scan(&uuid)
+	return uuid, err
+}
+
+// Link links an entry to a goal. Linking it again does nothing.
+func (s *sqlGoalStore) Link(goalID int64, entryType string, entryID int64) error {
+	uuid, err := s.entryUUID(entryType, entryID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.GetByID(goalID); err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO goal_links (uuid, goal_id, entity_type, entity_uuid, created_at)
+		SELECT ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM goal_links WHERE goal_id = ? AND entity_type = ? AND entity_uuid = ?
+		)`, ids.New(), goalID, entryType, uuid, time.Now(), goalID, entryType, uuid)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalUpdated, ID: goalID})
+	return nil
+}
+
+// Unlink removes the link between an entry and a goal
+func (s *sqlGoalStore) Unlink(goalID int64, entryType string, entryID int64) error {
+	uuid, err := s.entryUUID(entryType, entryID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		DELETE FROM goal_links
+		WHERE goal_id = ? AND entity_type = ? AND entity_uuid = ?`, goalID, entryType, uuid)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GoalUpdated, ID: goalID})
+	return nil
+}
+
+// GetLinkedGoals returns the goals an entry is linked to
+func (s *sqlGoalStore) GetLinkedGoals(entryType string, entryID int64) ([]Goal, error) {
+	uuid, err := s.entryUUID(entryType, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+goalColumns+`
+		FROM goals
+		WHERE id IN (SELECT goal_id FROM goal_links WHERE entity_type = ? AND entity_uuid = ?)
+		ORDER BY id`, entryType, uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []Goal{}
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, *g)
+	}
+	return goals, rows.Err()
+}
+
+// GetTimeline returns everything written about a goal in the order it was
+// written: the goal itself, reached milestones, check-ins and linked entries
+func (s *sqlGoalStore) GetTimeline(goalID int64) ([]GoalTimelineItem, error) {
+	goal, err := s.GetByID(goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []GoalTimelineItem{{Type: "goal", ID: goal.ID, At: goal.CreatedAt, Title: goal.Title, Content: goal.Why}}
+	if goal.CompletedAt != nil {
+		items = append(items, GoalTimelineItem{Type: "goal", ID: goal.ID, At: *goal.CompletedAt, Title: "Completed: " + goal.Title})
+	}
+
+	for _, m := range goal.Milestones {
+		if m.CompletedAt != nil {
+			items = append(items, GoalTimelineItem{Type: "milestone", ID: m.ID, At: *m.CompletedAt, Title: m.Title})
+		}
+	}
+
+	checkIns, err := s.GetCheckIns(goalID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range checkIns {
+		items = append(items, GoalTimelineItem{
+			Type:    "checkin",
+			ID:      c.ID,
+			At:      c.UpdatedAt,
+			Title:   fmt.Sprintf("Week of %s: %d%%", c.Week, c.Progress),
+			Content: c.Note,
+		})
+	}
+
+	// Linked entries, with the date each was written for. Links to entries
+	// that have since been deleted are skipped.
+	linked := []struct {
+		entryType string
+		query     string
+	}{
+		{GoalLinkAnswer, `
+			SELECT a.id, '', q.content, a.content, a.created_at
+			FROM goal_links l
+			JOIN answers a ON a.uuid = l.entity_uuid
+			JOIN questions q ON q.id = a.question_id
+			WHERE l.goal_id = ? AND l.entity_type = 'answer'`},
+		{GoalLinkCreativity, `
+			SELECT c.id, c.entry_date, c.title, c.content, c.created_at
+			FROM goal_links l
+			JOIN creativity_entries c ON c.uuid = l.entity_uuid
+			WHERE l.goal_id = ? AND l.entity_type = 'creativity'`},
+		{GoalLinkGratitude, `
+			SELECT g.id, g.entry_date, '', g.content, g.created_at
+			FROM goal_links l
+			JOIN gratitude_items g ON g.uuid = l.entity_uuid
+			WHERE l.goal_id = ? AND l.entity_type = 'gratitude'`},
+	}
+	for _, l := range linked {
+		rows, err := s.db.Query(l.query, goalID)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			item := GoalTimelineItem{Type: l.entryType}
+			if err := rows.Scan(&item.ID, &item.Date, &item.Title, &item.Content, &item.At); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range items {
+		if items[i].Date == "" {
+			items[i].Date = items[i].At.Local().Format("2006-01-02")
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Date != items[j].Date {
+			return items[i].Date < items[j].Date
+		}
+		return items[i].At.Before(items[j].At)
+	})
+	return items, nil
+}
@@ -0,0 +1,188 @@
+// backend/models/goal_test.go
+package models
+
+import (
+	"testing"
+	"time"
+
+	"myproject/backend/ids"
+)
+
+func TestGoalModel(t *testing.T) {
+	t.Parallel()
+	stores, db := newTestStores(t)
+
+	goal, err := stores.Goals.Create(Goal{Title: "Run a half marathon", Why: "To feel strong", TargetDate: "2030-10-01"})
+	if err != nil {
+		t.Fatalf("Failed to create goal: %v", err)
+	}
+
+	// Test validation and defaults
+	t.Run("CreateGoal", func(t *testing.T) {
+		if goal.Status != GoalActive || goal.UUID == "" {
+			t.Errorf("Expected an active goal with a UUID, got %+v", goal)
+		}
+		if _, err := stores.Goals.Create(Goal{Title: "  "}); err == nil {
+			t.Error("Expected a goal without a title to be rejected")
+		}
+		if _, err := stores.Goals.Create(Goal{Title: "Learn Welsh", TargetDate: "next year"}); err == nil {
+			t.Error("Expected an invalid target date to be rejected")
+		}
+	})
+
+	// Test milestones and status changes
+	t.Run("Milestones", func(t *testing.T) {
+		first, err := stores.Goals.AddMilestone(goal.ID, "Run 5k", "")
+		if err != nil {
+			t.Fatalf("Failed to add milestone: %v", err)
+		}
+		if _, err := stores.Goals.AddMilestone(goal.ID, "Run 10k", ""); err != nil {
+			t.Fatalf("Failed to add milestone: %v", err)
+		}
+		if err := stores.Goals.SetMilestoneDone(first.ID, true); err != nil {
+			t.Fatalf("Failed to complete milestone: %v", err)
+		}
+
+		stored, err := stores.Goals.GetByID(goal.ID)
+		if err != nil {
+			t.Fatalf("Failed to get goal: %v", err)
+		}
+		if len(stored.Milestones) != 2 || stored.Milestones[0].Title != "Run 5k" || stored.Milestones[0].CompletedAt == nil {
+			t.Errorf("Expected 2 milestones with the first reached, got %+v", stored.Milestones)
+		}
+
+		if err := stores.Goals.SetStatus(goal.ID, "done"); err == nil {
+			t.Error("Expected an unknown status to be rejected")
+		}
+		if err := stores.Goals.SetStatus(goal.ID, GoalPaused); err != nil {
+			t.Fatalf("Failed to pause goal: %v", err)
+		}
+		paused, _ := stores.Goals.GetAll(GoalPaused)
+		active, _ := stores.Goals.GetAll(GoalActive)
+		if len(paused) != 1 || len(active) != 0 {
+			t.Errorf("Expected the goal listed as paused, got %d paused and %d active", len(paused), len(active))
+		}
+		stores.Goals.SetStatus(goal.ID, GoalActive)
+	})
+
+	// Test that a week's check-in is replaced by a later one that week
+	t.Run("CheckIns", func(t *testing.T) {
+		if _, err := stores.Goals.CheckIn(goal.ID, 120, ""); err == nil {
+			t.Error("Expected progress over 100 to be rejected")
+		}
+		first, err := stores.Goals.CheckIn(goal.ID, 20, "Three runs")
+		if err != nil {
+			t.Fatalf("Failed to check in: %v", err)
+		}
+		second, err := stores.Goals.CheckIn(goal.ID, 25, "Four runs")
+		if err != nil {
+			t.Fatalf("Failed to check in: %v", err)
+		}
+		if second.ID != first.ID {
+			t.Errorf("Expected the week's check-in to be updated, got IDs %d and %d", first.ID, second.ID)
+		}
+
+		// A check-in from an earlier week
+		earlier := time.Date(2020, 1, 8, 12, 0, 0, 0, time.Local)
+		_, err = db.Exec(`
+			INSERT INTO goal_checkins (uuid, goal_id, week, progress, note, created_at, updated_at)
+			VALUES (?, ?, '2020-01-06', 5, 'First run', ?, ?)`, ids.New(), goal.ID, earlier, earlier)
+		if err != nil {
+			t.Fatalf("Failed to insert check-in: %v", err)
+		}
+
+		checkIns, err := stores.Goals.GetCheckIns(goal.ID)
+		if err != nil {
+			t.Fatalf("Failed to get check-ins: %v", err)
+		}
+		if len(checkIns) != 2 || checkIns[0].Week != "2020-01-06" || checkIns[1].Progress != 25 {
+			t.Errorf("Expected 2 check-ins oldest first, got %+v", checkIns)
+		}
+	})
+
+	// Test that linked entries appear on the timeline in the order written
+	t.Run("Timeline", func(t *testing.T) {
+		question, err := stores.Questions.Add("What's one small step towards your goal?")
+		if err != nil {
+			t.Fatalf("Failed to add question: %v", err)
+		}
+		answer, err := stores.Answers.Create(question.ID, "Buy running shoes")
+		if err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+		entry, err := stores.Creativity.Save("Ode to the long run", "2020-01-01")
+		if err != nil {
+			t.Fatalf("Failed to save creativity entry: %v", err)
+		}
+		item, err := stores.Gratitude.Add("Legs that carry me")
+		if err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+
+		for _, link := range []struct {
+			entryType string
+			id        int64
+		}{{GoalLinkAnswer, answer.ID}, {GoalLinkCreativity, entry.ID}, {GoalLinkGratitude, item.ID}, {GoalLinkAnswer, answer.ID}} {
+			if err := stores.Goals.Link(goal.ID, link.entryType, link.id); err != nil {
+				t.Fatalf("Failed to link %s: %v", link.entryType, err)
+			}
+		}
+		if err := stores.Goals.Link(goal.ID, "affirmation", 1); err == nil {
+			t.Error("Expected an unknown entry type to be rejected")
+		}
+
+		goals, err := stores.Goals.GetLinkedGoals(GoalLinkAnswer, answer.ID)
+		if err != nil || len(goals) != 1 || goals[0].ID != goal.ID {
+			t.Errorf("Expected the answer linked to the goal, got %+v (%v)", goals, err)
+		}
+
+		timeline, err := stores.Goals.GetTimeline(goal.ID)
+		if err != nil {
+			t.Fatalf("Failed to get timeline: %v", err)
+		}
+
+		var types []string
+		for i, item := range timeline {
+			types = append(types, item.Type)
+			if i > 0 && item.Date < timeline[i-1].Date {
+				t.Errorf("Expected the timeline in chronological order, got %s before %s", timeline[i-1].Date, item.Date)
+			}
+		}
+		counts := map[string]int{}
+		for _, typ := range types {
+			counts[typ]++
+		}
+		if len(timeline) != 7 || counts["answer"] != 1 || counts["checkin"] != 2 || counts["milestone"] != 1 {
+			t.Errorf("Expected goal, milestone, 2 check-ins and 3 entries, got %v", types)
+		}
+		if timeline[0].Type != "creativity" || timeline[1].Type != "checkin" {
+			t.Errorf("Expected the backdated entries first, got %v", types)
+		}
+
+		// Deleting a linked entry drops it from the timeline
+		if err := stores.Answers.Delete(answer.ID); err != nil {
+			t.Fatalf("Failed to delete answer: %v", err)
+		}
+		timeline, _ = stores.Goals.GetTimeline(goal.ID)
+		if len(timeline) != 6 {
+			t.Errorf("Expected the deleted answer dropped, got %d items", len(timeline))
+		}
+	})
+
+	// Test that deleting a goal keeps the linked entries
+	t.Run("DeleteGoal", func(t *testing.T) {
+		if err := stores.Goals.Delete(goal.ID); err != nil {
+			t.Fatalf("Failed to delete goal: %v", err)
+		}
+		if _, err := stores.Goals.GetByID(goal.ID); err == nil {
+			t.Error("Expected the goal to be deleted")
+		}
+
+		var links int
+		db.QueryRow(`SELECT COUNT(*) FROM goal_links`).Scan(&links)
+		items, _ := stores.Gratitude.GetToday()
+		if links != 0 || len(items) != 1 {
+			t.Errorf("Expected links deleted and entries kept, got %d links and %d items", links, len(items))
+		}
+	})
+}
@@ -2,14 +2,18 @@
 package models
 
 import (
+	"database/sql"
 	"errors"
 	"time"
 
-	"myproject/backend/database"
+	"myproject/backend/events"
+	"myproject/backend/ids"
+	"myproject/backend/sentiment"
 )
 
 type GratitudeItem struct {
 	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
 	Content   string    `json:"content"`
 	EntryDate string    `json:"entryDate"` // Store the date in YYYY-MM-DD format
 	CreatedAt time.Time `json:"createdAt"`
@@ -20,27 +24,38 @@ type GratitudeEntry struct {
 	Items []GratitudeItem `json:"items"`
 }
 
-// Initialize the gratitude table in the database
-func InitGratitudeTable() error {
-	_, err := database.DB.Exec(`
-	CREATE TABLE IF NOT EXISTS gratitude_items (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		content TEXT NOT NULL,
-		entry_date TEXT NOT NULL, 
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	)`)
-
-	return err
+// GratitudeStore manages gratitude journal items
+type GratitudeStore interface {
+	Add(content string) (*GratitudeItem, error)
+	GetToday() ([]GratitudeItem, error)
+	GetByDate(date string) ([]GratitudeItem, error)
+	HasToday() (bool, error)
+	CountToday() (int, error)
+	GetAllEntries() ([]GratitudeEntry, error)
+	Update(id int64, content string) error
+	Delete(id int64) error
+	GetLastNDays(n int) ([]GratitudeEntry, error)
+	GetStreak() (int, error)
 }
 
-// AddGratitudeItem adds a new gratitude item for today
-func AddGratitudeItem(content string) (*GratitudeItem, error) {
+type sqlGratitudeStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewGratitudeStore creates a GratitudeStore backed by db
+func NewGratitudeStore(db *sql.DB, bus *events.Bus) GratitudeStore {
+	return &sqlGratitudeStore{db: db, bus: bus}
+}
+
+// Add adds a new gratitude item for today
+func (s *sqlGratitudeStore) Add(content string) (*GratitudeItem, error) {
 	// Get today's date in YYYY-MM-DD format
 	today := time.Now().Format("2006-01-02")
 
 	// Check how many entries we already have for today
 	var count int
-	err := database.DB.QueryRow(`
+	err := s.db.QueryRow(`
 		SELECT COUNT(*) FROM gratitude_items 
 		WHERE entry_date = ?`, today).Scan(&count)
 
@@ -54,9 +69,10 @@ func AddGratitudeItem(content string) (*GratitudeItem, error) {
 	}
 
 	// Insert the new gratitude item
-	res, err := database.DB.Exec(`
-		INSERT INTO gratitude_items (content, entry_date) 
-		VALUES (?, ?)`, content, today)
+	uuid := ids.New()
+	res, err := s.db.Exec(`
+		INSERT INTO gratitude_items (uuid, content, entry_date, sentiment) 
+		VALUES (?, ?, ?, ?)`, uuid, content, today, sentiment.Score(content))
 
 	if err != nil {
 		return nil, err
@@ -67,24 +83,32 @@ func AddGratitudeItem(content string) (*GratitudeItem, error) {
 		return nil, err
 	}
 
+	if err := indexEntry(s.db, EntryGratitude, id); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GratitudeCreated, ID: id, Date: today})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "gratitude"})
+
 	return &GratitudeItem{
 		ID:        id,
+		UUID:      uuid,
 		Content:   content,
 		EntryDate: today,
 		CreatedAt: time.Now(),
 	}, nil
 }
 
-// GetTodayGratitudeItems gets all gratitude items for today
-func GetTodayGratitudeItems() ([]GratitudeItem, error) {
+// GetToday gets all gratitude items for today
+func (s *sqlGratitudeStore) GetToday() ([]GratitudeItem, error) {
 	today := time.Now().Format("2006-01-02")
-	return GetGratitudeItemsByDate(today)
+	return s.GetByDate(today)
 }
 
-// GetGratitudeItemsByDate gets all gratitude items for a specific date
-func GetGratitudeItemsByDate(date string) ([]GratitudeItem, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, content, entry_date, created_at 
+// GetByDate gets all gratitude items for a specific date
+func (s *sqlGratitudeStore) GetByDate(date string) ([]GratitudeItem, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, content, entry_date, created_at 
 		FROM gratitude_items 
 		WHERE entry_date = ? 
 		ORDER BY created_at ASC`, date)
@@ -97,7 +121,7 @@ func GetGratitudeItemsByDate(date string) ([]GratitudeItem, error) {
 	var items []GratitudeItem
 	for rows.Next() {
 		var item GratitudeItem
-		err := rows.Scan(&item.ID, &item.Content, &item.EntryDate, &item.CreatedAt)
+		err := rows.Scan(&item.ID, &item.UUID, &item.Content, &item.EntryDate, &item.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -107,12 +131,12 @@ func GetGratitudeItemsByDate(date string) ([]GratitudeItem, error) {
 	return items, nil
 }
 
-// HasTodayGratitudeEntries checks if there are any entries for today
-func HasTodayGratitudeEntries() (bool, error) {
+// HasToday checks if there are any entries for today
+func (s *sqlGratitudeStore) HasToday() (bool, error) {
 	today := time.Now().Format("2006-01-02")
 
 	var count int
-	err := database.DB.QueryRow(`
+	err := s.db.QueryRow(`
 		SELECT COUNT(*) FROM gratitude_items 
 		WHERE entry_date = ?`, today).Scan(&count)
 
@@ -123,12 +147,12 @@ func HasTodayGratitudeEntries() (bool, error) {
 	return count > 0, nil
 }
 
-// CountTodayGratitudeEntries counts the number of entries for today
-func CountTodayGratitudeEntries() (int, error) {
+// CountToday counts the number of entries for today
+func (s *sqlGratitudeStore) CountToday() (int, error) {
 	today := time.Now().Format("2006-01-02")
 
 	var count int
-	err := database.DB.QueryRow(`
+	err := s.db.QueryRow(`
 		SELECT COUNT(*) FROM gratitude_items 
 		WHERE entry_date = ?`, today).Scan(&count)
 
@@ -139,10 +163,10 @@ func CountTodayGratitudeEntries() (int, error) {
 	return count, nil
 }
 
-// GetAllGratitudeEntries gets all gratitude entries grouped by date
-func GetAllGratitudeEntries() ([]GratitudeEntry, error) {
+// GetAllEntries gets all gratitude entries grouped by date
+func (s *sqlGratitudeStore) GetAllEntries() ([]GratitudeEntry, error) {
 	// First, get distinct dates
-	rows, err := database.DB.Query(`
+	rows, err := s.db.Query(`
 		SELECT DISTINCT entry_date 
 		FROM gratitude_items 
 		ORDER BY entry_date DESC`)
@@ -164,7 +188,7 @@ func GetAllGratitudeEntries() ([]GratitudeEntry, error) {
 	// Now get items for each date
 	var entries []GratitudeEntry
 	for _, date := range dates {
-		items, err := GetGratitudeItemsByDate(date)
+		items, err := s.GetByDate(date)
 		if err != nil {
 			return nil, err
 		}
@@ -178,29 +202,43 @@ func GetAllGratitudeEntries() ([]GratitudeEntry, error) {
 	return entries, nil
 }
 
-// UpdateGratitudeItem updates a gratitude item
-func UpdateGratitudeItem(id int64, content string) error {
-	_, err := database.DB.Exec(`
+// Update updates a gratitude item
+func (s *sqlGratitudeStore) Update(id int64, content string) error {
+	_, err := s.db.Exec(`
 		UPDATE gratitude_items 
-		SET content = ? 
-		WHERE id = ?`, content, id)
+		SET content = ?, sentiment = ? 
+		WHERE id = ?`, content, sentiment.Score(content), id)
 
-	return err
+	if err != nil {
+		return err
+	}
+	if err := indexEntry(s.db, EntryGratitude, id); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GratitudeUpdated, ID: id})
+	return nil
 }
 
-// DeleteGratitudeItem deletes a gratitude item
-func DeleteGratitudeItem(id int64) error {
-	_, err := database.DB.Exec(`
+// Delete deletes a gratitude item
+func (s *sqlGratitudeStore) Delete(id int64) error {
+	_, err := s.db.Exec(`
 		DELETE FROM gratitude_items 
 		WHERE id = ?`, id)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.GratitudeDeleted, ID: id})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "gratitude"})
+	return nil
 }
 
-// GetLastNDaysWithGratitude gets entries for the last n days
-func GetLastNDaysWithGratitude(n int) ([]GratitudeEntry, error) {
+// GetLastNDays gets entries for the last n days
+func (s *sqlGratitudeStore) GetLastNDays(n int) ([]GratitudeEntry, error) {
 	// Get the last n distinct dates with entries
-	rows, err := database.DB.Query(`
+	rows, err := s.db.Query(`
 		SELECT DISTINCT entry_date 
 		FROM gratitude_items 
 		ORDER BY entry_date DESC 
@@ -223,7 +261,7 @@ func GetLastNDaysWithGratitude(n int) ([]GratitudeEntry, error) {
 	// Now get items for each date
 	var entries []GratitudeEntry
 	for _, date := range dates {
-		items, err := GetGratitudeItemsByDate(date)
+		items, err := s.GetByDate(date)
 		if err != nil {
 			return nil, err
 		}
@@ -237,9 +275,9 @@ func GetLastNDaysWithGratitude(n int) ([]GratitudeEntry, error) {
 	return entries, nil
 }
 
-// GetGratitudeStreak calculates the current streak of consecutive days with gratitude entries
-func GetGratitudeStreak() (int, error) {
-	rows, err := database.DB.Query(`
+// GetStreak calculates the current streak of consecutive days with gratitude entries
+func (s *sqlGratitudeStore) GetStreak() (int, error) {
+	rows, err := s.db.Query(`
 		SELECT DISTINCT entry_date
 		FROM gratitude_items
 		ORDER BY entry_date DESC`)
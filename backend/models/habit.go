@@ -0,0 +1,534 @@
+// backend/models/habit.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// Habit frequencies
+const (
+	FrequencyDaily  = "daily"  // Target check-ins every day
+	FrequencyWeekly = "weekly" // Target check-ins every week, starting on Monday
+)
+
+// BuiltinAffirmation names the built-in habit of completing the affirmation.
+// Its check-ins are the affirmation logs.
+const BuiltinAffirmation = "affirmation"
+
+// Habit is something the user wants to do regularly
+type Habit struct {
+	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
+	Builtin   string    `json:"builtin"` // Empty for user-defined habits
+	Name      string    `json:"name"`
+	Frequency string    `json:"frequency"` // "daily" or "weekly"
+	Target    int       `json:"target"`    // Check-ins needed per day or week
+	Unit      string    `json:"unit"`      // What quantities are measured in, e.g. "minutes"
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// HabitLog is one check-in of a habit
+type HabitLog struct {
+	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
+	HabitID   int64     `json:"habitId"`
+	Date      string    `json:"date"`     // YYYY-MM-DD
+	Quantity  *float64  `json:"quantity"` // Optional, e.g. minutes meditated
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// HabitStats summarises how well a habit has been kept. Streaks count
+// consecutive days or weeks that met the target, up to today. The other
+// figures cover the requested range, which is clamped to when the habit
+// was started.
+type HabitStats struct {
+	HabitID        int64     `json:"habitId"`
+	Range          DateRange `json:"range"`
+	CurrentStreak  int       `json:"currentStreak"` // A day or week still in progress doesn't break it
+	LongestStreak  int       `json:"longestStreak"`
+	Completions    int       `json:"completions"`
+	Quantity       float64   `json:"quantity"`
+	Periods        int       `json:"periods"` // Days or weeks in the range, less one still in progress and not yet met
+	PeriodsMet     int       `json:"periodsMet"`
+	CompletionRate float64   `json:"completionRate"` // PeriodsMet / Periods, from 0 to 1
+}
+
+// HabitStore manages habits and their check-ins
+type HabitStore interface {
+	GetAll(includeArchived bool) ([]Habit, error)
+	GetByID(id int64) (*Habit, error)
+	Create(habit Habit) (*Habit, error)
+	Update(habit Habit) error
+	SetArchived(id int64, archived bool) error
+	Delete(id int64) error
+	Log(habitID int64, date string, quantity *float64, note string) (*HabitLog, error)
+	GetLogs(habitID int64, r DateRange) ([]HabitLog, error)
+	DeleteLog(habitID int64, logID int64) error
+	GetStats(habitID int64, r DateRange) (*HabitStats, error)
+}
+
+type sqlHabitStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewHabitStore creates a HabitStore backed by db
+func NewHabitStore(db *sql.DB, bus *events.Bus) HabitStore {
+	return &sqlHabitStore{db: db, bus: bus}
+}
+
+const habitColumns = `id, uuid, COALESCE(builtin, ''), name, frequency, target, unit, archived, created_at, updated_at`
+
+func scanHabit(row interface{ Scan(...any) error }) (*Habit, error) {
+	var h Habit
+	err := row.Scan(&h.ID, &h.UUID, &h.Builtin, &h.Name, &h.Frequency, &h.Target, &h.Unit, &h.Archived, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// validateHabit checks the fields the user can set
+func validateHabit(h Habit) error {
+	if strings.TrimSpace(h.Name) == "" {
+		return fmt.Errorf("habit name is required")
+	}
+	if h.Frequency != FrequencyDaily && h.Frequency != FrequencyWeekly {
+		return fmt.Errorf("unknown habit frequency %q", h.Frequency)
+	}
+	if h.Target < 1 {
+		return fmt.Errorf("habit target must be at least 1")
+	}
+	return nil
+}
+
+// GetAll returns the habits in the order they were created
+func (s *sqlHabitStore) GetAll(includeArchived bool) ([]Habit, error) {
+	rows, err := s.db.Query(`
+		SELECT `+habitColumns+`
+		FROM habits
+		WHERE archived = 0 OR ?
+		ORDER BY id`, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	habits := []Habit{}
+	for rows.Next() {
+		h, err := scanHabit(rows)
+		if err != nil {
+			return nil, err
+		}
+		habits = append(habits, *h)
+	}
+	return habits, rows.Err()
+}
+
+// GetByID returns a habit
+func (s *sqlHabitStore) GetByID(id int64) (*Habit, error) {
+	return scanHabit(s.db.QueryRow(`SELECT `+habitColumns+` FROM habits WHERE id = ?`, id))
+}
+
+// Create adds a user-defined habit. Frequency defaults to daily and the
+// target to once.
+func (s *sqlHabitStore) Create(habit Habit) (*Habit, error) {
+	if habit.Frequency == "" {
+		habit.Frequency = FrequencyDaily
+	}
+	if habit.Target == 0 {
+		habit.Target = 1
+	}
+	habit.Name = strings.TrimSpace(habit.Name)
+	if err := validateHabit(habit); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	habit.UUID = ids.New()
+	habit.Builtin = ""
+	habit.Archived = false
+	habit.CreatedAt, habit.UpdatedAt = now, now
+
+	res, err := s.db.Exec(`
+		INSERT INTO habits (uuid, name, frequency, target, unit, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		habit.UUID, habit.Name, habit.Frequency, habit.Target, habit.Unit, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	habit.ID, err = res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.HabitCreated, ID: habit.ID})
+	return &habit, nil
+}
+
+// Update changes a habit's name, frequency, target and unit
+func (s *sqlHabitStore) Update(habit Habit) error {
+	habit.Name = strings.TrimSpace(habit.Name)
+	if err := validateHabit(habit); err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(`
+		UPDATE habits
+		SET name = ?, frequency = ?, target = ?, unit = ?, updated_at = ?
+		WHERE id = ?`,
+		habit.Name, habit.Frequency, habit.Target, habit.Unit, time.Now(), habit.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.bus.Publish(events.Event{Type: events.HabitUpdated, ID: habit.ID})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "habit", ID: habit.ID})
+	return nil
+}
+
+// SetArchived hides a habit from the list of current habits, or brings it back.
+// Archived habits keep their check-ins.
+func (s *sqlHabitStore) SetArchived(id int64, archived bool) error {
+	res, err := s.db.Exec(`UPDATE habits SET archived = ?, updated_at = ? WHERE id = ?`, archived, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.bus.Publish(events.Event{Type: events.HabitUpdated, ID: id})
+	return nil
+}
+
+// Delete deletes a habit and its check-ins. Built-in habits can only be archived.
+func (s *sqlHabitStore) Delete(id int64) error {
+	habit, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if habit.Builtin != "" {
+		return fmt.Errorf("the %s habit is built in and can't be deleted", habit.Name)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM habit_logs WHERE habit_id = ?`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM habits WHERE id = ?`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.HabitDeleted, ID: id})
+	return nil
+}
+
+// Log records a check-in of a habit on a date (YYYY-MM-DD), defaulting to
+// today. Checking in the affirmation habit logs the active affirmation.
+func (s *sqlHabitStore) Log(habitID int64, date string, quantity *float64, note string) (*HabitLog, error) {
+	today := time.Now().Format("2006-01-02")
+	if date == "" {
+		date = today
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+
+	habit, err := s.GetByID(habitID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	log := HabitLog{UUID: ids.New(), HabitID: habitID, Date: date, Quantity: quantity, Note: note, CreatedAt: now}
+
+	if habit.Builtin == BuiltinAffirmation {
+		if quantity != nil || note != "" {
+			return nil, fmt.Errorf("affirmation check-ins don't record a quantity or note")
+		}
+		affirmation, err := NewAffirmationStore(s.db, nil).GetActive()
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("there is no affirmation to complete")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Check-ins for today keep the time, like LogCompletion. Earlier
+		// days are stored at noon, so they stay on the chosen day when read
+		// back in local time.
+		completedAt := date + " 12:00:00"
+		if date == today {
+			completedAt = now.Format("2006-01-02 15:04:05")
+		}
+		res, err := s.db.Exec(`
+			INSERT INTO affirmation_logs (uuid, affirmation_id, completed_at)
+			VALUES (?, ?, ?)`, log.UUID, affirmation.ID, completedAt)
+		if err != nil {
+			return nil, err
+		}
+		if log.ID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+
+		s.bus.Publish(events.Event{Type: events.AffirmationLogged, ID: log.ID, Date: date})
+		s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "affirmation"})
+	} else {
+		res, err := s.db.Exec(`
+			INSERT INTO habit_logs (uuid, habit_id, log_date, quantity, note, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`, log.UUID, habitID, date, quantity, note, now)
+		if err != nil {
+			return nil, err
+		}
+		if log.ID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.bus.Publish(events.Event{Type: events.HabitLogged, ID: habitID, Date: date})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "habit", ID: habitID})
+	return &log, nil
+}
+
+// GetLogs returns a habit's check-ins in a date range, newest first
+func (s *sqlHabitStore) GetLogs(habitID int64, r DateRange) ([]HabitLog, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	habit, err := s.GetByID(habitID)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := habitLogs(s.db, habit)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []HabitLog{}
+	for _, l := range all {
+		if r.Contains(l.Date) {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+// DeleteLog deletes one of a habit's check-ins
+func (s *sqlHabitStore) DeleteLog(habitID int64, logID int64) error {
+	habit, err := s.GetByID(habitID)
+	if err != nil {
+		return err
+	}
+
+	if habit.Builtin == BuiltinAffirmation {
+		if err := NewAffirmationStore(s.db, s.bus).DeleteLog(logID); err != nil {
+			return err
+		}
+	} else {
+		res, err := s.db.Exec(`DELETE FROM habit_logs WHERE id = ? AND habit_id = ?`, logID, habitID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	s.bus.Publish(events.Event{Type: events.HabitLogDeleted, ID: logID})
+	s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "habit", ID: habitID})
+	return nil
+}
+
+// GetStats computes a habit's streaks and completion rate
+func (s *sqlHabitStore) GetStats(habitID int64, r DateRange) (*HabitStats, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	habit, err := s.GetByID(habitID)
+	if err != nil {
+		return nil, err
+	}
+	return habitStats(s.db, habit, r, time.Now())
+}
+
+// habitLogs reads all of a habit's check-ins, newest first
+func habitLogs(db *sql.DB, habit *Habit) ([]HabitLog, error) {
+	var rows *sql.Rows
+	var err error
+	if habit.Builtin == BuiltinAffirmation {
+		// Matches the dates CheckToday and GetStreak have always used
+		rows, err = db.Query(`
+			SELECT id, uuid, date(completed_at, 'localtime'), NULL, '', completed_at
+			FROM affirmation_logs
+			ORDER BY completed_at DESC, id DESC`)
+	} else {
+		rows, err = db.Query(`
+			SELECT id, uuid, log_date, quantity, note, created_at
+			FROM habit_logs
+			WHERE habit_id = ?
+			ORDER BY log_date DESC, id DESC`, habit.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []HabitLog{}
+	for rows.Next() {
+		l := HabitLog{HabitID: habit.ID}
+		if err := rows.Scan(&l.ID, &l.UUID, &l.Date, &l.Quantity, &l.Note, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+// habitStats computes the stats of a habit as of now
+func habitStats(db *sql.DB, habit *Habit, r DateRange, now time.Time) (*HabitStats, error) {
+	logs, err := habitLogs(db, habit)
+	if err != nil {
+		return nil, err
+	}
+
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+
+	// The habit started when it was created, or at its earliest check-in
+	// if that was logged for an earlier day
+	started := habit.CreatedAt.Local().Format("2006-01-02")
+	counts := map[string]int{}
+	for _, l := range logs {
+		if l.Date < started {
+			started = l.Date
+		}
+		date, err := time.Parse("2006-01-02", l.Date)
+		if err != nil {
+			continue
+		}
+		counts[habitPeriod(habit.Frequency, date).Format("2006-01-02")]++
+	}
+
+	stats := &HabitStats{HabitID: habit.ID, Range: r}
+	first, _ := time.Parse("2006-01-02", started)
+	stats.CurrentStreak, stats.LongestStreak = habitStreaks(habit.Frequency, habit.Target, counts, first, today)
+
+	// Clamp the range to the life of the habit
+	from, to := r.From, r.To
+	if from == "" || from < started {
+		from = started
+	}
+	if to == "" || to > today.Format("2006-01-02") {
+		to = today.Format("2006-01-02")
+	}
+	stats.Range = DateRange{From: from, To: to}
+	if from > to {
+		return stats, nil
+	}
+
+	inRange := map[string]int{}
+	for _, l := range logs {
+		if !stats.Range.Contains(l.Date) {
+			continue
+		}
+		stats.Completions++
+		if l.Quantity != nil {
+			stats.Quantity += *l.Quantity
+		}
+		date, _ := time.Parse("2006-01-02", l.Date)
+		inRange[habitPeriod(habit.Frequency, date).Format("2006-01-02")]++
+	}
+
+	start, _ := time.Parse("2006-01-02", from)
+	end, _ := time.Parse("2006-01-02", to)
+	current := habitPeriod(habit.Frequency, today)
+	for p := habitPeriod(habit.Frequency, start); !p.After(end); p = nextPeriod(habit.Frequency, p) {
+		met := inRange[p.Format("2006-01-02")] >= habit.Target
+		if !met && p.Equal(current) {
+			// There's still time to meet today's or this week's target
+			continue
+		}
+		stats.Periods++
+		if met {
+			stats.PeriodsMet++
+		}
+	}
+	if stats.Periods > 0 {
+		stats.CompletionRate = float64(stats.PeriodsMet) / float64(stats.Periods)
+	}
+	return stats, nil
+}
+
+// habitPeriod returns the first day of the period a date falls in: the day
+// itself for daily habits, or the Monday of its week for weekly ones
+func habitPeriod(frequency string, date time.Time) time.Time {
+	if frequency == FrequencyWeekly {
+		return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	}
+	return date
+}
+
+// nextPeriod returns the first day of the period after the one starting on start
+func nextPeriod(frequency string, start time.Time) time.Time {
+	if frequency == FrequencyWeekly {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// habitStreaks returns the current and longest runs of periods from first
+// to today with at least target check-ins. counts holds the check-ins per
+// period, keyed by the period's first day. The current period only adds to
+// the current streak once it is met; until then the streak runs up to the
+// period before.
+func habitStreaks(frequency string, target int, counts map[string]int, first time.Time, today time.Time) (current int, longest int) {
+	if len(counts) == 0 {
+		return 0, 0
+	}
+
+	run, before := 0, 0
+	last := habitPeriod(frequency, today)
+	for p := habitPeriod(frequency, first); !p.After(last); p = nextPeriod(frequency, p) {
+		before = run
+		if counts[p.Format("2006-01-02")] >= target {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	if run == 0 {
+		return before, longest
+	}
+	return run, longest
+}
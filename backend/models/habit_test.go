@@ -0,0 +1,190 @@
+// backend/models/habit_test.go
+package models
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// westOfUTC is a zone where a date stored without a time falls on the
+// previous day when read back in local time
+const westOfUTC = "America/Los_Angeles"
+
+// TestHabitModelWestOfUTC runs the habit tests again in a zone behind UTC.
+// SQLite reads the zone when the process starts, so they run in a new process.
+func TestHabitModelWestOfUTC(t *testing.T) {
+	if os.Getenv("TZ") == westOfUTC {
+		t.Skip("Already running west of UTC")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHabitModel$")
+	cmd.Env = append(os.Environ(), "TZ="+westOfUTC)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed habit tests in %s: %v\n%s", westOfUTC, err, out)
+	}
+}
+
+func TestHabitModel(t *testing.T) {
+	t.Parallel()
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	// Test streaks over days and weeks, where the current period in
+	// progress doesn't break the streak until it's over
+	t.Run("Streaks", func(t *testing.T) {
+		today := date("2024-05-15") // A Wednesday
+
+		daily := map[string]int{"2024-05-10": 1, "2024-05-11": 1, "2024-05-12": 1, "2024-05-14": 1}
+		current, longest := habitStreaks(FrequencyDaily, 1, daily, date("2024-05-10"), today)
+		if current != 1 || longest != 3 {
+			t.Errorf("Expected current streak 1 and longest 3, got %d and %d", current, longest)
+		}
+
+		daily["2024-05-15"] = 1
+		if current, _ := habitStreaks(FrequencyDaily, 1, daily, date("2024-05-10"), today); current != 2 {
+			t.Errorf("Expected today to extend the streak to 2, got %d", current)
+		}
+
+		// Three times a week, keyed by Monday
+		weekly := map[string]int{"2024-04-29": 3, "2024-05-06": 4, "2024-05-13": 1}
+		current, longest = habitStreaks(FrequencyWeekly, 3, weekly, date("2024-04-29"), today)
+		if current != 2 || longest != 2 {
+			t.Errorf("Expected weekly streaks of 2, got %d and %d", current, longest)
+		}
+
+		if current, longest := habitStreaks(FrequencyDaily, 1, map[string]int{}, today, today); current != 0 || longest != 0 {
+			t.Errorf("Expected no streak without check-ins, got %d and %d", current, longest)
+		}
+	})
+
+	// Test creating, logging and measuring a user-defined habit
+	t.Run("Habits", func(t *testing.T) {
+		stores, _ := newTestStores(t)
+
+		if _, err := stores.Habits.Create(Habit{Name: "Read", Frequency: "monthly"}); err == nil {
+			t.Error("Expected an unknown frequency to be rejected")
+		}
+
+		habit, err := stores.Habits.Create(Habit{Name: "Meditate", Unit: "minutes"})
+		if err != nil {
+			t.Fatalf("Failed to create habit: %v", err)
+		}
+		if habit.Frequency != FrequencyDaily || habit.Target != 1 || habit.UUID == "" {
+			t.Errorf("Expected a daily habit with a target of 1, got %+v", habit)
+		}
+
+		today := time.Now()
+		ten, twenty := 10.0, 20.0
+		for _, log := range []struct {
+			date     string
+			quantity *float64
+		}{
+			{today.Format("2006-01-02"), &ten},
+			{today.AddDate(0, 0, -1).Format("2006-01-02"), &twenty},
+			{today.AddDate(0, 0, -3).Format("2006-01-02"), nil},
+		} {
+			if _, err := stores.Habits.Log(habit.ID, log.date, log.quantity, ""); err != nil {
+				t.Fatalf("Failed to log habit: %v", err)
+			}
+		}
+
+		stats, err := stores.Habits.GetStats(habit.ID, DateRange{})
+		if err != nil {
+			t.Fatalf("Failed to get habit stats: %v", err)
+		}
+		if stats.CurrentStreak != 2 || stats.LongestStreak != 2 {
+			t.Errorf("Expected streaks of 2, got %d and %d", stats.CurrentStreak, stats.LongestStreak)
+		}
+		if stats.Completions != 3 || stats.Quantity != 30 {
+			t.Errorf("Expected 3 completions totalling 30, got %d and %v", stats.Completions, stats.Quantity)
+		}
+		if stats.Periods != 4 || stats.PeriodsMet != 3 || stats.CompletionRate != 0.75 {
+			t.Errorf("Expected 3 of 4 days met, got %d of %d (%v)", stats.PeriodsMet, stats.Periods, stats.CompletionRate)
+		}
+
+		logs, err := stores.Habits.GetLogs(habit.ID, DateRange{From: today.AddDate(0, 0, -1).Format("2006-01-02")})
+		if err != nil {
+			t.Fatalf("Failed to get habit logs: %v", err)
+		}
+		if len(logs) != 2 || logs[0].Quantity == nil || *logs[0].Quantity != 10 {
+			t.Fatalf("Expected 2 logs in range, newest first, got %+v", logs)
+		}
+
+		if err := stores.Habits.DeleteLog(habit.ID, logs[0].ID); err != nil {
+			t.Fatalf("Failed to delete habit log: %v", err)
+		}
+		stats, _ = stores.Habits.GetStats(habit.ID, DateRange{})
+		if stats.CurrentStreak != 1 {
+			t.Errorf("Expected today's missing check-in not to break the streak, got %d", stats.CurrentStreak)
+		}
+
+		if err := stores.Habits.SetArchived(habit.ID, true); err != nil {
+			t.Fatalf("Failed to archive habit: %v", err)
+		}
+		current, _ := stores.Habits.GetAll(false)
+		all, _ := stores.Habits.GetAll(true)
+		if len(current) != 1 || len(all) != 2 {
+			t.Errorf("Expected the archived habit hidden, got %d current and %d in all", len(current), len(all))
+		}
+
+		if err := stores.Habits.Delete(habit.ID); err != nil {
+			t.Fatalf("Failed to delete habit: %v", err)
+		}
+		if _, err := stores.Habits.GetByID(habit.ID); err == nil {
+			t.Error("Expected the habit to be deleted")
+		}
+	})
+
+	// Test that the affirmation habit is kept through the affirmation logs
+	t.Run("Affirmation", func(t *testing.T) {
+		stores, _ := newTestStores(t)
+
+		habits, err := stores.Habits.GetAll(false)
+		if err != nil {
+			t.Fatalf("Failed to get habits: %v", err)
+		}
+		if len(habits) != 1 || habits[0].Builtin != BuiltinAffirmation {
+			t.Fatalf("Expected the built-in affirmation habit, got %+v", habits)
+		}
+		habit := habits[0]
+
+		if _, err := stores.Habits.Log(habit.ID, "", nil, ""); err == nil {
+			t.Error("Expected logging without an affirmation to fail")
+		}
+
+		affirmation, err := stores.Affirmations.Save("I keep my promises")
+		if err != nil {
+			t.Fatalf("Failed to save affirmation: %v", err)
+		}
+		if err := stores.Affirmations.LogCompletion(affirmation.ID); err != nil {
+			t.Fatalf("Failed to log affirmation: %v", err)
+		}
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		if _, err := stores.Habits.Log(habit.ID, yesterday, nil, ""); err != nil {
+			t.Fatalf("Failed to log habit: %v", err)
+		}
+
+		logs, _ := stores.Affirmations.GetAllLogs()
+		if len(logs) != 2 {
+			t.Errorf("Expected both check-ins in the affirmation logs, got %d", len(logs))
+		}
+
+		stats, err := stores.Habits.GetStats(habit.ID, DateRange{})
+		if err != nil {
+			t.Fatalf("Failed to get habit stats: %v", err)
+		}
+		streak, _ := stores.Affirmations.GetStreak()
+		if stats.CurrentStreak != 2 || streak != 2 {
+			t.Errorf("Expected a streak of 2 both ways, got %d and %d", stats.CurrentStreak, streak)
+		}
+
+		if err := stores.Habits.Delete(habit.ID); err == nil {
+			t.Error("Expected the built-in habit not to be deletable")
+		}
+	})
+}
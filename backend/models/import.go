@@ -0,0 +1,115 @@
+// backend/models/import.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// ImportedEntry is an entry brought in from another journaling app
+type ImportedEntry struct {
+	Type      string // EntryAnswer or EntryCreativity
+	Question  string // The question an answer belongs to, added if the journal doesn't have it
+	Content   string
+	Date      string    // YYYY-MM-DD
+	CreatedAt time.Time // Kept from the source
+}
+
+// ImportStore writes entries brought in from other journaling apps
+type ImportStore interface {
+	Import(entries []ImportedEntry) error
+}
+
+type sqlImportStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewImportStore creates an ImportStore backed by db
+func NewImportStore(db *sql.DB, bus *events.Bus) ImportStore {
+	return &sqlImportStore{db: db, bus: bus}
+}
+
+// Import writes entries in one transaction, so an import that fails part
+// way leaves nothing behind and can be run again without duplicating entries
+func (s *sqlImportStore) Import(entries []ImportedEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var published []events.Event
+	questions := map[string]int64{}
+	for _, e := range entries {
+		switch e.Type {
+		case EntryAnswer:
+			questionID, ok := questions[e.Question]
+			if !ok {
+				var added bool
+				questionID, added, err = findOrAddQuestion(tx, e.Question)
+				if err != nil {
+					return err
+				}
+				if added {
+					published = append(published, events.Event{Type: events.QuestionCreated, ID: questionID})
+				}
+				questions[e.Question] = questionID
+			}
+
+			answer, err := createAnswer(tx, questionID, e.Content, nil, e.CreatedAt)
+			if err != nil {
+				return err
+			}
+			published = append(published, events.Event{Type: events.AnswerCreated, ID: answer.ID, Date: e.CreatedAt.Format("2006-01-02")})
+		case EntryCreativity:
+			entry, err := importCreativity(tx, e.Content, e.Date, e.CreatedAt)
+			if err != nil {
+				return err
+			}
+			published = append(published, events.Event{Type: events.CreativityCreated, ID: entry.ID, Date: e.Date})
+		default:
+			return fmt.Errorf("can't import %q entries", e.Type)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, e := range published {
+		s.bus.Publish(e)
+	}
+	for _, e := range published {
+		if e.Type == events.CreativityCreated {
+			s.bus.Publish(events.Event{Type: events.StreakChanged, Kind: "creativity"})
+			break
+		}
+	}
+	return nil
+}
+
+// findOrAddQuestion returns the ID of the question with this content,
+// adding it if there isn't one, and whether it was added
+func findOrAddQuestion(tx *sql.Tx, content string) (int64, bool, error) {
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM questions WHERE content = ? ORDER BY id ASC LIMIT 1`, content).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, err
+	}
+
+	res, err := tx.Exec(`INSERT INTO questions (uuid, content) VALUES (?, ?)`, ids.New(), content)
+	if err != nil {
+		return 0, false, err
+	}
+	id, err = res.LastInsertId()
+	return id, true, err
+}
@@ -0,0 +1,52 @@
+// backend/models/import_test.go
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImportModel(t *testing.T) {
+	t.Parallel()
+	stores, _ := newTestStores(t)
+
+	created := time.Date(2021, 3, 2, 10, 0, 0, 0, time.Local)
+	entries := []ImportedEntry{
+		{Type: EntryAnswer, Question: "What went well?", Content: "The #garden", Date: "2021-03-02", CreatedAt: created},
+		{Type: EntryCreativity, Content: "A short poem", Date: "2021-03-02", CreatedAt: created},
+	}
+
+	// Test that a failed import leaves nothing behind
+	t.Run("Rollback", func(t *testing.T) {
+		failing := append(append([]ImportedEntry{}, entries...), ImportedEntry{Type: "photo", Content: "?", CreatedAt: created})
+		if err := stores.Imports.Import(failing); err == nil {
+			t.Fatal("Expected an unknown entry type to be rejected")
+		}
+
+		questions, _ := stores.Questions.GetAll()
+		answers, _ := stores.Answers.GetAll()
+		creativity, _ := stores.Creativity.GetAll()
+		if len(questions) != 0 || len(answers) != 0 || len(creativity) != 0 {
+			t.Errorf("Expected nothing imported, got %d questions, %d answers and %d creativity entries", len(questions), len(answers), len(creativity))
+		}
+	})
+
+	// Test that entries are written and indexed
+	t.Run("Import", func(t *testing.T) {
+		if err := stores.Imports.Import(entries); err != nil {
+			t.Fatalf("Failed to import entries: %v", err)
+		}
+
+		answers, _ := stores.Answers.GetAll()
+		if len(answers) != 1 || !answers[0].CreatedAt.Equal(created) {
+			t.Errorf("Expected the answer imported with its date, got %+v", answers)
+		}
+		tagged, err := stores.Tags.GetEntries("garden")
+		if err != nil {
+			t.Fatalf("Failed to get tagged entries: %v", err)
+		}
+		if len(tagged) != 1 {
+			t.Errorf("Expected the answer tagged, got %+v", tagged)
+		}
+	})
+}
@@ -0,0 +1,343 @@
+// backend/models/link.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"myproject/backend/textstats"
+)
+
+// Links are written in answers and creativity entries as [[ref]] or
+// [[ref|label]], where ref is one of
+//
+//	type:<uuid>        an entry of a type, such as creativity:<uuid>
+//	type:YYYY-MM-DD    the first entry of a type that day, such as answer:2024-03-01
+//	Some title         the creativity entry with that title
+var linkSources = []string{EntryAnswer, EntryCreativity}
+
+// EntryLink is a [[link]] written in an entry
+type EntryLink struct {
+	ID         int64         `json:"id"`
+	SourceType string        `json:"sourceType"`
+	SourceID   int64         `json:"sourceId"`
+	Ref        string        `json:"ref"`
+	Target     *EntrySummary `json:"target"` // nil when the link is broken
+}
+
+// Backlink is an entry linking to another, and the ref it was linked by
+type Backlink struct {
+	Entry EntrySummary `json:"entry"`
+	Ref   string       `json:"ref"`
+}
+
+// LinkStore follows the [[links]] between entries. Links are read from
+// entries' content when they are saved. A link whose entry is deleted, or
+// that never matched one, is broken.
+type LinkStore interface {
+	GetLinks(entryType string, id int64) ([]EntryLink, error)
+	GetBacklinks(entryType string, id int64) ([]Backlink, error)
+	GetBroken() ([]EntryLink, error)
+	Reindex() error
+}
+
+type sqlLinkStore struct {
+	db *sql.DB
+}
+
+// NewLinkStore creates a LinkStore backed by db
+func NewLinkStore(db *sql.DB) LinkStore {
+	return &sqlLinkStore{db: db}
+}
+
+// linkEntry replaces the links written in an entry. The links are
+// resolved by resolveLinks.
+func linkEntry(tx *sql.Tx, e EntrySummary) error {
+	if !slices.Contains(linkSources, e.Type) {
+		return nil
+	}
+
+	_, err := tx.Exec(`DELETE FROM entry_links WHERE source_type = ? AND source_id = ?`, e.Type, e.ID)
+	if err != nil {
+		return err
+	}
+	for _, ref := range textstats.Links(e.Content) {
+		_, err := tx.Exec(`INSERT INTO entry_links (source_type, source_id, ref) VALUES (?, ?, ?)`, e.Type, e.ID, ref)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targetMissing is true for links whose entry isn't there
+var targetMissing = func() string {
+	cases := []string{}
+	for _, entryType := range entryTypes {
+		// Table names come from entryTables, not user input
+		cases = append(cases, fmt.Sprintf(`WHEN '%s' THEN NOT EXISTS (SELECT 1 FROM %s WHERE uuid = l.target_uuid)`,
+			entryType, entryTables[entryType]))
+	}
+	return `(l.target_uuid IS NULL OR CASE l.target_type ` + strings.Join(cases, " ") + ` ELSE 1 END)`
+}()
+
+// resolveLinks finds the entries of links that aren't resolved, or whose
+// entry was deleted, in case a matching entry has since been written
+func resolveLinks(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT l.id, l.ref FROM entry_links l WHERE ` + targetMissing)
+	if err != nil {
+		return err
+	}
+	refs := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var ref string
+		if err := rows.Scan(&id, &ref); err != nil {
+			rows.Close()
+			return err
+		}
+		refs[id] = ref
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, ref := range refs {
+		target, err := findRef(tx, ref)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			_, err = tx.Exec(`UPDATE entry_links SET target_type = '', target_uuid = NULL WHERE id = ?`, id)
+		} else {
+			_, err = tx.Exec(`UPDATE entry_links SET target_type = ?, target_uuid = ? WHERE id = ?`, target.Type, target.UUID, id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findRef returns the entry a ref points to, or nil if there isn't one
+func findRef(q querier, ref string) (*EntrySummary, error) {
+	entryType, value, ok := strings.Cut(ref, ":")
+	if _, known := entryTables[entryType]; !ok || !known {
+		entries, err := loadEntries(q, EntryCreativity, "title = ? COLLATE NOCASE", strings.TrimSpace(ref))
+		return first(entries, ""), err
+	}
+
+	value = strings.TrimSpace(value)
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		entries, err := loadEntries(q, entryType, entryColumn(entryType, "uuid")+" = ?", value)
+		return first(entries, ""), err
+	}
+
+	var entries []EntrySummary
+	if entryType == EntryAnswer || entryType == EntryAffirmation {
+		// Their date is worked out from created_at, so look either side of
+		// the day and keep the entries that fall on it in local time
+		column := entryColumn(entryType, "created_at")
+		entries, err = loadEntries(q, entryType, column+" >= ? AND "+column+" < ?", day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	} else {
+		entries, err = loadEntries(q, entryType, "entry_date = ?", value)
+	}
+	return first(entries, value), err
+}
+
+// first returns the entry written first, of those on date if it's set
+func first(entries []EntrySummary, date string) *EntrySummary {
+	var found *EntrySummary
+	for i, e := range entries {
+		if (date == "" || e.Date == date) && (found == nil || e.ID < found.ID) {
+			found = &entries[i]
+		}
+	}
+	return found
+}
+
+// renameLinks points the links to an entry by its old title at its new
+// one, rewriting the entries they are written in. It returns the entries
+// changed.
+func renameLinks(db *sql.DB, uuid string, from string, to string) ([]EntrySummary, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT DISTINCT source_type, source_id FROM entry_links
+		WHERE target_uuid = ? AND ref = ? COLLATE NOCASE`, uuid, from)
+	if err != nil {
+		return nil, err
+	}
+	var sources []EntrySummary
+	for rows.Next() {
+		var e EntrySummary
+		if err := rows.Scan(&e.Type, &e.ID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sources = append(sources, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	changed := []EntrySummary{}
+	for _, source := range sources {
+		e, err := loadEntry(tx, source.Type, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = rewriteContent(tx, e, func(text string) string {
+			return textstats.ReplaceLink(text, from, to)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := linkEntry(tx, *e); err != nil {
+			return nil, err
+		}
+		changed = append(changed, *e)
+	}
+	if err := resolveLinks(tx); err != nil {
+		return nil, err
+	}
+	return changed, tx.Commit()
+}
+
+// Reindex reads the links in every entry again, for entries changed
+// without going through the stores, such as by sync
+func (s *sqlLinkStore) Reindex() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM entry_links`); err != nil {
+		return err
+	}
+	for _, entryType := range linkSources {
+		entries, err := loadEntries(tx, entryType, "")
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := linkEntry(tx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if err := resolveLinks(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetLinks returns the links written in an entry, in the order written
+func (s *sqlLinkStore) GetLinks(entryType string, id int64) ([]EntryLink, error) {
+	return s.links(`l.source_type = ? AND l.source_id = ?`, entryType, id)
+}
+
+// GetBroken returns the links that don't lead to an entry
+func (s *sqlLinkStore) GetBroken() ([]EntryLink, error) {
+	return s.links(targetMissing)
+}
+
+func (s *sqlLinkStore) links(where string, args ...any) ([]EntryLink, error) {
+	rows, err := s.db.Query(`
+		SELECT l.id, l.source_type, l.source_id, l.ref, l.target_type, COALESCE(l.target_uuid, '')
+		FROM entry_links l
+		WHERE `+where+`
+		ORDER BY l.source_type, l.source_id, l.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []EntryLink{}
+	targets := map[int]string{}
+	for rows.Next() {
+		var l EntryLink
+		var targetType, targetUUID string
+		if err := rows.Scan(&l.ID, &l.SourceType, &l.SourceID, &l.Ref, &targetType, &targetUUID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if targetUUID != "" {
+			targets[len(list)] = targetType + ":" + targetUUID
+		}
+		list = append(list, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Targets are loaded once the rows are closed, as there is only one connection
+	for i, ref := range targets {
+		if list[i].Target, err = findRef(s.db, ref); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// GetBacklinks returns the entries linking to an entry, newest first
+func (s *sqlLinkStore) GetBacklinks(entryType string, id int64) ([]Backlink, error) {
+	target, err := loadEntry(s.db, entryType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT source_type, source_id, ref FROM entry_links
+		WHERE target_type = ? AND target_uuid = ?
+		ORDER BY id`, target.Type, target.UUID)
+	if err != nil {
+		return nil, err
+	}
+	var sources []Backlink
+	seen := map[string]bool{}
+	for rows.Next() {
+		var b Backlink
+		if err := rows.Scan(&b.Entry.Type, &b.Entry.ID, &b.Ref); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		key := fmt.Sprintf("%s:%d", b.Entry.Type, b.Entry.ID)
+		if !seen[key] {
+			seen[key] = true
+			sources = append(sources, b)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	list := []Backlink{}
+	for _, b := range sources {
+		source, err := loadEntry(s.db, b.Entry.Type, b.Entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, Backlink{Entry: *source, Ref: b.Ref})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Entry.Date != list[j].Entry.Date {
+			return list[i].Entry.Date > list[j].Entry.Date
+		}
+		return list[i].Entry.At.After(list[j].Entry.At)
+	})
+	return list, nil
+}
@@ -0,0 +1,108 @@
+// backend/models/link_test.go
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinkModel(t *testing.T) {
+	t.Parallel()
+	stores, db := newTestStores(t)
+
+	question, err := stores.Questions.Add("What inspired you?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+	rain, err := stores.Creativity.Create(CreativityEntry{Title: "Rain", Content: "Drops on the pane", EntryDate: "2024-03-01"})
+	if err != nil {
+		t.Fatalf("Failed to create creativity entry: %v", err)
+	}
+	answer, err := stores.Answers.Create(question.ID, "Rereading [[rain]], see [[creativity:2024-03-01|that day]] and [[Snow]]")
+	if err != nil {
+		t.Fatalf("Failed to create answer: %v", err)
+	}
+
+	// Test that links are resolved by title, date and UUID
+	t.Run("Resolve", func(t *testing.T) {
+		links, err := stores.Links.GetLinks(EntryAnswer, answer.ID)
+		if err != nil {
+			t.Fatalf("Failed to get links: %v", err)
+		}
+		if len(links) != 3 || links[0].Target == nil || links[0].Target.ID != rain.ID ||
+			links[1].Target == nil || links[1].Target.ID != rain.ID || links[2].Target != nil {
+			t.Fatalf("Expected two links to Rain and a broken one, got %+v", links)
+		}
+
+		// A link is resolved once the entry it names is written
+		snow, err := stores.Creativity.Create(CreativityEntry{
+			Title:     "Snow",
+			Content:   "Quiet streets, like [[creativity:" + rain.UUID + "]] and [[answer:" + time.Now().Format("2006-01-02") + "]]",
+			EntryDate: "2024-03-02",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create creativity entry: %v", err)
+		}
+		broken, err := stores.Links.GetBroken()
+		if err != nil {
+			t.Fatalf("Failed to get broken links: %v", err)
+		}
+		if len(broken) != 0 {
+			t.Errorf("Expected no broken links, got %+v", broken)
+		}
+
+		backlinks, err := stores.Links.GetBacklinks(EntryCreativity, rain.ID)
+		if err != nil {
+			t.Fatalf("Failed to get backlinks: %v", err)
+		}
+		if len(backlinks) != 2 || backlinks[0].Entry.Type != EntryAnswer || backlinks[0].Ref != "rain" || backlinks[1].Entry.ID != snow.ID {
+			t.Errorf("Expected the answer then the older Snow entry, got %+v", backlinks)
+		}
+
+		backlinks, _ = stores.Links.GetBacklinks(EntryAnswer, answer.ID)
+		if len(backlinks) != 1 || backlinks[0].Entry.ID != snow.ID {
+			t.Errorf("Expected Snow linking to the answer by date, got %+v", backlinks)
+		}
+	})
+
+	// Test that retitling an entry rewrites the links to it by title
+	t.Run("Rename", func(t *testing.T) {
+		entry, _ := stores.Creativity.GetByID(rain.ID)
+		entry.Title = "Storm"
+		if err := stores.Creativity.Edit(*entry); err != nil {
+			t.Fatalf("Failed to edit creativity entry: %v", err)
+		}
+
+		stored, _ := loadEntry(db, EntryAnswer, answer.ID)
+		if !strings.HasPrefix(stored.Content, "Rereading [[Storm]], see [[creativity:2024-03-01|that day]]") {
+			t.Errorf("Expected the link by title rewritten, got %q", stored.Content)
+		}
+		backlinks, _ := stores.Links.GetBacklinks(EntryCreativity, rain.ID)
+		if len(backlinks) != 2 || backlinks[0].Ref != "Storm" {
+			t.Errorf("Expected the backlinks kept, got %+v", backlinks)
+		}
+	})
+
+	// Test that links to a deleted entry are broken
+	t.Run("Delete", func(t *testing.T) {
+		if err := stores.Creativity.Delete(rain.ID); err != nil {
+			t.Fatalf("Failed to delete creativity entry: %v", err)
+		}
+		broken, err := stores.Links.GetBroken()
+		if err != nil {
+			t.Fatalf("Failed to get broken links: %v", err)
+		}
+		if len(broken) != 3 || broken[0].Ref != "Storm" || broken[0].Target != nil {
+			t.Errorf("Expected the three links to the deleted entry broken, got %+v", broken)
+		}
+
+		if err := stores.Links.Reindex(); err != nil {
+			t.Fatalf("Failed to reindex links: %v", err)
+		}
+		links, _ := stores.Links.GetLinks(EntryAnswer, answer.ID)
+		if len(links) != 3 || links[2].Target == nil || links[2].Target.Title != "Snow" {
+			t.Errorf("Expected the link to Snow kept, got %+v", links)
+		}
+	})
+}
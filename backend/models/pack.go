@@ -0,0 +1,273 @@
+// backend/models/pack.go
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+	"myproject/backend/textstats"
+)
+
+// QuestionPack is an installed question pack
+type QuestionPack struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Version       string    `json:"version"`
+	Author        string    `json:"author"`
+	Language      string    `json:"language"`
+	Tags          []string  `json:"tags"`
+	Description   string    `json:"description"`
+	QuestionCount int       `json:"questionCount"`
+	InstalledAt   time.Time `json:"installedAt"`
+}
+
+// PackInstallResult reports what installing a pack changed
+type PackInstallResult struct {
+	PackID  string   `json:"packId"`
+	Added   int      `json:"added"`
+	Skipped []string `json:"skipped"` // Questions already in the journal
+}
+
+// PackUninstallResult reports what uninstalling a pack changed
+type PackUninstallResult struct {
+	PackID  string `json:"packId"`
+	Removed int    `json:"removed"`
+	Kept    int    `json:"kept"` // Answered or drafted questions kept as the user's own
+}
+
+// QuestionPackStore manages installed question packs
+type QuestionPackStore interface {
+	GetAll() ([]QuestionPack, error)
+	GetByID(id string) (*QuestionPack, error)
+	Install(pack QuestionPack, questions []string) (*PackInstallResult, error)
+	Uninstall(id string) (*PackUninstallResult, error)
+	GetQuestions(id string) ([]Question, error)
+}
+
+type sqlQuestionPackStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewQuestionPackStore creates a QuestionPackStore backed by db
+func NewQuestionPackStore(db *sql.DB, bus *events.Bus) QuestionPackStore {
+	return &sqlQuestionPackStore{db: db, bus: bus}
+}
+
+// GetAll retrieves the installed packs with their question counts
+func (s *sqlQuestionPackStore) GetAll() ([]QuestionPack, error) {
+	rows, err := s.db.Query(`
+		SELECT p.id, p.name, p.version, p.author, p.language, p.tags, p.description, p.installed_at,
+			(SELECT COUNT(*) FROM questions q WHERE q.pack_id = p.id)
+		FROM question_packs p
+		ORDER BY p.name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	packs := []QuestionPack{}
+	for rows.Next() {
+		p, err := scanQuestionPack(rows)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, *p)
+	}
+
+	return packs, rows.Err()
+}
+
+// GetByID retrieves an installed pack
+func (s *sqlQuestionPackStore) GetByID(id string) (*QuestionPack, error) {
+	return scanQuestionPack(s.db.QueryRow(`
+		SELECT p.id, p.name, p.version, p.author, p.language, p.tags, p.description, p.installed_at,
+			(SELECT COUNT(*) FROM questions q WHERE q.pack_id = p.id)
+		FROM question_packs p
+		WHERE p.id = ?`, id))
+}
+
+// scanQuestionPack reads a pack row selected by GetAll or GetByID
+func scanQuestionPack(row interface{ Scan(...any) error }) (*QuestionPack, error) {
+	var p QuestionPack
+	var tags string
+
+	err := row.Scan(&p.ID, &p.Name, &p.Version, &p.Author, &p.Language, &tags, &p.Description, &p.InstalledAt, &p.QuestionCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &p.Tags); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Install records the pack and adds its questions. Questions already in the
+// journal, from any source, are skipped. Installing a pack again updates its
+// metadata and adds only questions that are new.
+func (s *sqlQuestionPackStore) Install(pack QuestionPack, questions []string) (*PackInstallResult, error) {
+	if pack.Tags == nil {
+		pack.Tags = []string{}
+	}
+	tags, err := json.Marshal(pack.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO question_packs (id, name, version, author, language, tags, description)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			version = excluded.version,
+			author = excluded.author,
+			language = excluded.language,
+			tags = excluded.tags,
+			description = excluded.description`,
+		pack.ID, pack.Name, pack.Version, pack.Author, pack.Language, string(tags), pack.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect what's already in the journal so duplicates can be skipped
+	existing := map[string]bool{}
+	rows, err := tx.Query(`SELECT content FROM questions`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		existing[textstats.Normalize(content)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &PackInstallResult{PackID: pack.ID, Skipped: []string{}}
+	for _, content := range questions {
+		key := textstats.Normalize(content)
+		if existing[key] {
+			result.Skipped = append(result.Skipped, content)
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO questions (content, pack_id, uuid)
+			VALUES (?, ?, ?)`, content, pack.ID, ids.New())
+		if err != nil {
+			return nil, err
+		}
+		existing[key] = true
+		result.Added++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.QuestionPackInstalled, Key: pack.ID})
+	return result, nil
+}
+
+// Uninstall removes a pack and its unanswered questions. Questions that have
+// answers or drafts are kept and no longer belong to the pack.
+func (s *sqlQuestionPackStore) Uninstall(id string) (*PackUninstallResult, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM question_packs WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	// Skips go with the questions removed below
+	_, err = tx.Exec(`
+		DELETE FROM question_skips WHERE question_id IN (
+			SELECT id FROM questions
+			WHERE pack_id = ?
+			AND NOT EXISTS (SELECT 1 FROM answers a WHERE a.question_id = questions.id)
+			AND NOT EXISTS (SELECT 1 FROM answer_drafts d WHERE d.question_id = questions.id))`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.Exec(`
+		DELETE FROM questions
+		WHERE pack_id = ?
+		AND NOT EXISTS (SELECT 1 FROM answers a WHERE a.question_id = questions.id)
+		AND NOT EXISTS (SELECT 1 FROM answer_drafts d WHERE d.question_id = questions.id)`, id)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = tx.Exec(`UPDATE questions SET pack_id = NULL WHERE pack_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	kept, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM question_packs WHERE id = ?`, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.QuestionPackUninstalled, Key: id})
+	return &PackUninstallResult{PackID: id, Removed: int(removed), Kept: int(kept)}, nil
+}
+
+// GetQuestions retrieves the questions installed from a pack. An empty id
+// selects the questions that don't belong to any pack.
+func (s *sqlQuestionPackStore) GetQuestions(id string) ([]Question, error) {
+	rows, err := s.db.Query(`
+		SELECT `+questionColumns+`
+		FROM questions
+		WHERE COALESCE(pack_id, '') = ?
+		ORDER BY id ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []Question{}
+	for rows.Next() {
+		q, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, *q)
+	}
+
+	return questions, rows.Err()
+}
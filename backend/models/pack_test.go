@@ -0,0 +1,104 @@
+// backend/models/pack_test.go
+package models
+
+import (
+	"testing"
+)
+
+func TestQuestionPackModel(t *testing.T) {
+	t.Parallel()
+
+	stores, _ := newTestStores(t)
+
+	own, err := stores.Questions.Add("What made me smile today?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+
+	pack := QuestionPack{ID: "smiles", Name: "Smiles", Version: "1.0.0", Tags: []string{"joy"}}
+	questions := []string{
+		"what made me  smile today",
+		"Who made me laugh?",
+		"Who made me laugh",
+		"What am I looking forward to?",
+	}
+
+	// Test installing with de-duplication
+	t.Run("Install", func(t *testing.T) {
+		result, err := stores.Packs.Install(pack, questions)
+		if err != nil {
+			t.Fatalf("Failed to install pack: %v", err)
+		}
+
+		if result.Added != 2 {
+			t.Errorf("Expected 2 questions added, got %d", result.Added)
+		}
+
+		if len(result.Skipped) != 2 {
+			t.Errorf("Expected 2 duplicates skipped, got %v", result.Skipped)
+		}
+
+		installed, err := stores.Packs.GetByID("smiles")
+		if err != nil {
+			t.Fatalf("Failed to get pack: %v", err)
+		}
+
+		if installed.QuestionCount != 2 || len(installed.Tags) != 1 {
+			t.Errorf("Unexpected installed pack %+v", installed)
+		}
+
+		// Installing again adds nothing
+		result, err = stores.Packs.Install(pack, questions)
+		if err != nil {
+			t.Fatalf("Failed to reinstall pack: %v", err)
+		}
+
+		if result.Added != 0 {
+			t.Errorf("Expected no questions added on reinstall, got %d", result.Added)
+		}
+
+		mine, err := stores.Packs.GetQuestions("")
+		if err != nil {
+			t.Fatalf("Failed to get own questions: %v", err)
+		}
+
+		if len(mine) != 1 || mine[0].ID != own.ID {
+			t.Errorf("Expected only the user's own question, got %+v", mine)
+		}
+	})
+
+	// Test that uninstalling keeps answered questions
+	t.Run("Uninstall", func(t *testing.T) {
+		packQuestions, err := stores.Packs.GetQuestions("smiles")
+		if err != nil {
+			t.Fatalf("Failed to get pack questions: %v", err)
+		}
+
+		answered := packQuestions[0]
+		if _, err := stores.Answers.Create(answered.ID, "My sister"); err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+
+		result, err := stores.Packs.Uninstall("smiles")
+		if err != nil {
+			t.Fatalf("Failed to uninstall pack: %v", err)
+		}
+
+		if result.Removed != 1 || result.Kept != 1 {
+			t.Errorf("Expected 1 removed and 1 kept, got %+v", result)
+		}
+
+		question, err := stores.Questions.GetByID(answered.ID)
+		if err != nil {
+			t.Fatalf("Expected answered question to be kept: %v", err)
+		}
+
+		if question.PackID != "" {
+			t.Errorf("Expected kept question to leave the pack, got %q", question.PackID)
+		}
+
+		if _, err := stores.Packs.Uninstall("smiles"); err == nil {
+			t.Errorf("Expected error uninstalling a pack that isn't installed")
+		}
+	})
+}
@@ -0,0 +1,291 @@
+// backend/models/prompt.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// promptRepeatWindow is how many days must pass before a daily prompt
+// comes round again, when there are enough prompts
+const promptRepeatWindow = 90
+
+// CreativityPrompt is something to respond to in the creativity journal
+type CreativityPrompt struct {
+	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"`
+	Content   string    `json:"content"`
+	Category  string    `json:"category"`
+	Builtin   bool      `json:"builtin"`
+	Archived  bool      `json:"archived"` // Archived prompts aren't chosen as the daily prompt
+	Entries   int       `json:"entries"`  // Creativity entries written in response
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// PromptStore manages creativity prompts and picks the prompt of the day
+type PromptStore interface {
+	GetAll(includeArchived bool) ([]CreativityPrompt, error)
+	GetByID(id int64) (*CreativityPrompt, error)
+	GetCategories() ([]string, error)
+	Add(content string, category string) (*CreativityPrompt, error)
+	Update(id int64, content string, category string) error
+	SetArchived(id int64, archived bool) error
+	Delete(id int64) error
+	GetDaily(date string) (*CreativityPrompt, error)
+}
+
+type sqlPromptStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewPromptStore creates a PromptStore backed by db
+func NewPromptStore(db *sql.DB, bus *events.Bus) PromptStore {
+	return &sqlPromptStore{db: db, bus: bus}
+}
+
+const promptColumns = `p.id, p.uuid, p.content, p.category, p.builtin, p.archived,
+	(SELECT COUNT(*) FROM creativity_entries e WHERE e.prompt_id = p.id), p.created_at`
+
+func scanPrompt(row interface{ Scan(...any) error }) (*CreativityPrompt, error) {
+	var p CreativityPrompt
+	err := row.Scan(&p.ID, &p.UUID, &p.Content, &p.Category, &p.Builtin, &p.Archived, &p.Entries, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetAll returns the prompts by category, then in the order they were added
+func (s *sqlPromptStore) GetAll(includeArchived bool) ([]CreativityPrompt, error) {
+	rows, err := s.db.Query(`
+		SELECT `+promptColumns+`
+		FROM creativity_prompts p
+		WHERE p.archived = 0 OR ?
+		ORDER BY p.category, p.id`, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []CreativityPrompt{}
+	for rows.Next() {
+		p, err := scanPrompt(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *p)
+	}
+	return list, rows.Err()
+}
+
+// GetByID returns a prompt
+func (s *sqlPromptStore) GetByID(id int64) (*CreativityPrompt, error) {
+	return scanPrompt(s.db.QueryRow(`SELECT `+promptColumns+` FROM creativity_prompts p WHERE p.id = ?`, id))
+}
+
+// GetCategories lists the categories in use
+func (s *sqlPromptStore) GetCategories() ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT category FROM creativity_prompts
+		WHERE category != ''
+		ORDER BY category`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []string{}
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// Add adds a prompt of the user's own
+func (s *sqlPromptStore) Add(content string, category string) (*CreativityPrompt, error) {
+	content, category = strings.TrimSpace(content), strings.TrimSpace(category)
+	if content == "" {
+		return nil, fmt.Errorf("prompt content is required")
+	}
+
+	now := time.Now()
+	uuid := ids.New()
+	res, err := s.db.Exec(`
+		INSERT INTO creativity_prompts (uuid, content, category, created_at)
+		VALUES (?, ?, ?, ?)`, uuid, content, category, now)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.PromptCreated, ID: id})
+	return &CreativityPrompt{ID: id, UUID: uuid, Content: content, Category: category, CreatedAt: now}, nil
+}
+
+// Update changes one of the user's prompts. Built-in prompts can only be archived.
+func (s *sqlPromptStore) Update(id int64, content string, category string) error {
+	content, category = strings.TrimSpace(content), strings.TrimSpace(category)
+	if content == "" {
+		return fmt.Errorf("prompt content is required")
+	}
+	if err := s.checkEditable(id); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec(`UPDATE creativity_prompts SET content = ?, category = ? WHERE id = ?`, content, category, id)
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.PromptUpdated, ID: id})
+	return nil
+}
+
+// SetArchived stops a prompt being chosen as the daily prompt, or lets it be chosen again
+func (s *sqlPromptStore) SetArchived(id int64, archived bool) error {
+	res, err := s.db.Exec(`UPDATE creativity_prompts SET archived = ? WHERE id = ?`, archived, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.bus.Publish(events.Event{Type: events.PromptUpdated, ID: id})
+	return nil
+}
+
+// Delete deletes one of the user's prompts. Entries written in response
+// keep their content but no longer refer to the prompt.
+func (s *sqlPromptStore) Delete(id int64) error {
+	if err := s.checkEditable(id); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`UPDATE creativity_entries SET prompt_id = NULL WHERE prompt_id = ?`,
+		`DELETE FROM creativity_daily_prompts WHERE prompt_id = ?`,
+		`DELETE FROM creativity_prompts WHERE id = ?`,
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.PromptDeleted, ID: id})
+	return nil
+}
+
+// checkEditable returns an error unless the prompt is one of the user's own
+func (s *sqlPromptStore) checkEditable(id int64) error {
+	var builtin bool
+	if err := s.db.QueryRow(`SELECT builtin FROM creativity_prompts WHERE id = ?`, id).Scan(&builtin); err != nil {
+		return err
+	}
+	if builtin {
+		return fmt.Errorf("built-in prompts can't be changed, only archived")
+	}
+	return nil
+}
+
+// GetDaily returns the prompt of the day for a date (YYYY-MM-DD). The
+// prompt is picked from those not shown or responded to in the
+// promptRepeatWindow days before, using the date as the seed, and is
+// remembered so the day's prompt doesn't change.
+func (s *sqlPromptStore) GetDaily(date string) (*CreativityPrompt, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+
+	chosen := func() (*CreativityPrompt, error) {
+		return scanPrompt(s.db.QueryRow(`
+			SELECT `+promptColumns+`
+			FROM creativity_daily_prompts d
+			JOIN creativity_prompts p ON p.id = d.prompt_id
+			WHERE d.entry_date = ?`, date))
+	}
+	prompt, err := chosen()
+	if err != sql.ErrNoRows {
+		return prompt, err
+	}
+
+	// Prompts are ordered by UUID, so journals with the same prompts and
+	// history pick the same one
+	since := day.AddDate(0, 0, -promptRepeatWindow).Format("2006-01-02")
+	candidates, err := s.promptIDs(`
+		SELECT id FROM creativity_prompts
+		WHERE archived = 0 AND id NOT IN (
+			SELECT prompt_id FROM creativity_daily_prompts WHERE entry_date >= ? AND entry_date < ?
+			UNION
+			SELECT prompt_id FROM creativity_entries
+			WHERE prompt_id IS NOT NULL AND entry_date >= ? AND entry_date < ?
+		)
+		ORDER BY uuid`, since, date, since, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		// Every prompt was used recently, so repeats can't be avoided
+		if candidates, err = s.promptIDs(`SELECT id FROM creativity_prompts WHERE archived = 0 ORDER BY uuid`); err != nil {
+			return nil, err
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("there are no creativity prompts to choose from")
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(date))
+	id := candidates[h.Sum32()%uint32(len(candidates))]
+
+	_, err = s.db.Exec(`INSERT OR IGNORE INTO creativity_daily_prompts (entry_date, prompt_id) VALUES (?, ?)`, date, id)
+	if err != nil {
+		return nil, err
+	}
+	return chosen()
+}
+
+// promptIDs runs a query listing prompt IDs
+func (s *sqlPromptStore) promptIDs(query string, args ...any) ([]int64, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		list = append(list, id)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,150 @@
+// backend/models/prompt_test.go
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromptModel(t *testing.T) {
+	t.Parallel()
+
+	// Test that the daily prompt is stable and doesn't repeat within the window
+	t.Run("DailyPrompt", func(t *testing.T) {
+		stores, _ := newTestStores(t)
+
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		seen := map[int64]string{}
+		for i := 0; i < promptRepeatWindow; i++ {
+			date := start.AddDate(0, 0, i).Format("2006-01-02")
+			prompt, err := stores.Prompts.GetDaily(date)
+			if err != nil {
+				t.Fatalf("Failed to get daily prompt: %v", err)
+			}
+			if other, ok := seen[prompt.ID]; ok {
+				t.Fatalf("Expected no repeats, got %q on %s and %s", prompt.Content, other, date)
+			}
+			seen[prompt.ID] = date
+		}
+
+		first, _ := stores.Prompts.GetDaily("2024-01-01")
+		again, err := stores.Prompts.GetDaily("2024-01-01")
+		if err != nil {
+			t.Fatalf("Failed to get daily prompt: %v", err)
+		}
+		if again.ID != first.ID {
+			t.Errorf("Expected the same prompt for the same day, got %q and %q", first.Content, again.Content)
+		}
+
+		// Another journal with the same history picks the same prompts
+		other, _ := newTestStores(t)
+		prompt, err := other.Prompts.GetDaily("2024-01-01")
+		if err != nil {
+			t.Fatalf("Failed to get daily prompt: %v", err)
+		}
+		if prompt.UUID != first.UUID {
+			t.Errorf("Expected %q in both journals, got %q", first.Content, prompt.Content)
+		}
+
+		if _, err := stores.Prompts.GetDaily("January"); err == nil {
+			t.Error("Expected an invalid date to be rejected")
+		}
+	})
+
+	// Test managing the user's own prompts
+	t.Run("UserPrompts", func(t *testing.T) {
+		stores, _ := newTestStores(t)
+
+		prompt, err := stores.Prompts.Add("A door you never opened", "Memories")
+		if err != nil {
+			t.Fatalf("Failed to add prompt: %v", err)
+		}
+		if err := stores.Prompts.Update(prompt.ID, "A door you were afraid to open", "Memories"); err != nil {
+			t.Fatalf("Failed to update prompt: %v", err)
+		}
+
+		categories, err := stores.Prompts.GetCategories()
+		if err != nil {
+			t.Fatalf("Failed to get categories: %v", err)
+		}
+		if len(categories) != 2 {
+			t.Errorf("Expected the built-in and new categories, got %v", categories)
+		}
+
+		all, _ := stores.Prompts.GetAll(false)
+		for _, p := range all {
+			if p.Builtin {
+				if err := stores.Prompts.Update(p.ID, "Something else", ""); err == nil {
+					t.Error("Expected built-in prompts to be read-only")
+				}
+				break
+			}
+		}
+
+		if err := stores.Prompts.SetArchived(prompt.ID, true); err != nil {
+			t.Fatalf("Failed to archive prompt: %v", err)
+		}
+		current, _ := stores.Prompts.GetAll(false)
+		if len(current) != len(all)-1 {
+			t.Errorf("Expected the archived prompt hidden, got %d of %d", len(current), len(all))
+		}
+	})
+
+	// Test that entries record their prompt and can be browsed by it
+	t.Run("EntriesByPrompt", func(t *testing.T) {
+		stores, db := newTestStores(t)
+
+		prompt, err := stores.Prompts.Add("The smell of rain", "Senses")
+		if err != nil {
+			t.Fatalf("Failed to add prompt: %v", err)
+		}
+		for _, date := range []string{"2024-02-01", "2024-02-02"} {
+			if _, err := stores.Creativity.SaveForPrompt("Petrichor and puddles", date, &prompt.ID); err != nil {
+				t.Fatalf("Failed to save creativity entry: %v", err)
+			}
+		}
+
+		// Saving again without a prompt keeps it
+		entry, err := stores.Creativity.Save("Petrichor, puddles and wet dogs", "2024-02-01")
+		if err != nil {
+			t.Fatalf("Failed to save creativity entry: %v", err)
+		}
+		if entry.PromptID == nil || *entry.PromptID != prompt.ID {
+			t.Errorf("Expected the entry to keep its prompt, got %v", entry.PromptID)
+		}
+
+		entries, err := stores.Creativity.GetByPrompt(prompt.ID)
+		if err != nil {
+			t.Fatalf("Failed to get entries by prompt: %v", err)
+		}
+		stored, _ := stores.Prompts.GetByID(prompt.ID)
+		if len(entries) != 2 || stored.Entries != 2 {
+			t.Errorf("Expected 2 entries for the prompt, got %d and a count of %d", len(entries), stored.Entries)
+		}
+
+		// A prompt responded to recently isn't picked as the daily prompt
+		_, err = db.Exec(`UPDATE creativity_prompts SET archived = 1 WHERE builtin = 1 AND content != 'Moonlight'`)
+		if err != nil {
+			t.Fatalf("Failed to archive prompts: %v", err)
+		}
+		daily, err := stores.Prompts.GetDaily("2024-02-03")
+		if err != nil {
+			t.Fatalf("Failed to get daily prompt: %v", err)
+		}
+		if daily.Content != "Moonlight" {
+			t.Errorf("Expected the prompt not used recently, got %q", daily.Content)
+		}
+
+		// Deleting the prompt keeps the entries written for it
+		if err := stores.Prompts.Delete(prompt.ID); err != nil {
+			t.Fatalf("Failed to delete prompt: %v", err)
+		}
+		kept, err := stores.Creativity.GetByDate("2024-02-01")
+		if err != nil {
+			t.Fatalf("Failed to get creativity entries: %v", err)
+		}
+		if len(kept) != 1 || kept[0].PromptID != nil {
+			t.Errorf("Expected the entry kept without its prompt, got %+v", kept)
+		}
+	})
+}
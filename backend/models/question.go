@@ -2,39 +2,90 @@
 package models
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
-	"myproject/backend/database"
+	"myproject/backend/events"
+	"myproject/backend/ids"
 )
 
 type Question struct {
 	ID        int64     `json:"id"`
+	UUID      string    `json:"uuid"` // Globally unique, unlike ID, so it survives syncing and merging
 	Content   string    `json:"content"`
+	PackID    string    `json:"packId,omitempty"`   // Question pack the question was installed from, if any
+	Template  *Template `json:"template,omitempty"` // Structure for answers, if any
 	CreatedAt time.Time `json:"createdAt"`
 }
 
-// GetRandomQuestion gets a random question
-func GetRandomQuestion() (*Question, error) {
-	var question Question
-
-	err := database.DB.QueryRow(`
-		SELECT id, content, created_at 
-		FROM questions 
-		ORDER BY RANDOM() 
-		LIMIT 1`).Scan(
-		&question.ID, &question.Content, &question.CreatedAt)
+const questionColumns = `id, uuid, content, COALESCE(pack_id, ''), template, created_at`
 
+func scanQuestion(row interface{ Scan(...any) error }) (*Question, error) {
+	var question Question
+	var template string
+	err := row.Scan(&question.ID, &question.UUID, &question.Content, &question.PackID, &template, &question.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
-
+	if question.Template, err = decodeTemplate(template); err != nil {
+		return nil, err
+	}
 	return &question, nil
 }
 
-// GetAllQuestions retrieves all questions from the database
-func GetAllQuestions() ([]Question, error) {
-	rows, err := database.DB.Query(`
-		SELECT id, content, created_at 
+// QuestionStore manages journal questions
+type QuestionStore interface {
+	GetRandom() (*Question, error)
+	GetByContent(content string) (*Question, error)
+	GetAll() ([]Question, error)
+	Add(content string) (*Question, error)
+	Update(id int64, content string) error
+	Delete(id int64) error
+	GetByID(id int64) (*Question, error)
+	SetTemplate(id int64, template *Template) error
+	Next() (*Question, error)
+	Skip(id int64, reason string) (*QuestionSkip, error)
+	Snooze(id int64, until string) (*QuestionSkip, error)
+	GetMostSkipped(limit int) ([]SkippedQuestion, error)
+	Strategy() (string, error)
+	SetStrategy(name string) error
+}
+
+type sqlQuestionStore struct {
+	db       *sql.DB
+	bus      *events.Bus
+	settings SettingsStore
+}
+
+// NewQuestionStore creates a QuestionStore backed by db
+func NewQuestionStore(db *sql.DB, bus *events.Bus) QuestionStore {
+	return &sqlQuestionStore{db: db, bus: bus, settings: NewSettingsStore(db)}
+}
+
+// GetRandom gets a random question
+func (s *sqlQuestionStore) GetRandom() (*Question, error) {
+	return scanQuestion(s.db.QueryRow(`
+		SELECT ` + questionColumns + `
+		FROM questions 
+		ORDER BY RANDOM() 
+		LIMIT 1`))
+}
+
+// GetByContent finds a question by its exact content
+func (s *sqlQuestionStore) GetByContent(content string) (*Question, error) {
+	return scanQuestion(s.db.QueryRow(`
+		SELECT `+questionColumns+`
+		FROM questions 
+		WHERE content = ? 
+		ORDER BY id ASC 
+		LIMIT 1`, content))
+}
+
+// GetAll retrieves all questions from the database
+func (s *sqlQuestionStore) GetAll() ([]Question, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + questionColumns + `
 		FROM questions 
 		ORDER BY created_at DESC`)
 
@@ -45,24 +96,23 @@ func GetAllQuestions() ([]Question, error) {
 
 	var questions []Question
 	for rows.Next() {
-		var q Question
-
-		err := rows.Scan(&q.ID, &q.Content, &q.CreatedAt)
+		q, err := scanQuestion(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		questions = append(questions, q)
+		questions = append(questions, *q)
 	}
 
 	return questions, nil
 }
 
-// AddQuestion adds a new question to the database
-func AddQuestion(content string) (*Question, error) {
-	res, err := database.DB.Exec(`
-		INSERT INTO questions (content) 
-		VALUES (?)`, content)
+// Add adds a new question to the database
+func (s *sqlQuestionStore) Add(content string) (*Question, error) {
+	uuid := ids.New()
+	res, err := s.db.Exec(`
+		INSERT INTO questions (uuid, content) 
+		VALUES (?, ?)`, uuid, content)
 
 	if err != nil {
 		return nil, err
@@ -73,104 +123,101 @@ func AddQuestion(content string) (*Question, error) {
 		return nil, err
 	}
 
+	s.bus.Publish(events.Event{Type: events.QuestionCreated, ID: id})
+
 	return &Question{
 		ID:        id,
+		UUID:      uuid,
 		Content:   content,
 		CreatedAt: time.Now(),
 	}, nil
 }
 
-// UpdateQuestion updates a question in the database
-func UpdateQuestion(id int64, content string) error {
-	_, err := database.DB.Exec(`
+// Update updates a question in the database
+func (s *sqlQuestionStore) Update(id int64, content string) error {
+	_, err := s.db.Exec(`
 		UPDATE questions 
 		SET content = ? 
 		WHERE id = ?`, content, id)
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.QuestionUpdated, ID: id})
+	return nil
 }
 
-// DeleteQuestion deletes a question and its associated answers
-func DeleteQuestion(id int64) error {
-	tx, err := database.DB.Begin()
+// Delete deletes a question and its associated answers
+func (s *sqlQuestionStore) Delete(id int64) error {
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 
-	// Delete associated answers first
+	// Delete associated answers and drafts first
 	_, err = tx.Exec(`DELETE FROM answers WHERE question_id = ?`, id)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	// Delete the question
-	_, err = tx.Exec(`DELETE FROM questions WHERE id = ?`, id)
+	_, err = tx.Exec(`DELETE FROM answer_drafts WHERE question_id = ?`, id)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	return tx.Commit()
-}
-
-// backend/models/answer.go
-// Add these functions to your answer.go file
-
-// UpdateAnswer updates an answer in the database
-func UpdateAnswer(id int64, content string) error {
-	now := time.Now()
-	_, err := database.DB.Exec(`
-		UPDATE answers 
-		SET content = ?, updated_at = ? 
-		WHERE id = ?`, content, now, id)
-	return err
-}
-
-// DeleteAnswer deletes an answer from the database
-func DeleteAnswer(id int64) error {
-	_, err := database.DB.Exec(`DELETE FROM answers WHERE id = ?`, id)
-	return err
-}
-
-// backend/models/affirmation.go
-// Add these functions to your affirmation.go file
-
-// UpdateAffirmation updates an affirmation in the database
-func UpdateAffirmation(id int64, content string) error {
-	now := time.Now()
-	_, err := database.DB.Exec(`
-		UPDATE affirmations 
-		SET content = ?, updated_at = ? 
-		WHERE id = ?`, content, now, id)
-	return err
-}
-
-// DeleteAffirmation deletes an affirmation and its associated logs
-func DeleteAffirmation(id int64) error {
-	tx, err := database.DB.Begin()
+	_, err = tx.Exec(`DELETE FROM question_skips WHERE question_id = ?`, id)
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	// Delete associated logs first
-	_, err = tx.Exec(`DELETE FROM affirmation_logs WHERE affirmation_id = ?`, id)
+	// Delete the question
+	_, err = tx.Exec(`DELETE FROM questions WHERE id = ?`, id)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	// Delete the affirmation
-	_, err = tx.Exec(`DELETE FROM affirmations WHERE id = ?`, id)
-	if err != nil {
-		tx.Rollback()
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	s.bus.Publish(events.Event{Type: events.QuestionDeleted, ID: id})
+	return nil
+}
+
+// GetByID retrieves a specific question by its ID
+func (s *sqlQuestionStore) GetByID(id int64) (*Question, error) {
+	return scanQuestion(s.db.QueryRow(`
+		SELECT `+questionColumns+`
+		FROM questions 
+		WHERE id = ?`, id))
 }
 
-// DeleteAffirmationLog deletes an affirmation log from the database
-func DeleteAffirmationLog(id int64) error {
-	_, err := database.DB.Exec(`DELETE FROM affirmation_logs WHERE id = ?`, id)
-	return err
+// SetTemplate gives a question's answers a structure, or removes it when
+// template is nil. Answers already written keep their fields.
+func (s *sqlQuestionStore) SetTemplate(id int64, template *Template) error {
+	var data []byte
+	if template != nil {
+		if err := template.Validate(); err != nil {
+			return err
+		}
+		var err error
+		if data, err = json.Marshal(template); err != nil {
+			return err
+		}
+	}
+
+	res, err := s.db.Exec(`UPDATE questions SET template = ? WHERE id = ?`, string(data), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.bus.Publish(events.Event{Type: events.QuestionUpdated, ID: id})
+	return nil
 }
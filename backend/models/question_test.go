@@ -2,34 +2,19 @@
 package models
 
 import (
-	"myproject/backend/database"
-	"os"
 	"testing"
 )
 
 func TestQuestionModel(t *testing.T) {
-	// Set up test database
-	testDB := "./test_question.db"
+	t.Parallel()
 
-	// Clean up any existing test database
-	os.Remove(testDB)
-
-	// Initialize test database
-	err := database.Initialize(testDB)
-	if err != nil {
-		t.Fatalf("Failed to initialize test database: %v", err)
-	}
-
-	// Clean up after test
-	defer func() {
-		database.Close()
-		os.Remove(testDB)
-	}()
+	// Set up an in-memory test database
+	stores, _ := newTestStores(t)
 
 	// Test AddQuestion
 	t.Run("AddQuestion", func(t *testing.T) {
 		content := "What inspired you today?"
-		question, err := AddQuestion(content)
+		question, err := stores.Questions.Add(content)
 
 		if err != nil {
 			t.Fatalf("Failed to add question: %v", err)
@@ -46,6 +31,9 @@ func TestQuestionModel(t *testing.T) {
 
 	// Test GetRandomQuestion
 	t.Run("GetRandomQuestion", func(t *testing.T) {
+		// Use a fresh database so only these questions can be picked
+		stores, _ := newTestStores(t)
+
 		// Add a few questions
 		questions := []string{
 			"What are your goals for today?",
@@ -54,14 +42,14 @@ func TestQuestionModel(t *testing.T) {
 		}
 
 		for _, q := range questions {
-			_, err := AddQuestion(q)
+			_, err := stores.Questions.Add(q)
 			if err != nil {
 				t.Fatalf("Failed to add question: %v", err)
 			}
 		}
 
 		// Get a random question
-		question, err := GetRandomQuestion()
+		question, err := stores.Questions.GetRandom()
 		if err != nil {
 			t.Fatalf("Failed to get random question: %v", err)
 		}
@@ -82,10 +70,8 @@ func TestQuestionModel(t *testing.T) {
 
 	// Test GetAllQuestions
 	t.Run("GetAllQuestions", func(t *testing.T) {
-		// Reset database
-		database.Close()
-		os.Remove(testDB)
-		database.Initialize(testDB)
+		// Use a fresh database
+		stores, _ := newTestStores(t)
 
 		// Add some questions
 		expectedQuestions := []string{
@@ -95,14 +81,14 @@ func TestQuestionModel(t *testing.T) {
 		}
 
 		for _, q := range expectedQuestions {
-			_, err := AddQuestion(q)
+			_, err := stores.Questions.Add(q)
 			if err != nil {
 				t.Fatalf("Failed to add question: %v", err)
 			}
 		}
 
 		// Get all questions
-		questions, err := GetAllQuestions()
+		questions, err := stores.Questions.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get all questions: %v", err)
 		}
@@ -131,20 +117,20 @@ func TestQuestionModel(t *testing.T) {
 	t.Run("QuestionCRUD", func(t *testing.T) {
 		// Create
 		content := "Original question"
-		question, err := AddQuestion(content)
+		question, err := stores.Questions.Add(content)
 		if err != nil {
 			t.Fatalf("Failed to add question: %v", err)
 		}
 
 		// Update
 		newContent := "Updated question"
-		err = UpdateQuestion(question.ID, newContent)
+		err = stores.Questions.Update(question.ID, newContent)
 		if err != nil {
 			t.Fatalf("Failed to update question: %v", err)
 		}
 
 		// Get all to verify update
-		questions, err := GetAllQuestions()
+		questions, err := stores.Questions.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get questions: %v", err)
 		}
@@ -165,19 +151,19 @@ func TestQuestionModel(t *testing.T) {
 		}
 
 		// Create an answer for this question to test cascade delete
-		_, err = CreateNewAnswer(question.ID, "Test answer")
+		_, err = stores.Answers.Create(question.ID, "Test answer")
 		if err != nil {
 			t.Fatalf("Failed to create answer: %v", err)
 		}
 
 		// Delete
-		err = DeleteQuestion(question.ID)
+		err = stores.Questions.Delete(question.ID)
 		if err != nil {
 			t.Fatalf("Failed to delete question: %v", err)
 		}
 
 		// Verify deletion
-		questions, err = GetAllQuestions()
+		questions, err = stores.Questions.GetAll()
 		if err != nil {
 			t.Fatalf("Failed to get questions: %v", err)
 		}
@@ -190,7 +176,7 @@ func TestQuestionModel(t *testing.T) {
 		}
 
 		// Verify associated answers were deleted
-		answers, err := GetAnswerHistoryByQuestionID(question.ID)
+		answers, err := stores.Answers.GetHistoryByQuestionID(question.ID)
 		if err != nil {
 			t.Fatalf("Failed to get answers: %v", err)
 		}
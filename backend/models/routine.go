@@ -0,0 +1,507 @@
+// backend/models/routine.go
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// Types of routine step. Most are done by writing one of the existing
+// kinds of entry; intentions and reviews are written in the routine itself.
+const (
+	StepAffirmation = EntryAffirmation
+	StepAnswer      = EntryAnswer
+	StepGratitude   = EntryGratitude
+	StepCreativity  = EntryCreativity
+	StepIntention   = "intention"
+	StepReview      = "review" // Looks back at the day's intention
+)
+
+// Built-in routines
+const (
+	RoutineMorning = "morning" // Affirmation and intention
+	RoutineEvening = "evening" // Question, gratitude and a review of the morning's intention
+)
+
+// Routine is a guided sequence of steps done once a day
+type Routine struct {
+	ID        int64         `json:"id"`
+	UUID      string        `json:"uuid"`
+	Builtin   string        `json:"builtin"` // Empty for user-defined routines
+	Name      string        `json:"name"`
+	Steps     []RoutineStep `json:"steps"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// RoutineStep is one step of a routine
+type RoutineStep struct {
+	Key   string `json:"key"` // Identifies the step, so steps can be reordered and relabelled
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// RoutineRun is a routine done on one day
+type RoutineRun struct {
+	ID          int64      `json:"id"`
+	UUID        string     `json:"uuid"`
+	RoutineID   int64      `json:"routineId"`
+	Date        string     `json:"date"` // YYYY-MM-DD
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt"` // Set once every step is done
+}
+
+// RoutineStatus is how far through a routine the user is on a day
+type RoutineStatus struct {
+	Routine Routine             `json:"routine"`
+	Date    string              `json:"date"`
+	Run     *RoutineRun         `json:"run"` // nil until the routine is started
+	Steps   []RoutineStepStatus `json:"steps"`
+	Done    bool                `json:"done"`
+}
+
+// RoutineStepStatus is whether a step of a routine has been done
+type RoutineStepStatus struct {
+	Step      RoutineStep   `json:"step"`
+	Done      bool          `json:"done"`
+	DoneAt    *time.Time    `json:"doneAt"`
+	Entry     *EntrySummary `json:"entry"`               // The entry written for the step, if any
+	Note      string        `json:"note"`                // The intention or review written in the step
+	Intention string        `json:"intention,omitempty"` // For review steps, the intention set that day
+}
+
+// RoutineStore manages routines and the days they are done
+type RoutineStore interface {
+	GetAll() ([]Routine, error)
+	GetByID(id int64) (*Routine, error)
+	Create(routine Routine) (*Routine, error)
+	Update(routine Routine) error
+	Delete(id int64) error
+	Start(routineID int64, date string) (*RoutineRun, error)
+	CompleteStep(runID int64, stepKey string, entryID int64, note string) (*RoutineStatus, error)
+	GetStatus(date string) ([]RoutineStatus, error)
+}
+
+type sqlRoutineStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewRoutineStore creates a RoutineStore backed by db
+func NewRoutineStore(db *sql.DB, bus *events.Bus) RoutineStore {
+	return &sqlRoutineStore{db: db, bus: bus}
+}
+
+const routineColumns = `id, uuid, COALESCE(builtin, ''), name, steps, created_at, updated_at`
+
+func scanRoutine(row interface{ Scan(...any) error }) (*Routine, error) {
+	var r Routine
+	var steps string
+	err := row.Scan(&r.ID, &r.UUID, &r.Builtin, &r.Name, &steps, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(steps), &r.Steps); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// validateRoutine checks the fields the user can set, filling in step keys
+// left empty
+func validateRoutine(r *Routine) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("routine name is required")
+	}
+	if len(r.Steps) == 0 {
+		return fmt.Errorf("a routine needs at least one step")
+	}
+
+	keys := map[string]bool{}
+	for i := range r.Steps {
+		step := &r.Steps[i]
+		switch step.Type {
+		case StepAffirmation, StepAnswer, StepGratitude, StepCreativity, StepIntention, StepReview:
+		default:
+			return fmt.Errorf("unknown routine step type %q", step.Type)
+		}
+		if step.Key = strings.TrimSpace(step.Key); step.Key == "" {
+			step.Key = step.Type
+			for n := 2; keys[step.Key]; n++ {
+				step.Key = fmt.Sprintf("%s-%d", step.Type, n)
+			}
+		}
+		if keys[step.Key] {
+			return fmt.Errorf("two steps are both called %q", step.Key)
+		}
+		keys[step.Key] = true
+		step.Label = strings.TrimSpace(step.Label)
+	}
+	return nil
+}
+
+// GetAll returns the routines, built-in ones first
+func (s *sqlRoutineStore) GetAll() ([]Routine, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + routineColumns + `
+		FROM routines
+		ORDER BY builtin IS NULL, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	routines := []Routine{}
+	for rows.Next() {
+		r, err := scanRoutine(rows)
+		if err != nil {
+			return nil, err
+		}
+		routines = append(routines, *r)
+	}
+	return routines, rows.Err()
+}
+
+// GetByID returns a routine
+func (s *sqlRoutineStore) GetByID(id int64) (*Routine, error) {
+	return scanRoutine(s.db.QueryRow(`SELECT `+routineColumns+` FROM routines WHERE id = ?`, id))
+}
+
+// Create adds a user-defined routine
+func (s *sqlRoutineStore) Create(routine Routine) (*Routine, error) {
+	if err := validateRoutine(&routine); err != nil {
+		return nil, err
+	}
+	steps, err := json.Marshal(routine.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	routine.UUID = ids.New()
+	routine.Builtin = ""
+	routine.CreatedAt, routine.UpdatedAt = now, now
+
+	res, err := s.db.Exec(`
+		INSERT INTO routines (uuid, name, steps, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)`, routine.UUID, routine.Name, string(steps), now, now)
+	if err != nil {
+		return nil, err
+	}
+	if routine.ID, err = res.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.RoutineCreated, ID: routine.ID})
+	return &routine, nil
+}
+
+// Update changes a routine's name and steps. Steps already done keep their
+// records, matched by key.
+func (s *sqlRoutineStore) Update(routine Routine) error {
+	if err := validateRoutine(&routine); err != nil {
+		return err
+	}
+	steps, err := json.Marshal(routine.Steps)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(`
+		UPDATE routines SET name = ?, steps = ?, updated_at = ?
+		WHERE id = ?`, routine.Name, string(steps), time.Now(), routine.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	s.bus.Publish(events.Event{Type: events.RoutineUpdated, ID: routine.ID})
+	return nil
+}
+
+// Delete removes a user-defined routine and its runs. Built-in routines
+// can be changed but not deleted.
+func (s *sqlRoutineStore) Delete(id int64) error {
+	routine, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if routine.Builtin != "" {
+		return fmt.Errorf("the %s routine is built in and can't be deleted", routine.Name)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM routine_run_steps WHERE run_id IN (SELECT id FROM routine_runs WHERE routine_id = ?)`,
+		`DELETE FROM routine_runs WHERE routine_id = ?`,
+		`DELETE FROM routines WHERE id = ?`,
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.bus.Publish(events.Event{Type: events.RoutineDeleted, ID: id})
+	return nil
+}
+
+const routineRunColumns = `id, uuid, routine_id, run_date, started_at, completed_at`
+
+func scanRoutineRun(row interface{ Scan(...any) error }) (*RoutineRun, error) {
+	var run RoutineRun
+	var completedAt sql.NullTime
+	err := row.Scan(&run.ID, &run.UUID, &run.RoutineID, &run.Date, &run.StartedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		run.CompletedAt = &completedAt.Time
+	}
+	return &run, nil
+}
+
+// run returns a routine's run on a date, or nil if it hasn't been started.
+// Devices that each started the routine leave several runs once synced, so
+// the first is used.
+func (s *sqlRoutineStore) run(routineID int64, date string) (*RoutineRun, error) {
+	run, err := scanRoutineRun(s.db.QueryRow(`
+		SELECT `+routineRunColumns+` FROM routine_runs
+		WHERE routine_id = ? AND run_date = ?
+		ORDER BY id LIMIT 1`, routineID, date))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return run, err
+}
+
+// Start starts a routine on a date, today if it's empty, returning the
+// run already started if there is one
+func (s *sqlRoutineStore) Start(routineID int64, date string) (*RoutineRun, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+	if _, err := s.GetByID(routineID); err != nil {
+		return nil, err
+	}
+
+	run, err := s.run(routineID, date)
+	if err != nil || run != nil {
+		return run, err
+	}
+
+	run = &RoutineRun{UUID: ids.New(), RoutineID: routineID, Date: date, StartedAt: time.Now()}
+	res, err := s.db.Exec(`
+		INSERT INTO routine_runs (uuid, routine_id, run_date, started_at)
+		VALUES (?, ?, ?, ?)`, run.UUID, routineID, date, run.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+	if run.ID, err = res.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.RoutineProgressed, ID: routineID, Date: date})
+	return run, nil
+}
+
+// CompleteStep records that a step of a run was done. Steps done by
+// writing an entry are given the entry's ID, or 0 if it wasn't written in
+// the routine; intentions and reviews are given the note written.
+func (s *sqlRoutineStore) CompleteStep(runID int64, stepKey string, entryID int64, note string) (*RoutineStatus, error) {
+	run, err := scanRoutineRun(s.db.QueryRow(`SELECT `+routineRunColumns+` FROM routine_runs WHERE id = ?`, runID))
+	if err != nil {
+		return nil, err
+	}
+	routine, err := s.GetByID(run.RoutineID)
+	if err != nil {
+		return nil, err
+	}
+
+	var step *RoutineStep
+	for i := range routine.Steps {
+		if routine.Steps[i].Key == stepKey {
+			step = &routine.Steps[i]
+		}
+	}
+	if step == nil {
+		return nil, fmt.Errorf("the %s routine has no step %q", routine.Name, stepKey)
+	}
+
+	note = strings.TrimSpace(note)
+	entryUUID := ""
+	switch step.Type {
+	case StepIntention, StepReview:
+		if note == "" {
+			return nil, fmt.Errorf("write your %s to complete the step", step.Type)
+		}
+	default:
+		if entryID != 0 {
+			entry, err := loadEntry(s.db, step.Type, entryID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%s %d not found", step.Type, entryID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			entryUUID = entry.UUID
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var existing int64
+	err = tx.QueryRow(`
+		SELECT id FROM routine_run_steps WHERE run_id = ? AND step_key = ?
+		ORDER BY id LIMIT 1`, runID, stepKey).Scan(&existing)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.Exec(`
+			INSERT INTO routine_run_steps (uuid, run_id, step_key, step_type, entry_uuid, note, done_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, ids.New(), runID, stepKey, step.Type, entryUUID, note, now)
+	case err == nil:
+		_, err = tx.Exec(`
+			UPDATE routine_run_steps SET step_type = ?, entry_uuid = ?, note = ?, done_at = ?
+			WHERE id = ?`, step.Type, entryUUID, note, now, existing)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	status, err := s.status(*routine, run.Date)
+	if err != nil {
+		return nil, err
+	}
+	if status.Done && run.CompletedAt == nil {
+		if _, err := s.db.Exec(`UPDATE routine_runs SET completed_at = ? WHERE id = ?`, now, runID); err != nil {
+			return nil, err
+		}
+		status.Run.CompletedAt = &now
+	}
+
+	s.bus.Publish(events.Event{Type: events.RoutineProgressed, ID: routine.ID, Date: run.Date})
+	return status, nil
+}
+
+// GetStatus returns how far through each routine the user is on a date
+func (s *sqlRoutineStore) GetStatus(date string) ([]RoutineStatus, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+
+	routines, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	list := []RoutineStatus{}
+	for _, routine := range routines {
+		status, err := s.status(routine, date)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *status)
+	}
+	return list, nil
+}
+
+// stepRecord is a step done in any run of a routine on a day
+type stepRecord struct {
+	stepType  string
+	entryUUID string
+	note      string
+	doneAt    time.Time
+}
+
+func (s *sqlRoutineStore) status(routine Routine, date string) (*RoutineStatus, error) {
+	run, err := s.run(routine.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT st.step_key, st.step_type, st.entry_uuid, st.note, st.done_at
+		FROM routine_run_steps st
+		JOIN routine_runs r ON r.id = st.run_id
+		WHERE r.routine_id = ? AND r.run_date = ?
+		ORDER BY st.done_at`, routine.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	records := map[string]stepRecord{}
+	for rows.Next() {
+		var key string
+		var rec stepRecord
+		if err := rows.Scan(&key, &rec.stepType, &rec.entryUUID, &rec.note, &rec.doneAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		records[key] = rec
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Reviews look back at the latest intention set that day in any routine
+	var intention string
+	err = s.db.QueryRow(`
+		SELECT st.note
+		FROM routine_run_steps st
+		JOIN routine_runs r ON r.id = st.run_id
+		WHERE r.run_date = ? AND st.step_type = ?
+		ORDER BY st.done_at DESC LIMIT 1`, date, StepIntention).Scan(&intention)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	status := RoutineStatus{Routine: routine, Date: date, Run: run, Steps: []RoutineStepStatus{}, Done: true}
+	for _, step := range routine.Steps {
+		st := RoutineStepStatus{Step: step}
+		if step.Type == StepReview {
+			st.Intention = intention
+		}
+		if rec, ok := records[step.Key]; ok {
+			st.Done = true
+			st.DoneAt = &rec.doneAt
+			st.Note = rec.note
+			if rec.entryUUID != "" {
+				entries, err := loadEntries(s.db, step.Type, entryColumn(step.Type, "uuid")+" = ?", rec.entryUUID)
+				if err != nil {
+					return nil, err
+				}
+				st.Entry = first(entries, "")
+			}
+		}
+		status.Done = status.Done && st.Done
+		status.Steps = append(status.Steps, st)
+	}
+	return &status, nil
+}
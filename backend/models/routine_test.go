@@ -0,0 +1,124 @@
+// backend/models/routine_test.go
+package models
+
+import (
+	"testing"
+)
+
+func TestRoutineModel(t *testing.T) {
+	t.Parallel()
+	stores, _ := newTestStores(t)
+
+	routines, err := stores.Routines.GetAll()
+	if err != nil {
+		t.Fatalf("Failed to get routines: %v", err)
+	}
+	if len(routines) != 2 || routines[0].Builtin != RoutineMorning || routines[1].Builtin != RoutineEvening {
+		t.Fatalf("Expected the morning and evening routines built in, got %+v", routines)
+	}
+	morning, evening := routines[0], routines[1]
+	date := "2024-05-06"
+
+	// Test that steps are recorded against the day's run
+	t.Run("Morning", func(t *testing.T) {
+		run, err := stores.Routines.Start(morning.ID, date)
+		if err != nil {
+			t.Fatalf("Failed to start routine: %v", err)
+		}
+		again, err := stores.Routines.Start(morning.ID, date)
+		if err != nil {
+			t.Fatalf("Failed to start routine: %v", err)
+		}
+		if again.ID != run.ID {
+			t.Errorf("Expected the day's run reused, got %d and %d", run.ID, again.ID)
+		}
+
+		affirmation, err := stores.Affirmations.Save("I am steady")
+		if err != nil {
+			t.Fatalf("Failed to save affirmation: %v", err)
+		}
+		status, err := stores.Routines.CompleteStep(run.ID, "affirmation", affirmation.ID, "")
+		if err != nil {
+			t.Fatalf("Failed to complete step: %v", err)
+		}
+		if status.Done || !status.Steps[0].Done || status.Steps[0].Entry == nil || status.Steps[0].Entry.ID != affirmation.ID {
+			t.Errorf("Expected only the affirmation step done, got %+v", status)
+		}
+
+		if _, err := stores.Routines.CompleteStep(run.ID, "intention", 0, " "); err == nil {
+			t.Error("Expected an empty intention to be rejected")
+		}
+		if _, err := stores.Routines.CompleteStep(run.ID, "walk", 0, ""); err == nil {
+			t.Error("Expected an unknown step to be rejected")
+		}
+
+		status, err = stores.Routines.CompleteStep(run.ID, "intention", 0, "Be patient")
+		if err != nil {
+			t.Fatalf("Failed to complete step: %v", err)
+		}
+		if !status.Done || status.Run.CompletedAt == nil {
+			t.Errorf("Expected the routine done, got %+v", status)
+		}
+	})
+
+	// Test that the evening review sees the morning's intention
+	t.Run("Status", func(t *testing.T) {
+		statuses, err := stores.Routines.GetStatus(date)
+		if err != nil {
+			t.Fatalf("Failed to get routine status: %v", err)
+		}
+		if len(statuses) != 2 || !statuses[0].Done || statuses[1].Run != nil {
+			t.Fatalf("Expected the morning done and the evening not started, got %+v", statuses)
+		}
+		review := statuses[1].Steps[2]
+		if review.Step.Type != StepReview || review.Intention != "Be patient" || review.Done {
+			t.Errorf("Expected the review to show the intention, got %+v", review)
+		}
+
+		other, _ := stores.Routines.GetStatus("2024-05-07")
+		if other[0].Done || other[1].Steps[2].Intention != "" {
+			t.Errorf("Expected nothing done the next day, got %+v", other)
+		}
+	})
+
+	// Test that routines can be configured
+	t.Run("Configure", func(t *testing.T) {
+		if _, err := stores.Routines.Create(Routine{Name: "Walk", Steps: []RoutineStep{{Type: "jog"}}}); err == nil {
+			t.Error("Expected an unknown step type to be rejected")
+		}
+
+		routine, err := stores.Routines.Create(Routine{Name: "Lunch break", Steps: []RoutineStep{
+			{Type: StepGratitude}, {Type: StepGratitude}, {Type: StepCreativity, Label: "Sketch"},
+		}})
+		if err != nil {
+			t.Fatalf("Failed to create routine: %v", err)
+		}
+		if routine.Steps[1].Key != "gratitude-2" {
+			t.Errorf("Expected distinct step keys, got %+v", routine.Steps)
+		}
+
+		evening.Steps = evening.Steps[1:]
+		if err := stores.Routines.Update(evening); err != nil {
+			t.Fatalf("Failed to update routine: %v", err)
+		}
+		stored, _ := stores.Routines.GetByID(evening.ID)
+		if len(stored.Steps) != 2 || stored.Steps[0].Key != "gratitude" {
+			t.Errorf("Expected the question step removed, got %+v", stored.Steps)
+		}
+
+		if err := stores.Routines.Delete(morning.ID); err == nil {
+			t.Error("Expected a built-in routine to be kept")
+		}
+		run, _ := stores.Routines.Start(routine.ID, date)
+		if _, err := stores.Routines.CompleteStep(run.ID, "gratitude", 0, ""); err != nil {
+			t.Fatalf("Failed to complete step: %v", err)
+		}
+		if err := stores.Routines.Delete(routine.ID); err != nil {
+			t.Fatalf("Failed to delete routine: %v", err)
+		}
+		routines, _ := stores.Routines.GetAll()
+		if len(routines) != 2 {
+			t.Errorf("Expected the routine deleted, got %+v", routines)
+		}
+	})
+}
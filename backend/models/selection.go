@@ -0,0 +1,491 @@
+// backend/models/selection.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/ids"
+)
+
+// Question selection strategies
+const (
+	StrategyRandom     = "random"
+	StrategySequential = "sequential" // Each question in turn, in the order they were added
+	StrategyWeighted   = "weighted"   // Favours questions the answers so far have neglected
+	StrategyScheduled  = "scheduled"  // One question all day, moving on to the next each day
+)
+
+// Reasons for skipping a question
+const (
+	SkipNotRelevant = "not_relevant"
+	SkipAnswerLater = "answer_later"
+	SkipOther       = "other"
+	SkipSnoozed     = "snoozed" // Set aside until a date with Snooze
+)
+
+// QuestionStrategySetting names the strategy questions are picked with
+const QuestionStrategySetting = "questions.strategy"
+
+// lastQuestionSetting keeps the ID of the question picked last
+const lastQuestionSetting = "questions.last_picked"
+
+// QuestionSkip records that the user passed over or snoozed a question
+type QuestionSkip struct {
+	ID            int64      `json:"id"`
+	UUID          string     `json:"uuid"`
+	QuestionID    int64      `json:"questionId"`
+	Reason        string     `json:"reason"` // "not_relevant", "answer_later", "other" or "snoozed"
+	SkippedAt     time.Time  `json:"skippedAt"`
+	ExcludedUntil *time.Time `json:"excludedUntil"` // The question isn't picked before this
+}
+
+// SkippedQuestion is a question with how often it has been passed over,
+// for finding questions worth rewording or removing
+type SkippedQuestion struct {
+	Question    Question       `json:"question"`
+	Skips       int            `json:"skips"` // Not counting snoozes
+	Snoozes     int            `json:"snoozes"`
+	Reasons     map[string]int `json:"reasons"`
+	Answers     int            `json:"answers"`
+	LastSkipped time.Time      `json:"lastSkipped"`
+}
+
+// QuestionCandidate is a question with the history a strategy can weigh
+type QuestionCandidate struct {
+	Question     Question
+	Answers      int
+	LastAnswered *time.Time // nil if never answered
+	AverageWords float64
+	Skips        []QuestionSkip // Newest first
+}
+
+// SelectionContext is what a strategy picks a question with
+type SelectionContext struct {
+	Now  time.Time
+	Rand *rand.Rand
+	Last int64 // ID of the question picked last, or 0
+
+	// All is every question, including those left out of the candidates
+	// for being skipped or snoozed
+	All []QuestionCandidate
+}
+
+// QuestionStrategy picks the next question to ask. Candidates are in the
+// order the questions were added, and there is at least one. It returns
+// the index of the candidate picked.
+type QuestionStrategy interface {
+	Pick(candidates []QuestionCandidate, ctx SelectionContext) int
+}
+
+// QuestionStrategyFunc lets a function be used as a QuestionStrategy
+type QuestionStrategyFunc func(candidates []QuestionCandidate, ctx SelectionContext) int
+
+// Pick calls f
+func (f QuestionStrategyFunc) Pick(candidates []QuestionCandidate, ctx SelectionContext) int {
+	return f(candidates, ctx)
+}
+
+var (
+	strategiesMu       sync.RWMutex
+	questionStrategies = map[string]QuestionStrategy{
+		StrategyRandom:     QuestionStrategyFunc(pickRandom),
+		StrategySequential: QuestionStrategyFunc(pickSequential),
+		StrategyWeighted:   QuestionStrategyFunc(pickWeighted),
+		StrategyScheduled:  QuestionStrategyFunc(pickScheduled),
+	}
+)
+
+// RegisterQuestionStrategy makes a strategy available by name, replacing
+// any strategy already registered with it
+func RegisterQuestionStrategy(name string, strategy QuestionStrategy) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	questionStrategies[name] = strategy
+}
+
+// QuestionStrategies returns the names of the strategies available
+func QuestionStrategies() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	names := make([]string, 0, len(questionStrategies))
+	for name := range questionStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func questionStrategy(name string) (QuestionStrategy, error) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	strategy, ok := questionStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown question strategy %q", name)
+	}
+	return strategy, nil
+}
+
+func pickRandom(candidates []QuestionCandidate, ctx SelectionContext) int {
+	return ctx.Rand.IntN(len(candidates))
+}
+
+func pickSequential(candidates []QuestionCandidate, ctx SelectionContext) int {
+	for i, c := range candidates {
+		if c.Question.ID > ctx.Last {
+			return i
+		}
+	}
+	return 0
+}
+
+func pickScheduled(candidates []QuestionCandidate, ctx SelectionContext) int {
+	// The day picks from every question, so skipping one doesn't shift the
+	// schedule; only a question left out moves on to the next
+	all := ctx.All
+	if len(all) == 0 {
+		all = candidates
+	}
+	day := time.Date(ctx.Now.Year(), ctx.Now.Month(), ctx.Now.Day(), 0, 0, 0, 0, time.UTC)
+	start := int(day.Unix()/(24*60*60)) % len(all)
+	for offset := range all {
+		id := all[(start+offset)%len(all)].Question.ID
+		for i, c := range candidates {
+			if c.Question.ID == id {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+func pickWeighted(candidates []QuestionCandidate, ctx SelectionContext) int {
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, c := range candidates {
+		weights[i] = questionWeight(c, ctx.Now)
+		total += weights[i]
+	}
+
+	r := ctx.Rand.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return len(candidates) - 1
+}
+
+const (
+	// shortAnswerWords is the average length below which a question is
+	// worth asking again, as it hasn't had much thought
+	shortAnswerWords = 25
+
+	// skipMemory is how long a skip lowers a question's weight
+	skipMemory = 30 * 24 * time.Hour
+)
+
+// skipPenalties are how much a fresh skip lowers a question's weight, by
+// reason. The penalty fades over skipMemory.
+var skipPenalties = map[string]float64{
+	SkipNotRelevant: 0.8,
+	SkipAnswerLater: 0.3,
+	SkipOther:       0.5,
+	SkipSnoozed:     0,
+}
+
+// questionWeight favours questions never answered, answered long ago or
+// answered briefly, and disfavours questions skipped recently
+func questionWeight(c QuestionCandidate, now time.Time) float64 {
+	w := 1.0
+	if c.LastAnswered == nil {
+		w += 3
+	} else {
+		days := now.Sub(*c.LastAnswered).Hours() / 24
+		w += math.Min(math.Max(days, 0)/30, 3)
+		if c.AverageWords < shortAnswerWords {
+			w++
+		}
+	}
+
+	for _, skip := range c.Skips {
+		age := now.Sub(skip.SkippedAt)
+		if age >= skipMemory {
+			continue
+		}
+		penalty, ok := skipPenalties[skip.Reason]
+		if !ok {
+			penalty = skipPenalties[SkipOther]
+		}
+		w *= 1 - penalty*(1-float64(max(age, 0))/float64(skipMemory))
+	}
+	return math.Max(w, 0.01)
+}
+
+// Next picks the next question to ask with the chosen strategy
+func (s *sqlQuestionStore) Next() (*Question, error) {
+	name, err := s.Strategy()
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := questionStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.candidates()
+	if err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// Leave out questions skipped or snoozed until later, unless that's
+	// every question
+	now := time.Now()
+	var candidates []QuestionCandidate
+	for _, c := range all {
+		if !excluded(c, now) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = all
+	}
+
+	ctx := SelectionContext{Now: now, Rand: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), All: all}
+	if _, err := s.settings.GetJSON(lastQuestionSetting, &ctx.Last); err != nil {
+		return nil, err
+	}
+	i := strategy.Pick(candidates, ctx)
+	if i < 0 || i >= len(candidates) {
+		return nil, fmt.Errorf("question strategy %q picked candidate %d of %d", name, i, len(candidates))
+	}
+
+	question := candidates[i].Question
+	if err := s.settings.SetJSON(lastQuestionSetting, question.ID); err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// excluded reports whether a question has been skipped or snoozed until
+// after now
+func excluded(c QuestionCandidate, now time.Time) bool {
+	for _, skip := range c.Skips {
+		if skip.ExcludedUntil != nil && skip.ExcludedUntil.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates loads every question with its history
+func (s *sqlQuestionStore) candidates() ([]QuestionCandidate, error) {
+	rows, err := s.db.Query(`SELECT ` + questionColumns + ` FROM questions ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []QuestionCandidate
+	byID := map[int64]int{}
+	for rows.Next() {
+		q, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		byID[q.ID] = len(candidates)
+		candidates = append(candidates, QuestionCandidate{Question: *q, Skips: []QuestionSkip{}})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	answers, err := s.db.Query(`SELECT question_id, word_count, created_at FROM answers`)
+	if err != nil {
+		return nil, err
+	}
+	defer answers.Close()
+
+	words := map[int64]int{}
+	for answers.Next() {
+		var questionID int64
+		var count int
+		var createdAt time.Time
+		if err := answers.Scan(&questionID, &count, &createdAt); err != nil {
+			return nil, err
+		}
+		i, ok := byID[questionID]
+		if !ok {
+			continue
+		}
+		c := &candidates[i]
+		c.Answers++
+		words[questionID] += count
+		if c.LastAnswered == nil || createdAt.After(*c.LastAnswered) {
+			c.LastAnswered = &createdAt
+		}
+	}
+	if err := answers.Err(); err != nil {
+		return nil, err
+	}
+	answers.Close()
+
+	for id, count := range words {
+		c := &candidates[byID[id]]
+		c.AverageWords = float64(count) / float64(c.Answers)
+	}
+
+	skips, err := s.skips(`1`)
+	if err != nil {
+		return nil, err
+	}
+	for _, skip := range skips {
+		if i, ok := byID[skip.QuestionID]; ok {
+			candidates[i].Skips = append(candidates[i].Skips, skip)
+		}
+	}
+	return candidates, nil
+}
+
+// skips returns the skips matching a condition, newest first
+func (s *sqlQuestionStore) skips(where string, args ...any) ([]QuestionSkip, error) {
+	rows, err := s.db.Query(`
+		SELECT id, uuid, question_id, reason, skipped_at, excluded_until
+		FROM question_skips
+		WHERE `+where+`
+		ORDER BY skipped_at DESC, id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skips := []QuestionSkip{}
+	for rows.Next() {
+		var skip QuestionSkip
+		var until sql.NullTime
+		if err := rows.Scan(&skip.ID, &skip.UUID, &skip.QuestionID, &skip.Reason, &skip.SkippedAt, &until); err != nil {
+			return nil, err
+		}
+		if until.Valid {
+			skip.ExcludedUntil = &until.Time
+		}
+		skips = append(skips, skip)
+	}
+	return skips, rows.Err()
+}
+
+// Skip records that the user passed over a question. It isn't picked again
+// today, and the weighted strategy asks it less often for a while.
+func (s *sqlQuestionStore) Skip(id int64, reason string) (*QuestionSkip, error) {
+	if _, ok := skipPenalties[reason]; !ok || reason == SkipSnoozed {
+		return nil, fmt.Errorf("unknown skip reason %q", reason)
+	}
+	now := time.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	return s.addSkip(id, reason, now, tomorrow)
+}
+
+// Snooze sets a question aside until a date (YYYY-MM-DD) after today.
+// Unlike a skip, it doesn't make the question less likely afterwards.
+func (s *sqlQuestionStore) Snooze(id int64, until string) (*QuestionSkip, error) {
+	day, err := time.ParseInLocation("2006-01-02", until, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", until, err)
+	}
+	now := time.Now()
+	if !day.After(now) {
+		return nil, fmt.Errorf("a question can only be snoozed until a day after today")
+	}
+	return s.addSkip(id, SkipSnoozed, now, day)
+}
+
+func (s *sqlQuestionStore) addSkip(id int64, reason string, now, until time.Time) (*QuestionSkip, error) {
+	if _, err := s.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	skip := QuestionSkip{UUID: ids.New(), QuestionID: id, Reason: reason, SkippedAt: now, ExcludedUntil: &until}
+	res, err := s.db.Exec(`
+		INSERT INTO question_skips (uuid, question_id, reason, skipped_at, excluded_until)
+		VALUES (?, ?, ?, ?, ?)`, skip.UUID, id, reason, skip.SkippedAt, until)
+	if err != nil {
+		return nil, err
+	}
+	if skip.ID, err = res.LastInsertId(); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.QuestionSkipped, ID: id})
+	return &skip, nil
+}
+
+// GetMostSkipped returns the questions skipped most often, up to limit, or
+// all skipped questions if limit is 0. Questions only ever snoozed aren't
+// included.
+func (s *sqlQuestionStore) GetMostSkipped(limit int) ([]SkippedQuestion, error) {
+	candidates, err := s.candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := []SkippedQuestion{}
+	for _, c := range candidates {
+		sq := SkippedQuestion{Question: c.Question, Reasons: map[string]int{}, Answers: c.Answers}
+		for _, skip := range c.Skips {
+			if skip.Reason == SkipSnoozed {
+				sq.Snoozes++
+				continue
+			}
+			sq.Skips++
+			sq.Reasons[skip.Reason]++
+			if skip.SkippedAt.After(sq.LastSkipped) {
+				sq.LastSkipped = skip.SkippedAt
+			}
+		}
+		if sq.Skips > 0 {
+			skipped = append(skipped, sq)
+		}
+	}
+
+	sort.SliceStable(skipped, func(i, j int) bool {
+		if skipped[i].Skips != skipped[j].Skips {
+			return skipped[i].Skips > skipped[j].Skips
+		}
+		return skipped[i].LastSkipped.After(skipped[j].LastSkipped)
+	})
+	if limit > 0 && len(skipped) > limit {
+		skipped = skipped[:limit]
+	}
+	return skipped, nil
+}
+
+// Strategy returns the name of the strategy questions are picked with,
+// random unless another has been chosen
+func (s *sqlQuestionStore) Strategy() (string, error) {
+	name, ok, err := s.settings.Get(QuestionStrategySetting)
+	if err != nil || !ok {
+		return StrategyRandom, err
+	}
+	return name, nil
+}
+
+// SetStrategy chooses the strategy questions are picked with
+func (s *sqlQuestionStore) SetStrategy(name string) error {
+	if _, err := questionStrategy(name); err != nil {
+		return err
+	}
+	return s.settings.Set(QuestionStrategySetting, name)
+}
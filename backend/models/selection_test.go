@@ -0,0 +1,227 @@
+// backend/models/selection_test.go
+package models
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestSelection(t *testing.T) {
+	t.Parallel()
+	stores, _ := newTestStores(t)
+
+	var questions []*Question
+	for _, content := range []string{"What went well?", "What surprised you?", "Who helped you?"} {
+		q, err := stores.Questions.Add(content)
+		if err != nil {
+			t.Fatalf("Failed to add question: %v", err)
+		}
+		questions = append(questions, q)
+	}
+
+	// Test that questions with less history weigh more
+	t.Run("Weights", func(t *testing.T) {
+		now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+		recent := now.Add(-24 * time.Hour)
+		old := now.AddDate(0, -2, 0)
+
+		never := questionWeight(QuestionCandidate{}, now)
+		stale := questionWeight(QuestionCandidate{Answers: 1, LastAnswered: &old, AverageWords: 100}, now)
+		fresh := questionWeight(QuestionCandidate{Answers: 1, LastAnswered: &recent, AverageWords: 100}, now)
+		brief := questionWeight(QuestionCandidate{Answers: 1, LastAnswered: &recent, AverageWords: 5}, now)
+		if !(never > stale && stale > brief && brief > fresh) {
+			t.Errorf("Expected never > stale > brief > fresh, got %v, %v, %v, %v", never, stale, brief, fresh)
+		}
+
+		skipped := func(reason string, ago time.Duration) float64 {
+			return questionWeight(QuestionCandidate{Skips: []QuestionSkip{{Reason: reason, SkippedAt: now.Add(-ago)}}}, now)
+		}
+		if !(skipped(SkipNotRelevant, time.Hour) < skipped(SkipAnswerLater, time.Hour)) {
+			t.Error("Expected not relevant to lower the weight more than answer later")
+		}
+		if !(skipped(SkipOther, time.Hour) < skipped(SkipOther, 20*24*time.Hour)) {
+			t.Error("Expected a skip to fade over time")
+		}
+		if skipped(SkipOther, 40*24*time.Hour) != never {
+			t.Error("Expected an old skip to be forgotten")
+		}
+	})
+
+	// Test that the weighted strategy follows the weights
+	t.Run("Weighted", func(t *testing.T) {
+		now := time.Now()
+		recent := now.Add(-time.Hour)
+		candidates := []QuestionCandidate{
+			{Answers: 3, LastAnswered: &recent, AverageWords: 200, Skips: []QuestionSkip{{Reason: SkipNotRelevant, SkippedAt: now}}},
+			{},
+		}
+		ctx := SelectionContext{Now: now, Rand: rand.New(rand.NewPCG(1, 2))}
+		picks := [2]int{}
+		for range 200 {
+			picks[pickWeighted(candidates, ctx)]++
+		}
+		if picks[1] < 180 {
+			t.Errorf("Expected the unanswered question picked most often, got %v", picks)
+		}
+	})
+
+	// Test that the scheduled strategy keeps its place when questions are
+	// left out, moving on only from the one left out
+	t.Run("Scheduled", func(t *testing.T) {
+		all := make([]QuestionCandidate, 4)
+		for i := range all {
+			all[i].Question.ID = int64(i + 1)
+		}
+		// Day 19849 since the epoch, which is question 2 of 4
+		ctx := SelectionContext{Now: time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC), All: all}
+
+		if i := pickScheduled(all, ctx); all[i].Question.ID != 2 {
+			t.Errorf("Expected question 2, got %d", all[i].Question.ID)
+		}
+		withoutFirst := all[1:]
+		if i := pickScheduled(withoutFirst, ctx); withoutFirst[i].Question.ID != 2 {
+			t.Errorf("Expected question 2 with another question left out, got %d", withoutFirst[i].Question.ID)
+		}
+		withoutPicked := []QuestionCandidate{all[0], all[2], all[3]}
+		if i := pickScheduled(withoutPicked, ctx); withoutPicked[i].Question.ID != 3 {
+			t.Errorf("Expected question 3 with question 2 left out, got %d", withoutPicked[i].Question.ID)
+		}
+	})
+
+	// Test that the strategy can be chosen and is kept
+	t.Run("Strategy", func(t *testing.T) {
+		name, err := stores.Questions.Strategy()
+		if err != nil {
+			t.Fatalf("Failed to get strategy: %v", err)
+		}
+		if name != StrategyRandom {
+			t.Errorf("Expected random by default, got %q", name)
+		}
+		if err := stores.Questions.SetStrategy("alphabetical"); err == nil {
+			t.Error("Expected an unknown strategy to be rejected")
+		}
+
+		if err := stores.Questions.SetStrategy(StrategySequential); err != nil {
+			t.Fatalf("Failed to set strategy: %v", err)
+		}
+		for i := range 4 {
+			q, err := stores.Questions.Next()
+			if err != nil {
+				t.Fatalf("Failed to get next question: %v", err)
+			}
+			if want := questions[i%3].ID; q.ID != want {
+				t.Errorf("Expected question %d in turn, got %d", want, q.ID)
+			}
+		}
+
+		RegisterQuestionStrategy("test-last", QuestionStrategyFunc(func(c []QuestionCandidate, _ SelectionContext) int {
+			return len(c) - 1
+		}))
+		if err := stores.Questions.SetStrategy("test-last"); err != nil {
+			t.Fatalf("Failed to set strategy: %v", err)
+		}
+		q, err := stores.Questions.Next()
+		if err != nil {
+			t.Fatalf("Failed to get next question: %v", err)
+		}
+		if q.ID != questions[2].ID {
+			t.Errorf("Expected a registered strategy to be used, got %d", q.ID)
+		}
+	})
+
+	// Test that skips are recorded and reach the strategy
+	t.Run("Skip", func(t *testing.T) {
+		if _, err := stores.Questions.Skip(questions[0].ID, "bored"); err == nil {
+			t.Error("Expected an unknown reason to be rejected")
+		}
+		skip, err := stores.Questions.Skip(questions[0].ID, SkipAnswerLater)
+		if err != nil {
+			t.Fatalf("Failed to skip question: %v", err)
+		}
+		if skip.ID == 0 || skip.UUID == "" {
+			t.Errorf("Expected the skip stored, got %+v", skip)
+		}
+
+		candidates, err := stores.Questions.(*sqlQuestionStore).candidates()
+		if err != nil {
+			t.Fatalf("Failed to load candidates: %v", err)
+		}
+		if len(candidates[0].Skips) != 1 || candidates[0].Skips[0].Reason != SkipAnswerLater || len(candidates[1].Skips) != 0 {
+			t.Errorf("Expected the skip loaded with its question, got %+v", candidates)
+		}
+
+		if skip.ExcludedUntil == nil || !skip.ExcludedUntil.After(time.Now()) || skip.ExcludedUntil.Sub(time.Now()) > 25*time.Hour {
+			t.Errorf("Expected the question excluded until tomorrow, got %v", skip.ExcludedUntil)
+		}
+	})
+
+	// Test that skipped and snoozed questions are left out until their date
+	t.Run("Snooze", func(t *testing.T) {
+		today := time.Now().Format("2006-01-02")
+		if _, err := stores.Questions.Snooze(questions[1].ID, today); err == nil {
+			t.Error("Expected snoozing until today to be rejected")
+		}
+		if _, err := stores.Questions.Snooze(questions[1].ID, "next week"); err == nil {
+			t.Error("Expected an invalid date to be rejected")
+		}
+		if _, err := stores.Questions.Skip(questions[1].ID, SkipSnoozed); err == nil {
+			t.Error("Expected a skip to need a skip reason")
+		}
+		until := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+		snooze, err := stores.Questions.Snooze(questions[1].ID, until)
+		if err != nil {
+			t.Fatalf("Failed to snooze question: %v", err)
+		}
+		if snooze.Reason != SkipSnoozed || snooze.ExcludedUntil.Format("2006-01-02") != until {
+			t.Errorf("Expected a snooze until %s, got %+v", until, snooze)
+		}
+
+		if err := stores.Questions.SetStrategy(StrategyRandom); err != nil {
+			t.Fatalf("Failed to set strategy: %v", err)
+		}
+		for range 10 {
+			q, err := stores.Questions.Next()
+			if err != nil {
+				t.Fatalf("Failed to get next question: %v", err)
+			}
+			if q.ID != questions[2].ID {
+				t.Fatalf("Expected only the question not set aside, got %d", q.ID)
+			}
+		}
+
+		// With every question set aside, one is still picked
+		if _, err := stores.Questions.Skip(questions[2].ID, SkipOther); err != nil {
+			t.Fatalf("Failed to skip question: %v", err)
+		}
+		if _, err := stores.Questions.Next(); err != nil {
+			t.Fatalf("Failed to get next question: %v", err)
+		}
+	})
+
+	// Test that the report counts skips but not snoozes
+	t.Run("MostSkipped", func(t *testing.T) {
+		if _, err := stores.Questions.Skip(questions[0].ID, SkipNotRelevant); err != nil {
+			t.Fatalf("Failed to skip question: %v", err)
+		}
+		report, err := stores.Questions.GetMostSkipped(0)
+		if err != nil {
+			t.Fatalf("Failed to get most skipped: %v", err)
+		}
+		if len(report) != 2 || report[0].Question.ID != questions[0].ID || report[0].Skips != 2 ||
+			report[0].Reasons[SkipNotRelevant] != 1 || report[1].Question.ID != questions[2].ID {
+			t.Errorf("Expected the first question skipped most and the snoozed one left out, got %+v", report)
+		}
+		if limited, _ := stores.Questions.GetMostSkipped(1); len(limited) != 1 {
+			t.Errorf("Expected the report limited, got %d", len(limited))
+		}
+
+		if err := stores.Questions.Delete(questions[0].ID); err != nil {
+			t.Fatalf("Failed to delete question: %v", err)
+		}
+		report, _ = stores.Questions.GetMostSkipped(0)
+		if len(report) != 1 {
+			t.Errorf("Expected skips deleted with their question, got %+v", report)
+		}
+	})
+}
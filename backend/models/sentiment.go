@@ -0,0 +1,226 @@
+// backend/models/sentiment.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"myproject/backend/sentiment"
+)
+
+// Granularities a sentiment trend can be grouped by
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// SentimentPoint is the sentiment of the entries written in one period
+type SentimentPoint struct {
+	Period   string   `json:"period"`  // First day of the period, YYYY-MM-DD
+	Average  float64  `json:"average"` // From -1 to 1
+	Entries  int      `json:"entries"`
+	Positive int      `json:"positive"` // Entries scoring above zero
+	Negative int      `json:"negative"` // Entries scoring below zero
+	Mood     *float64 `json:"mood"`     // Average mood in the period, if any was recorded
+}
+
+// SentimentTrend is sentiment over time
+type SentimentTrend struct {
+	Range           DateRange        `json:"range"`
+	Granularity     string           `json:"granularity"`
+	Average         float64          `json:"average"`
+	Points          []SentimentPoint `json:"points"`
+	MoodCorrelation *float64         `json:"moodCorrelation"` // Correlation of sentiment and mood across periods with both
+}
+
+// MoodSource provides recorded mood ratings, averaged per day (YYYY-MM-DD)
+type MoodSource interface {
+	MoodByDate(r DateRange) (map[string]float64, error)
+}
+
+// SentimentStore computes sentiment trends from the scores stored on entries
+type SentimentStore interface {
+	GetTrend(r DateRange, granularity string, moods MoodSource) (*SentimentTrend, error)
+}
+
+type sqlSentimentStore struct {
+	db *sql.DB
+}
+
+// NewSentimentStore creates a SentimentStore backed by db
+func NewSentimentStore(db *sql.DB) SentimentStore {
+	return &sqlSentimentStore{db: db}
+}
+
+// periodStart returns the first day of the period containing date
+func periodStart(date string, granularity string) (string, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", err
+	}
+
+	switch granularity {
+	case GranularityDay:
+		return date, nil
+	case GranularityWeek:
+		// Weeks start on Monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, -offset).Format("2006-01-02"), nil
+	case GranularityMonth:
+		return t.Format("2006-01") + "-01", nil
+	}
+	return "", fmt.Errorf("unknown granularity %q", granularity)
+}
+
+// GetTrend averages the sentiment of answers, gratitude items and creativity
+// entries per period. When moods is not nil and has ratings in the range, the
+// trend includes each period's mood and its correlation with sentiment.
+func (s *sqlSentimentStore) GetTrend(r DateRange, granularity string, moods MoodSource) (*SentimentTrend, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := periodStart("2000-01-01", granularity); err != nil {
+		return nil, err
+	}
+
+	type bucket struct {
+		sum      float64
+		entries  int
+		positive int
+		negative int
+		moodSum  float64
+		moodDays int
+	}
+	buckets := map[string]*bucket{}
+	get := func(date string) (*bucket, error) {
+		period, err := periodStart(date, granularity)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := buckets[period]
+		if !ok {
+			b = &bucket{}
+			buckets[period] = b
+		}
+		return b, nil
+	}
+
+	trend := &SentimentTrend{Range: r, Granularity: granularity, Points: []SentimentPoint{}}
+	var total float64
+	var count int
+
+	add := func(date string, score float64) error {
+		if !r.Contains(date) {
+			return nil
+		}
+		b, err := get(date)
+		if err != nil {
+			return err
+		}
+		b.sum += score
+		b.entries++
+		if score > 0 {
+			b.positive++
+		} else if score < 0 {
+			b.negative++
+		}
+		total += score
+		count++
+		return nil
+	}
+
+	// Answers have no entry date, so they belong to their local creation date
+	rows, err := s.db.Query(`SELECT sentiment, created_at FROM answers`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var score float64
+		var createdAt time.Time
+		if err := rows.Scan(&score, &createdAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if err := add(createdAt.In(time.Local).Format("2006-01-02"), score); err != nil {
+			rows.Close()
+			return nil, err
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{"gratitude_items", "creativity_entries"} {
+		rows, err := s.db.Query(`SELECT sentiment, entry_date FROM ` + table)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var score float64
+			var date string
+			if err := rows.Scan(&score, &date); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if err := add(date, score); err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if moods != nil {
+		byDate, err := moods.MoodByDate(r)
+		if err != nil {
+			return nil, err
+		}
+		for date, mood := range byDate {
+			if !r.Contains(date) {
+				continue
+			}
+			b, err := get(date)
+			if err != nil {
+				return nil, err
+			}
+			b.moodSum += mood
+			b.moodDays++
+		}
+	}
+
+	var sentiments, moodValues []float64
+	for period, b := range buckets {
+		point := SentimentPoint{Period: period, Entries: b.entries, Positive: b.positive, Negative: b.negative}
+		if b.entries > 0 {
+			point.Average = b.sum / float64(b.entries)
+		}
+		if b.moodDays > 0 {
+			mood := b.moodSum / float64(b.moodDays)
+			point.Mood = &mood
+			if b.entries > 0 {
+				sentiments = append(sentiments, point.Average)
+				moodValues = append(moodValues, mood)
+			}
+		}
+		trend.Points = append(trend.Points, point)
+	}
+	sort.Slice(trend.Points, func(i, j int) bool {
+		return trend.Points[i].Period < trend.Points[j].Period
+	})
+
+	if count > 0 {
+		trend.Average = total / float64(count)
+	}
+	if c, ok := sentiment.Correlation(sentiments, moodValues); ok {
+		trend.MoodCorrelation = &c
+	}
+
+	return trend, nil
+}
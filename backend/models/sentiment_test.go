@@ -0,0 +1,114 @@
+// backend/models/sentiment_test.go
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedMoods is a MoodSource with preset ratings
+type fixedMoods map[string]float64
+
+func (m fixedMoods) MoodByDate(r DateRange) (map[string]float64, error) {
+	return m, nil
+}
+
+func TestSentimentTrend(t *testing.T) {
+	t.Parallel()
+
+	stores, db := newTestStores(t)
+
+	// Test that scores are stored when entries are written
+	t.Run("ScoresOnWrite", func(t *testing.T) {
+		item, err := stores.Gratitude.Add("My wonderful, supportive friends")
+		if err != nil {
+			t.Fatalf("Failed to add gratitude item: %v", err)
+		}
+
+		var score float64
+		db.QueryRow(`SELECT sentiment FROM gratitude_items WHERE id = ?`, item.ID).Scan(&score)
+		if score <= 0 {
+			t.Errorf("Expected a positive score, got %v", score)
+		}
+
+		if err := stores.Gratitude.Update(item.ID, "Survived a terrible, stressful week"); err != nil {
+			t.Fatalf("Failed to update gratitude item: %v", err)
+		}
+
+		db.QueryRow(`SELECT sentiment FROM gratitude_items WHERE id = ?`, item.ID).Scan(&score)
+		if score >= 0 {
+			t.Errorf("Expected a negative score after update, got %v", score)
+		}
+	})
+
+	// Test grouping by week and month
+	t.Run("GetTrend", func(t *testing.T) {
+		entries := map[string]string{
+			"2024-03-04": "A happy, peaceful morning",    // Monday
+			"2024-03-06": "Felt sad and lonely",          // Wednesday, same week
+			"2024-03-11": "Excited and grateful for all", // Next Monday
+			"2024-04-02": "Wrote a list of groceries",    // April, neutral
+		}
+		for date, content := range entries {
+			created, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+			entry := ImportedEntry{Type: EntryCreativity, Content: content, Date: date, CreatedAt: created}
+			if err := stores.Imports.Import([]ImportedEntry{entry}); err != nil {
+				t.Fatalf("Failed to import creativity entry: %v", err)
+			}
+		}
+
+		r := DateRange{From: "2024-03-01", To: "2024-04-30"}
+		weekly, err := stores.Sentiment.GetTrend(r, GranularityWeek, nil)
+		if err != nil {
+			t.Fatalf("Failed to get trend: %v", err)
+		}
+
+		if len(weekly.Points) != 3 {
+			t.Fatalf("Expected 3 weeks, got %+v", weekly.Points)
+		}
+
+		first := weekly.Points[0]
+		if first.Period != "2024-03-04" || first.Entries != 2 || first.Positive != 1 || first.Negative != 1 {
+			t.Errorf("Unexpected first week %+v", first)
+		}
+
+		if weekly.Points[1].Average <= 0 {
+			t.Errorf("Expected the second week to be positive, got %v", weekly.Points[1].Average)
+		}
+
+		monthly, err := stores.Sentiment.GetTrend(r, GranularityMonth, nil)
+		if err != nil {
+			t.Fatalf("Failed to get trend: %v", err)
+		}
+
+		if len(monthly.Points) != 2 || monthly.Points[0].Period != "2024-03-01" || monthly.Points[0].Entries != 3 {
+			t.Errorf("Unexpected monthly points %+v", monthly.Points)
+		}
+
+		if monthly.MoodCorrelation != nil {
+			t.Errorf("Expected no mood correlation without mood data")
+		}
+
+		if _, err := stores.Sentiment.GetTrend(r, "year", nil); err == nil {
+			t.Errorf("Expected error for unknown granularity")
+		}
+	})
+
+	// Test correlation with mood ratings
+	t.Run("MoodCorrelation", func(t *testing.T) {
+		moods := fixedMoods{"2024-03-04": 4, "2024-03-06": 1, "2024-03-11": 5}
+
+		trend, err := stores.Sentiment.GetTrend(DateRange{From: "2024-03-01", To: "2024-03-31"}, GranularityDay, moods)
+		if err != nil {
+			t.Fatalf("Failed to get trend: %v", err)
+		}
+
+		if trend.MoodCorrelation == nil || *trend.MoodCorrelation < 0.5 {
+			t.Errorf("Expected a strong positive mood correlation, got %v", trend.MoodCorrelation)
+		}
+
+		if trend.Points[0].Mood == nil || *trend.Points[0].Mood != 4 {
+			t.Errorf("Expected the first day to carry its mood, got %+v", trend.Points[0])
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// backend/models/settings.go
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SettingsStore manages key/value application settings
+type SettingsStore interface {
+	Get(key string) (string, bool, error)
+	Set(key string, value string) error
+	GetJSON(key string, v interface{}) (bool, error)
+	SetJSON(key string, v interface{}) error
+}
+
+type sqlSettingsStore struct {
+	db *sql.DB
+}
+
+// NewSettingsStore creates a SettingsStore backed by db
+func NewSettingsStore(db *sql.DB) SettingsStore {
+	return &sqlSettingsStore{db: db}
+}
+
+// Get retrieves a setting value, reporting whether it has been set
+func (s *sqlSettingsStore) Get(key string) (string, bool, error) {
+	var value string
+
+	err := s.db.QueryRow(`
+		SELECT value 
+		FROM settings 
+		WHERE key = ?`, key).Scan(&value)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+// Set creates or replaces a setting value
+func (s *sqlSettingsStore) Set(key string, value string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO settings (key, value, updated_at) 
+		VALUES (?, ?, ?) 
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		key, value, time.Now())
+	return err
+}
+
+// GetJSON decodes a JSON setting into v, reporting whether it has been set
+func (s *sqlSettingsStore) GetJSON(key string, v interface{}) (bool, error) {
+	value, ok, err := s.Get(key)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, json.Unmarshal([]byte(value), v)
+}
+
+// SetJSON stores v as a JSON setting
+func (s *sqlSettingsStore) SetJSON(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Set(key, string(data))
+}
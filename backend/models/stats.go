@@ -0,0 +1,289 @@
+// backend/models/stats.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"myproject/backend/ids"
+	"myproject/backend/textstats"
+)
+
+// previewLength is how many characters of an entry a summary shows
+const previewLength = 80
+
+// maxSessionLength caps a writing session, so a session left open while the
+// app sat idle doesn't count as hours of writing
+const maxSessionLength = 2 * time.Hour
+
+// WritingSession is a span of time the user spent writing
+type WritingSession struct {
+	ID        int64      `json:"id"`
+	Activity  string     `json:"activity"` // e.g. "answer" or "creativity"
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt"`
+}
+
+// Duration is how long the session lasted, capped at maxSessionLength.
+// Open sessions have no duration.
+func (ws WritingSession) Duration() time.Duration {
+	if ws.EndedAt == nil {
+		return 0
+	}
+	d := ws.EndedAt.Sub(ws.StartedAt)
+	if d < 0 {
+		return 0
+	}
+	if d > maxSessionLength {
+		return maxSessionLength
+	}
+	return d
+}
+
+// DateRange selects entries between From and To (inclusive, YYYY-MM-DD).
+// An empty end leaves that end of the range open.
+type DateRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Contains reports whether a YYYY-MM-DD date is inside the range
+func (r DateRange) Contains(date string) bool {
+	return (r.From == "" || date >= r.From) && (r.To == "" || date <= r.To)
+}
+
+// Validate checks the dates are YYYY-MM-DD and in order
+func (r DateRange) Validate() error {
+	for _, d := range []string{r.From, r.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", d)
+		}
+	}
+	if r.From != "" && r.To != "" && r.From > r.To {
+		return fmt.Errorf("range starts after it ends")
+	}
+	return nil
+}
+
+// WritingTotals are the totals for one kind of entry
+type WritingTotals struct {
+	Entries    int `json:"entries"`
+	Words      int `json:"words"`
+	Characters int `json:"characters"`
+}
+
+// LongestEntry identifies the entry with the most words
+type LongestEntry struct {
+	Type    string `json:"type"` // "answer" or "creativity"
+	ID      int64  `json:"id"`
+	Date    string `json:"date"`
+	Words   int    `json:"words"`
+	Preview string `json:"preview"`
+}
+
+// WritingStats summarises writing over a date range
+type WritingStats struct {
+	Range                DateRange             `json:"range"`
+	Entries              int                   `json:"entries"`
+	Words                int                   `json:"words"`
+	Characters           int                   `json:"characters"`
+	ActiveDays           int                   `json:"activeDays"`         // Days with at least one entry
+	AverageWordsPerDay   float64               `json:"averageWordsPerDay"` // Averaged over active days
+	AverageWordsPerEntry float64               `json:"averageWordsPerEntry"`
+	Answers              WritingTotals         `json:"answers"`
+	Creativity           WritingTotals         `json:"creativity"`
+	Sessions             int                   `json:"sessions"`
+	SecondsWriting       int                   `json:"secondsWriting"`
+	AverageSecondsPerDay float64               `json:"averageSecondsPerDay"` // Averaged over days with a session
+	Longest              *LongestEntry         `json:"longest"`
+	TopWords             []textstats.WordCount `json:"topWords"`
+}
+
+// topWordCount is how many frequent words GetStats reports
+const topWordCount = 20
+
+// WritingStore tracks writing sessions and computes writing statistics
+type WritingStore interface {
+	StartSession(activity string) (*WritingSession, error)
+	EndSession(id int64) (*WritingSession, error)
+	GetStats(r DateRange) (*WritingStats, error)
+}
+
+type sqlWritingStore struct {
+	db *sql.DB
+}
+
+// NewWritingStore creates a WritingStore backed by db
+func NewWritingStore(db *sql.DB) WritingStore {
+	return &sqlWritingStore{db: db}
+}
+
+// StartSession records that the user started writing
+func (s *sqlWritingStore) StartSession(activity string) (*WritingSession, error) {
+	if strings.TrimSpace(activity) == "" {
+		return nil, fmt.Errorf("writing session activity is required")
+	}
+
+	now := time.Now()
+	res, err := s.db.Exec(`
+		INSERT INTO writing_sessions (uuid, activity, started_at)
+		VALUES (?, ?, ?)`, ids.New(), activity, now)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WritingSession{ID: id, Activity: activity, StartedAt: now}, nil
+}
+
+// EndSession records that the user stopped writing. Ending a session that
+// already ended leaves it unchanged.
+func (s *sqlWritingStore) EndSession(id int64) (*WritingSession, error) {
+	_, err := s.db.Exec(`
+		UPDATE writing_sessions
+		SET ended_at = ?
+		WHERE id = ? AND ended_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	var session WritingSession
+	var endedAt sql.NullTime
+	err = s.db.QueryRow(`
+		SELECT id, activity, started_at, ended_at
+		FROM writing_sessions
+		WHERE id = ?`, id).Scan(&session.ID, &session.Activity, &session.StartedAt, &endedAt)
+	if err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		session.EndedAt = &endedAt.Time
+	}
+
+	return &session, nil
+}
+
+// GetStats computes writing statistics for the entries and sessions in r.
+// Answers and sessions belong to their local creation date, creativity
+// entries to their entry date.
+func (s *sqlWritingStore) GetStats(r DateRange) (*WritingStats, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	stats := &WritingStats{Range: r, TopWords: []textstats.WordCount{}}
+	days := map[string]bool{}
+	words := textstats.Frequencies{}
+
+	add := func(kind string, totals *WritingTotals, id int64, date string, content string, wordCount int, charCount int) {
+		totals.Entries++
+		totals.Words += wordCount
+		totals.Characters += charCount
+		days[date] = true
+		words.Add(content)
+
+		if stats.Longest == nil || wordCount > stats.Longest.Words {
+			stats.Longest = &LongestEntry{Type: kind, ID: id, Date: date, Words: wordCount, Preview: textstats.Preview(content, previewLength)}
+		}
+	}
+
+	rows, err := s.db.Query(`SELECT id, content, word_count, char_count, created_at FROM answers ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		var content string
+		var wordCount, charCount int
+		var createdAt time.Time
+		if err := rows.Scan(&id, &content, &wordCount, &charCount, &createdAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		date := createdAt.In(time.Local).Format("2006-01-02")
+		if r.Contains(date) {
+			add("answer", &stats.Answers, id, date, content, wordCount, charCount)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.Query(`SELECT id, content, word_count, char_count, entry_date FROM creativity_entries ORDER BY entry_date ASC`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		var content, date string
+		var wordCount, charCount int
+		if err := rows.Scan(&id, &content, &wordCount, &charCount, &date); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if r.Contains(date) {
+			add("creativity", &stats.Creativity, id, date, content, wordCount, charCount)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats.Entries = stats.Answers.Entries + stats.Creativity.Entries
+	stats.Words = stats.Answers.Words + stats.Creativity.Words
+	stats.Characters = stats.Answers.Characters + stats.Creativity.Characters
+	stats.ActiveDays = len(days)
+	if stats.ActiveDays > 0 {
+		stats.AverageWordsPerDay = float64(stats.Words) / float64(stats.ActiveDays)
+	}
+	if stats.Entries > 0 {
+		stats.AverageWordsPerEntry = float64(stats.Words) / float64(stats.Entries)
+	}
+	stats.TopWords = words.Top(topWordCount)
+
+	rows, err = s.db.Query(`SELECT id, activity, started_at, ended_at FROM writing_sessions WHERE ended_at IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var total time.Duration
+	sessionDays := map[string]bool{}
+	for rows.Next() {
+		var session WritingSession
+		var endedAt sql.NullTime
+		if err := rows.Scan(&session.ID, &session.Activity, &session.StartedAt, &endedAt); err != nil {
+			return nil, err
+		}
+		session.EndedAt = &endedAt.Time
+
+		date := session.StartedAt.In(time.Local).Format("2006-01-02")
+		if !r.Contains(date) {
+			continue
+		}
+		stats.Sessions++
+		total += session.Duration()
+		sessionDays[date] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats.SecondsWriting = int(total.Seconds())
+	if len(sessionDays) > 0 {
+		stats.AverageSecondsPerDay = float64(stats.SecondsWriting) / float64(len(sessionDays))
+	}
+
+	return stats, nil
+}
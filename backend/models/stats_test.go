@@ -0,0 +1,129 @@
+// backend/models/stats_test.go
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWritingStats(t *testing.T) {
+	t.Parallel()
+
+	stores, db := newTestStores(t)
+
+	question, err := stores.Questions.Add("What did I notice today?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	// Test that counts are stored when entries are written
+	t.Run("CountsOnWrite", func(t *testing.T) {
+		answer, err := stores.Answers.Create(question.ID, "Blossoms on the cherry tree")
+		if err != nil {
+			t.Fatalf("Failed to create answer: %v", err)
+		}
+
+		var words, chars int
+		db.QueryRow(`SELECT word_count, char_count FROM answers WHERE id = ?`, answer.ID).Scan(&words, &chars)
+		if words != 5 || chars != 27 {
+			t.Errorf("Expected 5 words and 27 characters, got %d and %d", words, chars)
+		}
+
+		if err := stores.Answers.Update(answer.ID, "Cherry blossoms"); err != nil {
+			t.Fatalf("Failed to update answer: %v", err)
+		}
+
+		db.QueryRow(`SELECT word_count FROM answers WHERE id = ?`, answer.ID).Scan(&words)
+		if words != 2 {
+			t.Errorf("Expected 2 words after update, got %d", words)
+		}
+	})
+
+	// Test the totals, longest entry and frequent words
+	t.Run("GetStats", func(t *testing.T) {
+		if _, err := stores.Creativity.Save("A poem about cherry blossoms and cherry jam in the spring", today); err != nil {
+			t.Fatalf("Failed to save creativity entry: %v", err)
+		}
+
+		old := ImportedEntry{Type: EntryCreativity, Content: "Old story", Date: "2001-02-03", CreatedAt: time.Date(2001, 2, 3, 12, 0, 0, 0, time.Local)}
+		if err := stores.Imports.Import([]ImportedEntry{old}); err != nil {
+			t.Fatalf("Failed to import creativity entry: %v", err)
+		}
+
+		stats, err := stores.Writing.GetStats(DateRange{From: today})
+		if err != nil {
+			t.Fatalf("Failed to get stats: %v", err)
+		}
+
+		if stats.Entries != 2 || stats.Words != 13 {
+			t.Errorf("Expected 2 entries with 13 words, got %d with %d", stats.Entries, stats.Words)
+		}
+
+		if stats.ActiveDays != 1 || stats.AverageWordsPerDay != 13 {
+			t.Errorf("Expected 1 active day averaging 13 words, got %d averaging %v", stats.ActiveDays, stats.AverageWordsPerDay)
+		}
+
+		if stats.Longest == nil || stats.Longest.Type != "creativity" || stats.Longest.Words != 11 {
+			t.Errorf("Expected the poem to be the longest entry, got %+v", stats.Longest)
+		}
+
+		if len(stats.TopWords) == 0 || stats.TopWords[0].Word != "cherry" || stats.TopWords[0].Count != 3 {
+			t.Errorf("Expected 'cherry' to be the most frequent word, got %v", stats.TopWords)
+		}
+
+		all, err := stores.Writing.GetStats(DateRange{})
+		if err != nil {
+			t.Fatalf("Failed to get stats: %v", err)
+		}
+
+		if all.Entries != 3 {
+			t.Errorf("Expected 3 entries in an open range, got %d", all.Entries)
+		}
+
+		if _, err := stores.Writing.GetStats(DateRange{From: "2024-02-01", To: "2024-01-01"}); err == nil {
+			t.Errorf("Expected error for a reversed range")
+		}
+	})
+
+	// Test that writing time comes from ended sessions
+	t.Run("Sessions", func(t *testing.T) {
+		session, err := stores.Writing.StartSession("answer")
+		if err != nil {
+			t.Fatalf("Failed to start session: %v", err)
+		}
+
+		// Backdate the start so the session has a measurable length
+		_, err = db.Exec(`UPDATE writing_sessions SET started_at = ? WHERE id = ?`, time.Now().Add(-90*time.Second), session.ID)
+		if err != nil {
+			t.Fatalf("Failed to backdate session: %v", err)
+		}
+
+		if _, err := stores.Writing.StartSession("creativity"); err != nil {
+			t.Fatalf("Failed to start session: %v", err)
+		}
+
+		ended, err := stores.Writing.EndSession(session.ID)
+		if err != nil {
+			t.Fatalf("Failed to end session: %v", err)
+		}
+
+		if ended.EndedAt == nil {
+			t.Fatalf("Expected session to have ended")
+		}
+
+		stats, err := stores.Writing.GetStats(DateRange{From: today})
+		if err != nil {
+			t.Fatalf("Failed to get stats: %v", err)
+		}
+
+		if stats.Sessions != 1 {
+			t.Errorf("Expected open sessions to be ignored, got %d sessions", stats.Sessions)
+		}
+
+		if stats.SecondsWriting < 89 || stats.SecondsWriting > 120 {
+			t.Errorf("Expected about 90 seconds of writing, got %d", stats.SecondsWriting)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// backend/models/store.go
+package models
+
+import (
+	"database/sql"
+
+	"myproject/backend/events"
+)
+
+// Stores groups the stores for every aggregate so they can be passed around together
+type Stores struct {
+	Questions    QuestionStore
+	Packs        QuestionPackStore
+	Answers      AnswerStore
+	Drafts       DraftStore
+	Affirmations AffirmationStore
+	Gratitude    GratitudeStore
+	Creativity   CreativityStore
+	Prompts      PromptStore
+	Settings     SettingsStore
+	Writing      WritingStore
+	Sentiment    SentimentStore
+	Habits       HabitStore
+	Goals        GoalStore
+	Tags         TagStore
+	Links        LinkStore
+	Routines     RoutineStore
+	Imports      ImportStore
+}
+
+// NewStores creates SQL-backed stores sharing db. Data changes are published on bus,
+// which may be nil.
+func NewStores(db *sql.DB, bus *events.Bus) *Stores {
+	return &Stores{
+		Questions:    NewQuestionStore(db, bus),
+		Packs:        NewQuestionPackStore(db, bus),
+		Answers:      NewAnswerStore(db, bus),
+		Drafts:       NewDraftStore(db, bus),
+		Affirmations: NewAffirmationStore(db, bus),
+		Gratitude:    NewGratitudeStore(db, bus),
+		Creativity:   NewCreativityStore(db, bus),
+		Prompts:      NewPromptStore(db, bus),
+		Settings:     NewSettingsStore(db),
+		Writing:      NewWritingStore(db),
+		Sentiment:    NewSentimentStore(db),
+		Habits:       NewHabitStore(db, bus),
+		Goals:        NewGoalStore(db, bus),
+		Tags:         NewTagStore(db, bus),
+		Links:        NewLinkStore(db),
+		Routines:     NewRoutineStore(db, bus),
+		Imports:      NewImportStore(db, bus),
+	}
+}
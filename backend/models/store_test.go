@@ -0,0 +1,85 @@
+// backend/models/store_test.go
+package models
+
+import (
+	"database/sql"
+	"testing"
+
+	"myproject/backend/database"
+	"myproject/backend/events"
+)
+
+// newTestStores opens an in-memory database with the latest schema and
+// returns stores for it. The database is closed when the test finishes.
+func newTestStores(t *testing.T) (*Stores, *sql.DB) {
+	t.Helper()
+
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewStores(db, events.NewBus()), db
+}
+
+func TestRecordUUIDs(t *testing.T) {
+	t.Parallel()
+	stores, _ := newTestStores(t)
+
+	question, err := stores.Questions.Add("What made you laugh?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+	answer, err := stores.Answers.Create(question.ID, "A cat video")
+	if err != nil {
+		t.Fatalf("Failed to create answer: %v", err)
+	}
+	affirmation, err := stores.Affirmations.Save("I am curious")
+	if err != nil {
+		t.Fatalf("Failed to save affirmation: %v", err)
+	}
+	item, err := stores.Gratitude.Add("Warm socks")
+	if err != nil {
+		t.Fatalf("Failed to add gratitude item: %v", err)
+	}
+	entry, err := stores.Creativity.Save("A short poem", "2024-03-01")
+	if err != nil {
+		t.Fatalf("Failed to save creativity entry: %v", err)
+	}
+
+	created := []string{question.UUID, answer.UUID, affirmation.UUID, item.UUID, entry.UUID}
+
+	// Test that created records carry distinct UUIDs that sort by creation time
+	for i, id := range created {
+		if len(id) != 36 {
+			t.Fatalf("Expected a UUID, got %q", id)
+		}
+		if i > 0 && id <= created[i-1] {
+			t.Errorf("Expected %s to sort after %s", id, created[i-1])
+		}
+	}
+
+	// Test that reading records returns the same UUIDs
+	storedQuestion, _ := stores.Questions.GetByID(question.ID)
+	answers, _ := stores.Answers.GetAll()
+	storedAffirmation, _ := stores.Affirmations.GetActive()
+	items, _ := stores.Gratitude.GetToday()
+	storedEntries, _ := stores.Creativity.GetByDate("2024-03-01")
+
+	read := []string{storedQuestion.UUID, answers[0].UUID, storedAffirmation.UUID, items[0].UUID, storedEntries[0].UUID}
+	for i := range created {
+		if read[i] != created[i] {
+			t.Errorf("Expected stored UUID %s, got %s", created[i], read[i])
+		}
+	}
+
+	// Test that updating keeps the UUID
+	updated, err := stores.Creativity.Save("A longer poem", "2024-03-01")
+	if err != nil {
+		t.Fatalf("Failed to update creativity entry: %v", err)
+	}
+	if updated.UUID != entry.UUID {
+		t.Errorf("Expected UUID %s kept on update, got %s", entry.UUID, updated.UUID)
+	}
+}
@@ -0,0 +1,268 @@
+// backend/models/tag.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"myproject/backend/events"
+	"myproject/backend/textstats"
+)
+
+// Tag is a #hashtag and how many entries use it
+type Tag struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"` // Lower case, without the #
+	Entries int    `json:"entries"`
+}
+
+// TagStore finds the entries that use each #hashtag. Tags are read from
+// entries' content when they are saved, so renaming a tag rewrites the
+// entries that use it.
+type TagStore interface {
+	GetAll() ([]Tag, error)
+	GetCloud(r DateRange) ([]Tag, error)
+	GetEntries(tag string) ([]EntrySummary, error)
+	GetForEntry(entryType string, id int64) ([]string, error)
+	Rename(from string, to string) (int, error)
+	Merge(tags []string, into string) (int, error)
+	Reindex() error
+}
+
+type sqlTagStore struct {
+	db  *sql.DB
+	bus *events.Bus
+}
+
+// NewTagStore creates a TagStore backed by db
+func NewTagStore(db *sql.DB, bus *events.Bus) TagStore {
+	return &sqlTagStore{db: db, bus: bus}
+}
+
+// tagEntry replaces an entry's tags with the hashtags in its content
+func tagEntry(tx *sql.Tx, e EntrySummary) error {
+	_, err := tx.Exec(`DELETE FROM entry_tags WHERE entity_type = ? AND entity_id = ?`, e.Type, e.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range textstats.Hashtags(e.Content) {
+		_, err := tx.Exec(`INSERT INTO tags (name, created_at) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`, name, time.Now())
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO entry_tags (tag_id, entity_type, entity_id, entry_date)
+			SELECT id, ?, ?, ? FROM tags WHERE name = ?`, e.Type, e.ID, e.Date, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneTags deletes the tags no entry uses any more
+func pruneTags(tx *sql.Tx) error {
+	_, err := tx.Exec(`DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM entry_tags)`)
+	return err
+}
+
+// Reindex reads the hashtags in every entry again, for entries changed
+// without going through the stores, such as by sync
+func (s *sqlTagStore) Reindex() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM entry_tags`); err != nil {
+		return err
+	}
+	for _, entryType := range entryTypes {
+		entries, err := loadEntries(tx, entryType, "")
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := tagEntry(tx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if err := pruneTags(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetAll returns the tags in use by name
+func (s *sqlTagStore) GetAll() ([]Tag, error) {
+	return s.tags(DateRange{}, `t.name`)
+}
+
+// GetCloud returns the tags used by entries in a date range, most used first
+func (s *sqlTagStore) GetCloud(r DateRange) ([]Tag, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return s.tags(r, `COUNT(*) DESC, t.name`)
+}
+
+func (s *sqlTagStore) tags(r DateRange, order string) ([]Tag, error) {
+	rows, err := s.db.Query(`
+		SELECT t.id, t.name, COUNT(*)
+		FROM tags t
+		JOIN entry_tags e ON e.tag_id = t.id
+		WHERE (? = '' OR e.entry_date >= ?) AND (? = '' OR e.entry_date <= ?)
+		GROUP BY t.id
+		ORDER BY `+order, r.From, r.From, r.To, r.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Tag{}
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Entries); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// GetEntries returns the entries that use a tag, newest first
+func (s *sqlTagStore) GetEntries(tag string) ([]EntrySummary, error) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+
+	list := []EntrySummary{}
+	for _, entryType := range entryTypes {
+		entries, err := taggedEntries(s.db, entryType, tag)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, entries...)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Date != list[j].Date {
+			return list[i].Date > list[j].Date
+		}
+		return list[i].At.After(list[j].At)
+	})
+	return list, nil
+}
+
+// taggedEntries loads the entries of a type that use a tag
+func taggedEntries(q querier, entryType string, tag string) ([]EntrySummary, error) {
+	return loadEntries(q, entryType, entryColumn(entryType, "id")+` IN (
+		SELECT e.entity_id FROM entry_tags e JOIN tags t ON t.id = e.tag_id
+		WHERE e.entity_type = ? AND t.name = ?)`, entryType, tag)
+}
+
+// GetForEntry returns the tags an entry uses, by name
+func (s *sqlTagStore) GetForEntry(entryType string, id int64) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT t.name
+		FROM entry_tags e
+		JOIN tags t ON t.id = e.tag_id
+		WHERE e.entity_type = ? AND e.entity_id = ?
+		ORDER BY t.name`, entryType, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// Rename renames a tag by rewriting the hashtag in every entry that uses
+// it. Renaming to a tag that is already used merges the two. It returns
+// how many entries were changed; a tag no entry uses changes none.
+func (s *sqlTagStore) Rename(from string, to string) (int, error) {
+	return s.Merge([]string{from}, to)
+}
+
+// Merge renames several tags to one in a single transaction, returning how
+// many entries were changed
+func (s *sqlTagStore) Merge(tags []string, into string) (int, error) {
+	if len(tags) == 0 {
+		return 0, errors.New("no tags to merge")
+	}
+	into = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(into), "#"))
+	if !textstats.IsHashtag(into) {
+		return 0, fmt.Errorf("%q can't be used as a tag; tags are a letter followed by letters, digits, - or _", into)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	changed := 0
+	for _, tag := range tags {
+		n, err := renameTag(tx, tag, into)
+		if err != nil {
+			return 0, err
+		}
+		changed += n
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	if err := pruneTags(tx); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	s.bus.Publish(events.Event{Type: events.TagsChanged, Key: into})
+	return changed, nil
+}
+
+// renameTag rewrites the hashtag from as to in the entries using it within
+// tx, returning how many there were
+func renameTag(tx *sql.Tx, from string, to string) (int, error) {
+	from = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(from), "#"))
+	if from == to {
+		return 0, nil
+	}
+
+	var entries []EntrySummary
+	for _, entryType := range entryTypes {
+		found, err := taggedEntries(tx, entryType, from)
+		if err != nil {
+			return 0, err
+		}
+		entries = append(entries, found...)
+	}
+
+	for _, e := range entries {
+		err := rewriteContent(tx, &e, func(text string) string {
+			return textstats.ReplaceHashtag(text, from, to)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := tagEntry(tx, e); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
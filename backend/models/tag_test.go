@@ -0,0 +1,140 @@
+// backend/models/tag_test.go
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagModel(t *testing.T) {
+	t.Parallel()
+	stores, db := newTestStores(t)
+
+	question, err := stores.Questions.Add("What went well today?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+	answer, err := stores.Answers.Create(question.ID, "Shipped the release #work #Focus")
+	if err != nil {
+		t.Fatalf("Failed to create answer: %v", err)
+	}
+	item, err := stores.Gratitude.Add("Dinner with my sister #family")
+	if err != nil {
+		t.Fatalf("Failed to add gratitude item: %v", err)
+	}
+	entry, err := stores.Creativity.Create(CreativityEntry{Title: "Commute", Content: "Trains hum #work", EntryDate: "2020-03-01"})
+	if err != nil {
+		t.Fatalf("Failed to create creativity entry: %v", err)
+	}
+	affirmation, err := stores.Affirmations.Save("I make time for my #kin")
+	if err != nil {
+		t.Fatalf("Failed to save affirmation: %v", err)
+	}
+
+	// Test that hashtags are read from every kind of entry when saved
+	t.Run("Index", func(t *testing.T) {
+		tags, err := stores.Tags.GetAll()
+		if err != nil {
+			t.Fatalf("Failed to get tags: %v", err)
+		}
+		names := map[string]int{}
+		for _, tag := range tags {
+			names[tag.Name] = tag.Entries
+		}
+		if len(tags) != 4 || names["work"] != 2 || names["focus"] != 1 {
+			t.Errorf("Expected work twice and focus, family and kin once, got %+v", tags)
+		}
+
+		entries, err := stores.Tags.GetEntries("#Work")
+		if err != nil {
+			t.Fatalf("Failed to get entries by tag: %v", err)
+		}
+		if len(entries) != 2 || entries[0].Type != EntryAnswer || entries[0].Title != question.Content || entries[1].ID != entry.ID {
+			t.Errorf("Expected the answer then the older creativity entry, got %+v", entries)
+		}
+
+		// Editing an entry updates its tags
+		if err := stores.Answers.Update(answer.ID, "Shipped the release #work"); err != nil {
+			t.Fatalf("Failed to update answer: %v", err)
+		}
+		names2, _ := stores.Tags.GetForEntry(EntryAnswer, answer.ID)
+		if len(names2) != 1 || names2[0] != "work" {
+			t.Errorf("Expected only #work left on the answer, got %v", names2)
+		}
+	})
+
+	// Test that the cloud only counts entries in the range
+	t.Run("Cloud", func(t *testing.T) {
+		today := time.Now().Format("2006-01-02")
+		cloud, err := stores.Tags.GetCloud(DateRange{From: today, To: today})
+		if err != nil {
+			t.Fatalf("Failed to get tag cloud: %v", err)
+		}
+		if len(cloud) != 3 || cloud[0].Name != "family" || cloud[0].Entries != 1 {
+			t.Errorf("Expected family, kin and work once each, got %+v", cloud)
+		}
+
+		if _, err := stores.Tags.GetCloud(DateRange{From: "March"}); err == nil {
+			t.Error("Expected an invalid range to be rejected")
+		}
+	})
+
+	// Test that renaming and merging rewrite the entries using a tag
+	t.Run("RenameAndMerge", func(t *testing.T) {
+		if _, err := stores.Tags.Rename("work", "two words"); err == nil {
+			t.Error("Expected an invalid tag name to be rejected")
+		}
+
+		changed, err := stores.Tags.Rename("work", "job")
+		if err != nil {
+			t.Fatalf("Failed to rename tag: %v", err)
+		}
+		stored, _ := stores.Creativity.GetByID(entry.ID)
+		if changed != 2 || stored.Content != "Trains hum #job" {
+			t.Errorf("Expected 2 entries rewritten, got %d and %q", changed, stored.Content)
+		}
+
+		// Tags no entry uses leave nothing to change
+		changed, err = stores.Tags.Rename("holiday", "trip")
+		if err != nil || changed != 0 {
+			t.Errorf("Expected renaming an unused tag to change nothing, got %d and %v", changed, err)
+		}
+
+		changed, err = stores.Tags.Merge([]string{"kin", "holiday", "family"}, "family")
+		if err != nil {
+			t.Fatalf("Failed to merge tags: %v", err)
+		}
+		entries, _ := stores.Tags.GetEntries("family")
+		if changed != 1 || len(entries) != 2 {
+			t.Errorf("Expected the affirmation merged into family, got %d changed and %d entries", changed, len(entries))
+		}
+
+		tags, _ := stores.Tags.GetAll()
+		if len(tags) != 2 || tags[0].Name != "family" || tags[1].Name != "job" {
+			t.Errorf("Expected only family and job left, got %+v", tags)
+		}
+	})
+
+	// Test that deleted entries lose their tags and entries changed
+	// directly are picked up by reindexing
+	t.Run("DeleteAndReindex", func(t *testing.T) {
+		if err := stores.Gratitude.Delete(item.ID); err != nil {
+			t.Fatalf("Failed to delete gratitude item: %v", err)
+		}
+		entries, _ := stores.Tags.GetEntries("family")
+		if len(entries) != 1 || entries[0].ID != affirmation.ID {
+			t.Errorf("Expected only the affirmation left, got %+v", entries)
+		}
+
+		if _, err := db.Exec(`UPDATE affirmations SET content = 'Rest is work too #rest' WHERE id = ?`, affirmation.ID); err != nil {
+			t.Fatalf("Failed to update affirmation: %v", err)
+		}
+		if err := stores.Tags.Reindex(); err != nil {
+			t.Fatalf("Failed to reindex tags: %v", err)
+		}
+		tags, _ := stores.Tags.GetAll()
+		if len(tags) != 2 || tags[1].Name != "rest" {
+			t.Errorf("Expected job and rest, got %+v", tags)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+// backend/models/template.go
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Types of template field
+const (
+	FieldText  = "text"
+	FieldList  = "list"
+	FieldScale = "scale" // A whole number from ScaleMin to ScaleMax
+	FieldYesNo = "yesno"
+)
+
+// The range of scale fields
+const (
+	ScaleMin = 1
+	ScaleMax = 10
+)
+
+// Template gives a question's answers a structure, such as "3 wins, 1
+// lesson, 1 intention"
+type Template struct {
+	Fields []TemplateField `json:"fields"`
+}
+
+// TemplateField is one field of a template
+type TemplateField struct {
+	Key      string `json:"key"` // Identifies the field's value, so labels can be reworded
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Count    int    `json:"count,omitempty"` // The most items a list can have, or 0 for any number
+}
+
+// FieldValue is an answer's value for a template field. Only the member
+// for the field's type is set.
+type FieldValue struct {
+	Key   string   `json:"key"`
+	Text  string   `json:"text,omitempty"`
+	Items []string `json:"items,omitempty"`
+	Scale int      `json:"scale,omitempty"`
+	Yes   *bool    `json:"yes,omitempty"`
+}
+
+// Validate checks a template, filling in keys left empty from the labels
+func (t *Template) Validate() error {
+	if len(t.Fields) == 0 {
+		return errors.New("a template needs at least one field")
+	}
+
+	keys := map[string]bool{}
+	for i := range t.Fields {
+		f := &t.Fields[i]
+		f.Label = strings.TrimSpace(f.Label)
+		if f.Label == "" {
+			return fmt.Errorf("field %d needs a label", i+1)
+		}
+		if f.Key = strings.TrimSpace(f.Key); f.Key == "" {
+			f.Key = fieldKey(f.Label, i)
+		}
+		if keys[f.Key] {
+			return fmt.Errorf("two fields are both called %q", f.Key)
+		}
+		keys[f.Key] = true
+
+		switch f.Type {
+		case FieldText, FieldScale, FieldYesNo:
+			f.Count = 0
+		case FieldList:
+			if f.Count < 0 {
+				return fmt.Errorf("%s can't have a negative number of items", f.Label)
+			}
+		default:
+			return fmt.Errorf("%s has unknown field type %q", f.Label, f.Type)
+		}
+	}
+	return nil
+}
+
+// fieldKey makes a key from the label of field i, such as "wins" from
+// "3 Wins". Labels without letters or digits give keys like "field-2".
+func fieldKey(label string, i int) string {
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && strings.TrimFunc(words[0], unicode.IsDigit) == "" {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return fmt.Sprintf("field-%d", i+1)
+	}
+	return strings.Join(words, "_")
+}
+
+// Check validates values against the template, returning them trimmed
+// and in the template's order. Fields left empty are dropped.
+func (t Template) Check(values []FieldValue) ([]FieldValue, error) {
+	byKey := map[string]FieldValue{}
+	for _, v := range values {
+		if _, dup := byKey[v.Key]; dup {
+			return nil, fmt.Errorf("%q is given more than once", v.Key)
+		}
+		byKey[v.Key] = v
+	}
+
+	checked := []FieldValue{}
+	for _, f := range t.Fields {
+		v, ok := byKey[f.Key]
+		delete(byKey, f.Key)
+		if (f.Type != FieldText && v.Text != "") || (f.Type != FieldList && len(v.Items) > 0) ||
+			(f.Type != FieldScale && v.Scale != 0) || (f.Type != FieldYesNo && v.Yes != nil) {
+			return nil, fmt.Errorf("%s is a %s field", f.Label, f.Type)
+		}
+		value := FieldValue{Key: f.Key}
+
+		switch f.Type {
+		case FieldText:
+			value.Text = strings.TrimSpace(v.Text)
+			ok = ok && value.Text != ""
+		case FieldList:
+			for _, item := range v.Items {
+				if item = strings.TrimSpace(item); item != "" {
+					value.Items = append(value.Items, item)
+				}
+			}
+			if f.Count > 0 && len(value.Items) > f.Count {
+				return nil, fmt.Errorf("%s can have at most %d items", f.Label, f.Count)
+			}
+			ok = ok && len(value.Items) > 0
+		case FieldScale:
+			if ok && v.Scale != 0 && (v.Scale < ScaleMin || v.Scale > ScaleMax) {
+				return nil, fmt.Errorf("%s must be from %d to %d", f.Label, ScaleMin, ScaleMax)
+			}
+			value.Scale = v.Scale
+			ok = ok && v.Scale != 0
+		case FieldYesNo:
+			value.Yes = v.Yes
+			ok = ok && v.Yes != nil
+		}
+
+		if !ok {
+			if f.Required {
+				return nil, fmt.Errorf("%s is required", f.Label)
+			}
+			continue
+		}
+		checked = append(checked, value)
+	}
+
+	for key := range byKey {
+		return nil, fmt.Errorf("the question has no field %q", key)
+	}
+	return checked, nil
+}
+
+// Render writes checked values as Markdown, which is kept as the answer's
+// content for searching, stats and clients that don't know templates
+func (t Template) Render(values []FieldValue) string {
+	byKey := map[string]FieldValue{}
+	for _, v := range values {
+		byKey[v.Key] = v
+	}
+
+	var parts []string
+	for _, f := range t.Fields {
+		v, ok := byKey[f.Key]
+		if !ok {
+			continue
+		}
+		switch f.Type {
+		case FieldText:
+			parts = append(parts, "**"+f.Label+"**\n"+v.Text)
+		case FieldList:
+			parts = append(parts, "**"+f.Label+"**\n- "+strings.Join(v.Items, "\n- "))
+		case FieldScale:
+			parts = append(parts, fmt.Sprintf("**%s:** %d/%d", f.Label, v.Scale, ScaleMax))
+		case FieldYesNo:
+			answer := "No"
+			if *v.Yes {
+				answer = "Yes"
+			}
+			parts = append(parts, "**"+f.Label+":** "+answer)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// decodeTemplate reads a stored template, which is empty for questions
+// without one
+func decodeTemplate(data string) (*Template, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var t Template
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// decodeFields reads an answer's stored field values
+func decodeFields(data string) ([]FieldValue, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var values []FieldValue
+	err := json.Unmarshal([]byte(data), &values)
+	return values, err
+}
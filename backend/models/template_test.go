@@ -0,0 +1,187 @@
+// backend/models/template_test.go
+package models
+
+import (
+	"testing"
+)
+
+func TestTemplateModel(t *testing.T) {
+	t.Parallel()
+	stores, _ := newTestStores(t)
+
+	yes := true
+	template := Template{Fields: []TemplateField{
+		{Label: "3 Wins", Type: FieldList, Required: true, Count: 3},
+		{Label: "Lesson", Type: FieldText},
+		{Key: "energy", Label: "Energy level", Type: FieldScale},
+		{Label: "Exercised?", Type: FieldYesNo},
+	}}
+
+	question, err := stores.Questions.Add("How did the day go?")
+	if err != nil {
+		t.Fatalf("Failed to add question: %v", err)
+	}
+
+	// Test that templates are checked and stored with the question
+	t.Run("SetTemplate", func(t *testing.T) {
+		bad := Template{Fields: []TemplateField{{Label: "Mood", Type: "colour"}}}
+		if err := stores.Questions.SetTemplate(question.ID, &bad); err == nil {
+			t.Error("Expected an unknown field type to be rejected")
+		}
+		bad = Template{Fields: []TemplateField{{Label: "Win", Type: FieldText}, {Label: "win", Type: FieldText}}}
+		if err := stores.Questions.SetTemplate(question.ID, &bad); err == nil {
+			t.Error("Expected two fields with the same key to be rejected")
+		}
+
+		if err := stores.Questions.SetTemplate(question.ID, &template); err != nil {
+			t.Fatalf("Failed to set template: %v", err)
+		}
+		stored, err := stores.Questions.GetByID(question.ID)
+		if err != nil {
+			t.Fatalf("Failed to get question: %v", err)
+		}
+		if stored.Template == nil || len(stored.Template.Fields) != 4 || stored.Template.Fields[0].Key != "wins" {
+			t.Errorf("Expected the template stored with keys from the labels, got %+v", stored.Template)
+		}
+
+		// Labels in other scripts keep their letters, and labels without
+		// any get a key from their position
+		other := Template{Fields: []TemplateField{
+			{Label: "Благодарность", Type: FieldText},
+			{Label: "今日の学び", Type: FieldText},
+			{Label: "✨", Type: FieldText},
+			{Label: "?!", Type: FieldText},
+		}}
+		if err := other.Validate(); err != nil {
+			t.Fatalf("Failed to validate template: %v", err)
+		}
+		for i, want := range []string{"благодарность", "今日の学び", "field-3", "field-4"} {
+			if other.Fields[i].Key != want {
+				t.Errorf("Expected key %q for %q, got %q", want, other.Fields[i].Label, other.Fields[i].Key)
+			}
+		}
+	})
+
+	// Test that values are validated against the template
+	t.Run("Check", func(t *testing.T) {
+		for name, fields := range map[string][]FieldValue{
+			"missing required": {{Key: "lesson", Text: "Rest"}},
+			"too many items":   {{Key: "wins", Items: []string{"a", "b", "c", "d"}}},
+			"scale too high":   {{Key: "wins", Items: []string{"a"}}, {Key: "energy", Scale: 11}},
+			"wrong type":       {{Key: "wins", Items: []string{"a"}}, {Key: "lesson", Scale: 3}},
+			"unknown field":    {{Key: "wins", Items: []string{"a"}}, {Key: "mood", Text: "ok"}},
+		} {
+			if _, err := stores.Answers.CreateStructured(question.ID, fields); err == nil {
+				t.Errorf("Expected %s to be rejected", name)
+			}
+		}
+
+		plain, _ := stores.Questions.Add("Anything else?")
+		if _, err := stores.Answers.CreateStructured(plain.ID, []FieldValue{{Key: "wins", Items: []string{"a"}}}); err == nil {
+			t.Error("Expected a question without a template to be rejected")
+		}
+	})
+
+	// Test that answers keep their values and render them as content
+	t.Run("Answer", func(t *testing.T) {
+		answer, err := stores.Answers.CreateStructured(question.ID, []FieldValue{
+			{Key: "exercised", Yes: &yes},
+			{Key: "wins", Items: []string{"Shipped it ", "", "Called Mum"}},
+			{Key: "energy", Scale: 7},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create structured answer: %v", err)
+		}
+		want := "**3 Wins**\n- Shipped it\n- Called Mum\n\n**Energy level:** 7/10\n\n**Exercised?:** Yes"
+		if answer.Content != want {
+			t.Errorf("Expected content %q, got %q", want, answer.Content)
+		}
+
+		history, err := stores.Answers.GetHistoryByQuestionID(question.ID)
+		if err != nil {
+			t.Fatalf("Failed to get answers: %v", err)
+		}
+		if len(history) != 1 || len(history[0].Fields) != 3 || history[0].Fields[0].Key != "wins" || len(history[0].Fields[0].Items) != 2 {
+			t.Errorf("Expected the values stored in the template's order, got %+v", history)
+		}
+
+		if err := stores.Answers.UpdateStructured(answer.ID, []FieldValue{{Key: "wins", Items: []string{"Slept well"}}, {Key: "lesson", Text: "Go slow"}}); err != nil {
+			t.Fatalf("Failed to update structured answer: %v", err)
+		}
+		history, _ = stores.Answers.GetHistoryByQuestionID(question.ID)
+		if history[0].Content != "**3 Wins**\n- Slept well\n\n**Lesson**\nGo slow" || len(history[0].Fields) != 2 {
+			t.Errorf("Expected the answer updated, got %+v", history[0])
+		}
+
+		// Editing the content directly drops the values, which no longer match
+		if err := stores.Answers.Update(answer.ID, "Just a good day"); err != nil {
+			t.Fatalf("Failed to update answer: %v", err)
+		}
+		history, _ = stores.Answers.GetHistoryByQuestionID(question.ID)
+		if history[0].Fields != nil {
+			t.Errorf("Expected the values dropped, got %+v", history[0].Fields)
+		}
+	})
+
+	// Test that renaming a tag reaches the values, not just the content
+	t.Run("RenameTag", func(t *testing.T) {
+		answer, err := stores.Answers.CreateStructured(question.ID, []FieldValue{
+			{Key: "wins", Items: []string{"Long walk #outside"}},
+			{Key: "lesson", Text: "Take breaks #rest"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create structured answer: %v", err)
+		}
+		if _, err := stores.Tags.Rename("rest", "recovery"); err != nil {
+			t.Fatalf("Failed to rename tag: %v", err)
+		}
+
+		history, _ := stores.Answers.GetHistoryByQuestionID(question.ID)
+		var fields []FieldValue
+		for _, h := range history {
+			if h.ID == answer.ID {
+				fields = h.Fields
+			}
+		}
+		if len(fields) != 2 || fields[1].Text != "Take breaks #recovery" || fields[0].Items[0] != "Long walk #outside" {
+			t.Fatalf("Expected the tag renamed in the values, got %+v", fields)
+		}
+
+		// Changing the values later keeps the new name
+		if err := stores.Answers.UpdateStructured(answer.ID, append(fields, FieldValue{Key: "energy", Scale: 5})); err != nil {
+			t.Fatalf("Failed to update structured answer: %v", err)
+		}
+		if tagged, _ := stores.Tags.GetEntries("recovery"); len(tagged) != 1 || tagged[0].ID != answer.ID {
+			t.Errorf("Expected the answer still tagged after the update, got %+v", tagged)
+		}
+	})
+
+	// Test that structured drafts are checked against the template when committed
+	t.Run("Draft", func(t *testing.T) {
+		draft, err := stores.Drafts.SaveStructured(question.ID, []FieldValue{{Key: "lesson", Text: "Halfway there"}})
+		if err != nil {
+			t.Fatalf("Failed to save draft: %v", err)
+		}
+		if len(draft.Fields) != 1 {
+			t.Errorf("Expected the draft to keep its values, got %+v", draft)
+		}
+		if _, err := stores.Drafts.Commit(draft.ID); err == nil {
+			t.Error("Expected a draft missing a required field to be rejected")
+		}
+		if _, err := stores.Drafts.GetByID(draft.ID); err != nil {
+			t.Errorf("Expected the rejected draft kept, got %v", err)
+		}
+
+		draft, err = stores.Drafts.SaveStructured(question.ID, []FieldValue{{Key: "wins", Items: []string{"Finished the draft"}}})
+		if err != nil {
+			t.Fatalf("Failed to save draft: %v", err)
+		}
+		answer, err := stores.Drafts.Commit(draft.ID)
+		if err != nil {
+			t.Fatalf("Failed to commit draft: %v", err)
+		}
+		if len(answer.Fields) != 1 || answer.Content != "**3 Wins**\n- Finished the draft" {
+			t.Errorf("Expected the answer built from the values, got %+v", answer)
+		}
+	})
+}
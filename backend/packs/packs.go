@@ -0,0 +1,194 @@
+// backend/packs/packs.go
+package packs
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"myproject/backend/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatVersion is the pack file format this build reads and writes
+const FormatVersion = 1
+
+// Pack is a shareable set of questions with metadata
+type Pack struct {
+	Format      int      `json:"format" yaml:"format"`
+	ID          string   `json:"id" yaml:"id"`
+	Name        string   `json:"name" yaml:"name"`
+	Version     string   `json:"version" yaml:"version"`
+	Author      string   `json:"author" yaml:"author"`
+	Language    string   `json:"language" yaml:"language"`
+	Tags        []string `json:"tags" yaml:"tags"`
+	Description string   `json:"description" yaml:"description"`
+	Questions   []string `json:"questions" yaml:"questions"`
+}
+
+//go:embed builtin/*.json
+var builtinFiles embed.FS
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Validate checks the pack's format version, ID and questions
+func (p *Pack) Validate() error {
+	if p.Format == 0 {
+		p.Format = FormatVersion
+	}
+	if p.Format > FormatVersion {
+		return fmt.Errorf("question pack format %d is newer than this app supports (%d)", p.Format, FormatVersion)
+	}
+	if !validID.MatchString(p.ID) {
+		return fmt.Errorf("question pack id %q must be lowercase letters, digits, '.', '_' or '-'", p.ID)
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("question pack %q is missing a name", p.ID)
+	}
+
+	questions := make([]string, 0, len(p.Questions))
+	for _, q := range p.Questions {
+		if q = strings.TrimSpace(q); q != "" {
+			questions = append(questions, q)
+		}
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("question pack %q has no questions", p.ID)
+	}
+	p.Questions = questions
+	return nil
+}
+
+// isYAML reports whether a file name has a YAML extension
+func isYAML(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// Parse decodes and validates a pack. name is the file name the data was
+// read from, whose extension selects YAML or JSON.
+func Parse(name string, data []byte) (*Pack, error) {
+	var p Pack
+	var err error
+	if isYAML(name) {
+		err = yaml.Unmarshal(data, &p)
+	} else {
+		err = json.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading question pack %s: %w", filepath.Base(name), err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Load reads a pack from a JSON or YAML file
+func Load(path string) (*Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// Write saves a pack to path as YAML or JSON, depending on the extension
+func Write(path string, p *Pack) error {
+	var data []byte
+	var err error
+	if isYAML(path) {
+		data, err = yaml.Marshal(p)
+	} else {
+		data, err = json.MarshalIndent(p, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Builtin returns the packs embedded in the app, sorted by ID
+func Builtin() ([]Pack, error) {
+	entries, err := builtinFiles.ReadDir("builtin")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Pack
+	for _, entry := range entries {
+		name := path.Join("builtin", entry.Name())
+		data, err := builtinFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		p, err := Parse(name, data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *p)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// BuiltinPack returns the embedded pack with the given ID
+func BuiltinPack(id string) (*Pack, error) {
+	builtin, err := Builtin()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range builtin {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown built-in question pack %q", id)
+}
+
+// ToModel converts a pack's metadata to an installed pack record
+func ToModel(p *Pack) models.QuestionPack {
+	return models.QuestionPack{
+		ID:          p.ID,
+		Name:        p.Name,
+		Version:     p.Version,
+		Author:      p.Author,
+		Language:    p.Language,
+		Tags:        p.Tags,
+		Description: p.Description,
+	}
+}
+
+// FromQuestions builds a pack file from installed metadata and questions
+func FromQuestions(meta models.QuestionPack, questions []models.Question) *Pack {
+	p := &Pack{
+		Format:      FormatVersion,
+		ID:          meta.ID,
+		Name:        meta.Name,
+		Version:     meta.Version,
+		Author:      meta.Author,
+		Language:    meta.Language,
+		Tags:        meta.Tags,
+		Description: meta.Description,
+		Questions:   make([]string, 0, len(questions)),
+	}
+	for _, q := range questions {
+		p.Questions = append(p.Questions, q.Content)
+	}
+	return p
+}
@@ -0,0 +1,105 @@
+// backend/packs/packs_test.go
+package packs
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPacks(t *testing.T) {
+	// Test that the embedded packs load
+	t.Run("Builtin", func(t *testing.T) {
+		builtin, err := Builtin()
+		if err != nil {
+			t.Fatalf("Failed to load built-in packs: %v", err)
+		}
+
+		if len(builtin) < 2 {
+			t.Fatalf("Expected at least 2 built-in packs, got %d", len(builtin))
+		}
+
+		pack, err := BuiltinPack("default")
+		if err != nil {
+			t.Fatalf("Failed to get default pack: %v", err)
+		}
+
+		if len(pack.Questions) != 40 {
+			t.Errorf("Expected 40 default questions, got %d", len(pack.Questions))
+		}
+
+		if _, err := BuiltinPack("missing"); err == nil {
+			t.Errorf("Expected error for unknown pack")
+		}
+	})
+
+	// Test reading YAML packs
+	t.Run("ParseYAML", func(t *testing.T) {
+		pack, err := Parse("pack.yaml", []byte(`
+id: mindful
+name: Mindful Moments
+version: "1.2"
+author: Someone
+language: en
+tags: [mindfulness]
+questions:
+  - What did I notice today?
+  - "  "
+  - Where did I feel calm?
+`))
+		if err != nil {
+			t.Fatalf("Failed to parse pack: %v", err)
+		}
+
+		if pack.Format != FormatVersion {
+			t.Errorf("Expected format %d, got %d", FormatVersion, pack.Format)
+		}
+
+		if len(pack.Questions) != 2 {
+			t.Errorf("Expected blank questions to be dropped, got %v", pack.Questions)
+		}
+	})
+
+	// Test validation errors
+	t.Run("Validate", func(t *testing.T) {
+		invalid := map[string]string{
+			"bad id":       `{"id": "Bad ID", "name": "x", "questions": ["q"]}`,
+			"no name":      `{"id": "x", "questions": ["q"]}`,
+			"no questions": `{"id": "x", "name": "x", "questions": []}`,
+			"newer format": `{"format": 99, "id": "x", "name": "x", "questions": ["q"]}`,
+		}
+		for name, data := range invalid {
+			if _, err := Parse("pack.json", []byte(data)); err == nil {
+				t.Errorf("Expected error for %s", name)
+			}
+		}
+	})
+
+	// Test that written packs read back the same in both formats
+	t.Run("WriteRoundTrip", func(t *testing.T) {
+		pack := &Pack{
+			Format:    FormatVersion,
+			ID:        "roundtrip",
+			Name:      "Round trip",
+			Version:   "1.0.0",
+			Tags:      []string{"a", "b"},
+			Questions: []string{"First?", "Second: with a colon?"},
+		}
+
+		for _, name := range []string{"pack.json", "pack.yml"} {
+			path := filepath.Join(t.TempDir(), name)
+			if err := Write(path, pack); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Failed to load %s: %v", name, err)
+			}
+
+			if !reflect.DeepEqual(loaded, pack) {
+				t.Errorf("%s: expected %+v, got %+v", name, pack, loaded)
+			}
+		}
+	})
+}
@@ -0,0 +1,330 @@
+// backend/profiles/profiles.go
+package profiles
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"myproject/backend/attachments"
+	"myproject/backend/database"
+	"myproject/backend/models"
+)
+
+// IndexFile is the name of the profile index inside the data directory
+const IndexFile = "profiles.json"
+
+// DefaultID identifies the profile backed by the original database file
+const DefaultID = "default"
+
+// Profile is a journal with its own database file
+type Profile struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	File      string    `json:"file"` // Database file, relative to the data directory
+	SeedSet   string    `json:"seedSet"`
+	Seeded    bool      `json:"seeded"` // Whether the seed questions have been added
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// index is the on-disk list of profiles
+type index struct {
+	Active   string    `json:"active"`
+	Profiles []Profile `json:"profiles"`
+}
+
+// Manager keeps track of the profiles in a data directory
+type Manager struct {
+	mu    sync.Mutex
+	dir   string
+	index index
+}
+
+// Open loads the profile index in dir. When there is none, one is created
+// holding a default profile for defaultFile, the database used before
+// profiles existed.
+func Open(dir string, defaultFile string) (*Manager, error) {
+	m := &Manager{dir: dir}
+
+	data, err := os.ReadFile(m.indexPath())
+	if err == nil {
+		if err := json.Unmarshal(data, &m.index); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", IndexFile, err)
+		}
+		if _, ok := m.find(m.index.Active); !ok {
+			return nil, fmt.Errorf("active profile %q is missing from %s", m.index.Active, IndexFile)
+		}
+		return m, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	def := Profile{
+		ID:        DefaultID,
+		Name:      "Default",
+		File:      defaultFile,
+		SeedSet:   SeedDefault,
+		CreatedAt: time.Now(),
+	}
+	m.index = index{Active: def.ID, Profiles: []Profile{def}}
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// List returns every profile in creation order
+func (m *Manager) List() []Profile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Profile(nil), m.index.Profiles...)
+}
+
+// Active returns the profile in use
+func (m *Manager) Active() Profile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, _ := m.find(m.index.Active)
+	return p
+}
+
+// Get returns the profile with the given ID
+func (m *Manager) Get(id string) (Profile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.find(id)
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", id)
+	}
+	return p, nil
+}
+
+// Path returns the database file of a profile
+func (m *Manager) Path(p Profile) string {
+	if filepath.IsAbs(p.File) {
+		return p.File
+	}
+	return filepath.Join(m.dir, p.File)
+}
+
+// Create adds a profile with a new database seeded from seedSet
+func (m *Manager) Create(name string, seedSet string) (Profile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Profile{}, fmt.Errorf("profile name is required")
+	}
+	if _, err := SeedQuestions(seedSet); err != nil {
+		return Profile{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.index.Profiles {
+		if strings.EqualFold(p.Name, name) {
+			return Profile{}, fmt.Errorf("a profile named %q already exists", p.Name)
+		}
+	}
+
+	id := m.newID(name)
+	p := Profile{
+		ID:        id,
+		Name:      name,
+		File:      filepath.Join("profiles", id+".db"),
+		SeedSet:   seedSet,
+		CreatedAt: time.Now(),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.Path(p)), 0755); err != nil {
+		return Profile{}, err
+	}
+	db, err := database.Open(m.Path(p))
+	if err != nil {
+		return Profile{}, err
+	}
+	err = seedIfEmpty(db, seedSet)
+	db.Close()
+	if err != nil {
+		removeDatabase(m.Path(p))
+		return Profile{}, err
+	}
+	p.Seeded = true
+
+	m.index.Profiles = append(m.index.Profiles, p)
+	if err := m.save(); err != nil {
+		m.index.Profiles = m.index.Profiles[:len(m.index.Profiles)-1]
+		removeDatabase(m.Path(p))
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// OpenDatabase opens a profile's database, adding its seed questions the
+// first time if it has none
+func (m *Manager) OpenDatabase(id string) (*sql.DB, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.indexOf(id)
+	if i < 0 {
+		return nil, fmt.Errorf("profile %q not found", id)
+	}
+	p := &m.index.Profiles[i]
+
+	db, err := database.Open(m.Path(*p))
+	if err != nil {
+		return nil, err
+	}
+	if p.Seeded {
+		return db, nil
+	}
+
+	if err := seedIfEmpty(db, p.SeedSet); err != nil {
+		db.Close()
+		return nil, err
+	}
+	p.Seeded = true
+	if err := m.save(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// SetActive records the profile in use
+func (m *Manager) SetActive(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.find(id); !ok {
+		return fmt.Errorf("profile %q not found", id)
+	}
+
+	previous := m.index.Active
+	m.index.Active = id
+	if err := m.save(); err != nil {
+		m.index.Active = previous
+		return err
+	}
+	return nil
+}
+
+// Delete removes a profile and its database. The active profile and the
+// default profile can't be deleted.
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id == DefaultID {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+	if id == m.index.Active {
+		return fmt.Errorf("the active profile can't be deleted")
+	}
+
+	for i, p := range m.index.Profiles {
+		if p.ID != id {
+			continue
+		}
+
+		profiles := append(append([]Profile(nil), m.index.Profiles[:i]...), m.index.Profiles[i+1:]...)
+		previous := m.index.Profiles
+		m.index.Profiles = profiles
+		if err := m.save(); err != nil {
+			m.index.Profiles = previous
+			return err
+		}
+		return removeDatabase(m.Path(p))
+	}
+	return fmt.Errorf("profile %q not found", id)
+}
+
+func (m *Manager) find(id string) (Profile, bool) {
+	i := m.indexOf(id)
+	if i < 0 {
+		return Profile{}, false
+	}
+	return m.index.Profiles[i], true
+}
+
+func (m *Manager) indexOf(id string) int {
+	for i, p := range m.index.Profiles {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// newID derives a unique, file-name safe ID from a profile name
+func (m *Manager) newID(name string) string {
+	base := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "profile"
+	}
+
+	id := base
+	for n := 2; ; n++ {
+		_, taken := m.find(id)
+		if _, err := os.Stat(filepath.Join(m.dir, "profiles", id+".db")); !taken && errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// seedIfEmpty adds the seed questions if db has no questions yet, so a
+// database from before profiles existed keeps its own
+func seedIfEmpty(db *sql.DB, seedSet string) error {
+	count := 0
+	if err := db.QueryRow("SELECT COUNT(*) FROM questions").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return Seed(models.NewStores(db, nil), seedSet)
+}
+
+func (m *Manager) indexPath() string {
+	return filepath.Join(m.dir, IndexFile)
+}
+
+// save writes the index atomically so a crash can't leave it half written
+func (m *Manager) save() error {
+	data, err := json.MarshalIndent(m.index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := m.indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.indexPath())
+}
+
+// removeDatabase deletes a database file along with its journal files and attachments
+func removeDatabase(path string) error {
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		os.Remove(path + suffix)
+	}
+	os.RemoveAll(attachments.Dir(path))
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
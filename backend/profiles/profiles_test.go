@@ -0,0 +1,144 @@
+// backend/profiles/profiles_test.go
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myproject/backend/database"
+	"myproject/backend/models"
+)
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+
+	countQuestions := func(t *testing.T, path string) int {
+		t.Helper()
+		db, err := database.Open(path)
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", path, err)
+		}
+		defer db.Close()
+
+		questions, err := models.NewQuestionStore(db, nil).GetAll()
+		if err != nil {
+			t.Fatalf("Failed to get questions: %v", err)
+		}
+		return len(questions)
+	}
+
+	m, err := Open(dir, "DailyReflection.db")
+	if err != nil {
+		t.Fatalf("Failed to open manager: %v", err)
+	}
+
+	// Test that the original database becomes the default profile
+	t.Run("DefaultProfile", func(t *testing.T) {
+		active := m.Active()
+		if active.ID != DefaultID {
+			t.Fatalf("Expected default profile to be active, got %q", active.ID)
+		}
+
+		if m.Path(active) != filepath.Join(dir, "DailyReflection.db") {
+			t.Errorf("Unexpected default database path %s", m.Path(active))
+		}
+
+		db, err := m.OpenDatabase(active.ID)
+		if err != nil {
+			t.Fatalf("Failed to open default database: %v", err)
+		}
+		db.Close()
+
+		defaults, _ := SeedQuestions(SeedDefault)
+		if n := countQuestions(t, m.Path(active)); n != len(defaults) {
+			t.Errorf("Expected %d seeded questions, got %d", len(defaults), n)
+		}
+	})
+
+	// Test creating profiles with different seed sets
+	t.Run("CreateProfile", func(t *testing.T) {
+		work, err := m.Create("Work Journal", SeedWork)
+		if err != nil {
+			t.Fatalf("Failed to create profile: %v", err)
+		}
+
+		if work.ID != "work-journal" {
+			t.Errorf("Expected ID 'work-journal', got %q", work.ID)
+		}
+
+		questions, _ := SeedQuestions(SeedWork)
+		if n := countQuestions(t, m.Path(work)); n != len(questions) {
+			t.Errorf("Expected %d work questions, got %d", len(questions), n)
+		}
+
+		empty, err := m.Create("Blank", SeedNone)
+		if err != nil {
+			t.Fatalf("Failed to create profile: %v", err)
+		}
+
+		if n := countQuestions(t, m.Path(empty)); n != 0 {
+			t.Errorf("Expected no questions, got %d", n)
+		}
+
+		if _, err := m.Create("work journal", SeedNone); err == nil {
+			t.Errorf("Expected error for duplicate profile name")
+		}
+
+		if _, err := m.Create("Other", "unknown"); err == nil {
+			t.Errorf("Expected error for unknown seed set")
+		}
+
+		if len(m.List()) != 3 {
+			t.Errorf("Expected 3 profiles, got %d", len(m.List()))
+		}
+	})
+
+	// Test that the index survives reopening
+	t.Run("Reopen", func(t *testing.T) {
+		if err := m.SetActive("work-journal"); err != nil {
+			t.Fatalf("Failed to set active profile: %v", err)
+		}
+
+		reopened, err := Open(dir, "DailyReflection.db")
+		if err != nil {
+			t.Fatalf("Failed to reopen manager: %v", err)
+		}
+
+		if reopened.Active().ID != "work-journal" {
+			t.Errorf("Expected active profile 'work-journal', got %q", reopened.Active().ID)
+		}
+
+		if len(reopened.List()) != 3 {
+			t.Errorf("Expected 3 profiles, got %d", len(reopened.List()))
+		}
+	})
+
+	// Test deleting profiles
+	t.Run("DeleteProfile", func(t *testing.T) {
+		if err := m.Delete("work-journal"); err == nil {
+			t.Errorf("Expected error deleting the active profile")
+		}
+
+		if err := m.Delete(DefaultID); err == nil {
+			t.Errorf("Expected error deleting the default profile")
+		}
+
+		blank, err := m.Get("blank")
+		if err != nil {
+			t.Fatalf("Failed to get profile: %v", err)
+		}
+
+		if err := m.Delete(blank.ID); err != nil {
+			t.Fatalf("Failed to delete profile: %v", err)
+		}
+
+		if _, err := os.Stat(m.Path(blank)); !os.IsNotExist(err) {
+			t.Errorf("Expected database file to be removed, got %v", err)
+		}
+
+		if _, err := m.Get(blank.ID); err == nil {
+			t.Errorf("Expected deleted profile to be gone")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+// backend/profiles/seeds.go
+package profiles
+
+import (
+	"myproject/backend/models"
+	"myproject/backend/packs"
+)
+
+// Seed sets a new profile's questions can be chosen from. Every built-in
+// question pack is also a seed set, named by its ID.
+const (
+	SeedDefault = "default"
+	SeedWork    = "work"
+	SeedNone    = "none"
+)
+
+// SeedSets lists the available seed sets
+func SeedSets() []string {
+	names := []string{}
+	if builtin, err := packs.Builtin(); err == nil {
+		for _, p := range builtin {
+			names = append(names, p.ID)
+		}
+	}
+	return append(names, SeedNone)
+}
+
+// SeedQuestions returns the questions in a seed set
+func SeedQuestions(set string) ([]string, error) {
+	if set == SeedNone {
+		return []string{}, nil
+	}
+	pack, err := packs.BuiltinPack(set)
+	if err != nil {
+		return nil, err
+	}
+	return pack.Questions, nil
+}
+
+// Seed installs the built-in pack for a seed set
+func Seed(stores *models.Stores, set string) error {
+	if set == SeedNone {
+		return nil
+	}
+	pack, err := packs.BuiltinPack(set)
+	if err != nil {
+		return err
+	}
+	_, err = stores.Packs.Install(packs.ToModel(pack), pack.Questions)
+	return err
+}
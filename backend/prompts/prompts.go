@@ -0,0 +1,28 @@
+// backend/prompts/prompts.go
+package prompts
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+)
+
+//go:embed creativity.txt
+var creativityFile string
+
+// CreativityCategory is the category of the built-in creativity prompts
+const CreativityCategory = "Words"
+
+// Creativity returns the built-in creativity prompts in their original order
+func Creativity() []string {
+	var list []string
+	scanner := bufio.NewScanner(strings.NewReader(creativityFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list = append(list, line)
+	}
+	return list
+}
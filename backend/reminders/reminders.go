@@ -0,0 +1,239 @@
+// backend/reminders/reminders.go
+package reminders
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Activities a reminder rule can watch
+const (
+	ActivityAffirmation = "affirmation"
+	ActivityGratitude   = "gratitude"
+	ActivityCreativity  = "creativity"
+	ActivityAnswer      = "answer"
+
+	// The built-in routines, done once every step is
+	ActivityMorningRoutine = "morning_routine"
+	ActivityEveningRoutine = "evening_routine"
+)
+
+// Rule fires a reminder at a time of day if an activity hasn't been done that day
+type Rule struct {
+	ID       string `json:"id"`
+	Activity string `json:"activity"`
+	At       string `json:"at"` // HH:MM in local time
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// Notification is sent when a rule fires
+type Notification struct {
+	RuleID   string    `json:"ruleId"`
+	Activity string    `json:"activity"`
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+	FiredAt  time.Time `json:"firedAt"`
+}
+
+// Clock provides the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Notifier delivers reminder notifications
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// StatusFunc reports whether an activity has been done on the given day
+type StatusFunc func(activity string, day time.Time) (bool, error)
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
+
+// DefaultRules are used until the user saves their own
+func DefaultRules() []Rule {
+	return []Rule{
+		{ID: "affirmation", Activity: ActivityAffirmation, At: "10:00", Title: "Daily affirmation", Message: "You haven't completed today's affirmation yet.", Enabled: true},
+		{ID: "creativity", Activity: ActivityCreativity, At: "20:00", Title: "Creativity journal", Message: "There's still time for today's creativity entry.", Enabled: true},
+		{ID: "gratitude", Activity: ActivityGratitude, At: "21:00", Title: "Gratitude journal", Message: "What are you grateful for today?", Enabled: true},
+	}
+}
+
+// Validate checks a rule's activity and time
+func (r Rule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("reminder rule is missing an id")
+	}
+	switch r.Activity {
+	case ActivityAffirmation, ActivityGratitude, ActivityCreativity, ActivityAnswer,
+		ActivityMorningRoutine, ActivityEveningRoutine:
+	default:
+		return fmt.Errorf("reminder %q has unknown activity %q", r.ID, r.Activity)
+	}
+	if _, _, err := r.timeOfDay(); err != nil {
+		return fmt.Errorf("reminder %q: %w", r.ID, err)
+	}
+	return nil
+}
+
+func (r Rule) timeOfDay() (int, int, error) {
+	t, err := time.Parse("15:04", r.At)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", r.At)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Scheduler evaluates reminder rules in the background
+type Scheduler struct {
+	clock    Clock
+	notifier Notifier
+	status   StatusFunc
+
+	mu    sync.Mutex
+	rules []Rule
+	fired map[string]string // Rule ID to the date it last fired
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewScheduler creates a scheduler with no rules
+func NewScheduler(clock Clock, notifier Notifier, status StatusFunc) *Scheduler {
+	return &Scheduler{
+		clock:    clock,
+		notifier: notifier,
+		status:   status,
+		fired:    map[string]string{},
+	}
+}
+
+// SetRules replaces the rules being evaluated
+func (s *Scheduler) SetRules(rules []Rule) error {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = append([]Rule(nil), rules...)
+	return nil
+}
+
+// Rules returns the rules being evaluated
+func (s *Scheduler) Rules() []Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Rule(nil), s.rules...)
+}
+
+// Check evaluates every rule once, firing those that are due. Each rule
+// fires at most once per day, and not at all once its activity is done.
+// A rule that fails doesn't stop the others; their errors are returned
+// together.
+func (s *Scheduler) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	today := now.Format("2006-01-02")
+
+	var errs []error
+	for _, r := range s.rules {
+		if !r.Enabled || s.fired[r.ID] == today {
+			continue
+		}
+
+		hour, minute, err := r.timeOfDay()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reminder %q: %w", r.ID, err))
+			continue
+		}
+		due := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if now.Before(due) {
+			continue
+		}
+
+		done, err := s.status(r.Activity, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reminder %q: %w", r.ID, err))
+			continue
+		}
+		if done {
+			// Nothing to remind about today
+			s.fired[r.ID] = today
+			continue
+		}
+
+		err = s.notifier.Notify(Notification{
+			RuleID:   r.ID,
+			Activity: r.Activity,
+			Title:    r.Title,
+			Message:  r.Message,
+			FiredAt:  now,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reminder %q: %w", r.ID, err))
+			continue
+		}
+		s.fired[r.ID] = today
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start runs Check on the given interval until Stop is called
+func (s *Scheduler) Start(interval time.Duration, onError func(error)) {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := s.Check(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the background goroutine and waits for it to exit
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
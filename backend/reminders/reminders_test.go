@@ -0,0 +1,145 @@
+// backend/reminders/reminders_test.go
+package reminders
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+type fakeNotifier struct {
+	sent []Notification
+}
+
+func (n *fakeNotifier) Notify(notification Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 3, 18, 20, 0, 0, 0, time.Local)}
+	notifier := &fakeNotifier{}
+	done := map[string]bool{}
+
+	scheduler := NewScheduler(clock, notifier, func(activity string, day time.Time) (bool, error) {
+		return done[activity], nil
+	})
+
+	err := scheduler.SetRules([]Rule{
+		{ID: "gratitude", Activity: ActivityGratitude, At: "21:00", Message: "Gratitude time", Enabled: true},
+		{ID: "creativity", Activity: ActivityCreativity, At: "19:00", Message: "Creativity time", Enabled: true},
+		{ID: "affirmation", Activity: ActivityAffirmation, At: "09:00", Enabled: false},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set rules: %v", err)
+	}
+
+	// Test rules before and after their time
+	t.Run("FiresWhenDue", func(t *testing.T) {
+		if err := scheduler.Check(); err != nil {
+			t.Fatalf("Failed to check rules: %v", err)
+		}
+
+		// Only the creativity rule is due, and the affirmation rule is disabled
+		if len(notifier.sent) != 1 || notifier.sent[0].RuleID != "creativity" {
+			t.Fatalf("Expected only the creativity reminder, got %+v", notifier.sent)
+		}
+
+		// A rule fires only once per day
+		if err := scheduler.Check(); err != nil {
+			t.Fatalf("Failed to check rules: %v", err)
+		}
+
+		if len(notifier.sent) != 1 {
+			t.Errorf("Expected 1 notification, got %d", len(notifier.sent))
+		}
+	})
+
+	// Test that completed activities don't fire
+	t.Run("SkipsCompletedActivity", func(t *testing.T) {
+		done[ActivityGratitude] = true
+		clock.now = time.Date(2025, 3, 18, 21, 30, 0, 0, time.Local)
+
+		if err := scheduler.Check(); err != nil {
+			t.Fatalf("Failed to check rules: %v", err)
+		}
+
+		if len(notifier.sent) != 1 {
+			t.Errorf("Expected no new notification, got %+v", notifier.sent)
+		}
+	})
+
+	// Test that rules fire again on the next day
+	t.Run("NextDay", func(t *testing.T) {
+		done[ActivityGratitude] = false
+		clock.now = time.Date(2025, 3, 19, 22, 0, 0, 0, time.Local)
+
+		if err := scheduler.Check(); err != nil {
+			t.Fatalf("Failed to check rules: %v", err)
+		}
+
+		if len(notifier.sent) != 3 {
+			t.Fatalf("Expected 3 notifications, got %d", len(notifier.sent))
+		}
+
+		if !notifier.sent[2].FiredAt.Equal(clock.now) {
+			t.Errorf("Expected notification fired at %v, got %v", clock.now, notifier.sent[2].FiredAt)
+		}
+	})
+
+	// Test that a rule whose status can't be read doesn't hold up the others
+	t.Run("ContinuesAfterError", func(t *testing.T) {
+		notifier := &fakeNotifier{}
+		failing := errors.New("database is closed")
+		scheduler := NewScheduler(clock, notifier, func(activity string, day time.Time) (bool, error) {
+			if activity == ActivityGratitude {
+				return false, failing
+			}
+			return false, nil
+		})
+		err := scheduler.SetRules([]Rule{
+			{ID: "gratitude", Activity: ActivityGratitude, At: "09:00", Enabled: true},
+			{ID: "creativity", Activity: ActivityCreativity, At: "09:00", Enabled: true},
+		})
+		if err != nil {
+			t.Fatalf("Failed to set rules: %v", err)
+		}
+
+		if err := scheduler.Check(); !errors.Is(err, failing) {
+			t.Errorf("Expected the status error returned, got %v", err)
+		}
+		if len(notifier.sent) != 1 || notifier.sent[0].RuleID != "creativity" {
+			t.Errorf("Expected the creativity reminder still sent, got %+v", notifier.sent)
+		}
+	})
+
+	// Test rule validation
+	t.Run("Validate", func(t *testing.T) {
+		if err := scheduler.SetRules([]Rule{{ID: "x", Activity: ActivityGratitude, At: "9pm"}}); err == nil {
+			t.Errorf("Expected error for invalid time")
+		}
+
+		if err := scheduler.SetRules([]Rule{{ID: "x", Activity: "moods", At: "21:00"}}); err == nil {
+			t.Errorf("Expected error for unknown activity")
+		}
+
+		// Invalid rules leave the existing ones in place
+		if len(scheduler.Rules()) != 3 {
+			t.Errorf("Expected 3 rules, got %d", len(scheduler.Rules()))
+		}
+	})
+
+	// Test starting and stopping the background goroutine
+	t.Run("StartStop", func(t *testing.T) {
+		scheduler.Start(time.Millisecond, nil)
+		scheduler.Start(time.Millisecond, nil)
+		scheduler.Stop()
+		scheduler.Stop()
+	})
+}
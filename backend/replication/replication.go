@@ -0,0 +1,624 @@
+// backend/replication/replication.go
+package replication
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change is the latest version of one record. Deleted records are sent as
+// tombstones without data.
+type Change struct {
+	Table    string         `json:"table"`
+	UUID     string         `json:"uuid"`
+	Seq      int64          `json:"seq"`      // Position in the sending device's change log
+	Origin   int64          `json:"origin"`   // Position in the change log of the device that made the change
+	Modified int64          `json:"modified"` // Milliseconds since the Unix epoch
+	Device   string         `json:"device"`   // Device that made the change
+	Deleted  bool           `json:"deleted"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// newerThan reports whether c wins over a version modified at modified by
+// device. The later change wins, and the device ID breaks ties so every
+// device picks the same winner.
+func (c Change) newerThan(modified int64, device string) bool {
+	if c.Modified != modified {
+		return c.Modified > modified
+	}
+	return c.Device > device
+}
+
+// Conflict is a record changed on both devices since they last synced
+type Conflict struct {
+	Table          string `json:"table"`
+	UUID           string `json:"uuid"`
+	LocalModified  int64  `json:"localModified"`
+	RemoteModified int64  `json:"remoteModified"`
+	Winner         string `json:"winner"` // "local" or "remote"
+	Reason         string `json:"reason"`
+}
+
+// Result reports what applying changes did
+type Result struct {
+	Applied   int        `json:"applied"`
+	Skipped   int        `json:"skipped"` // Changes already present or older than the local version
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// reference is a foreign key, sent as the UUID of the referenced record
+type reference struct {
+	column string // Local integer column, e.g. question_id
+	field  string // Field holding the UUID in change data, e.g. question_uuid
+	table  string
+
+	// The column may be NULL. A referenced record that is missing leaves it
+	// NULL, and deleting the referenced record clears it.
+	optional bool
+}
+
+// table describes how a synced table's rows are sent
+type table struct {
+	name        string
+	columns     []string
+	timeColumns []string // Sent as the text SQLite stores, so they round-trip unchanged
+	parent      *reference
+	children    []reference // Rows deleted, or for optional references cleared, along with a row of this table
+
+	// Column that identifies a record in any journal, so records added on
+	// two devices with the same value merge instead of being duplicated
+	key string
+}
+
+// tables are the synced tables, parents before children
+var tables = []table{
+	{
+		name:        "questions",
+		columns:     []string{"content", "template"},
+		timeColumns: []string{"used_on", "created_at"},
+		children: []reference{
+			{column: "question_id", table: "answers"},
+			{column: "question_id", table: "answer_drafts"},
+			{column: "question_id", table: "question_skips"},
+		},
+		key: "content",
+	},
+	{
+		name:        "affirmations",
+		columns:     []string{"content"},
+		timeColumns: []string{"created_at", "updated_at"},
+		children: []reference{
+			{column: "affirmation_id", table: "affirmation_logs"},
+		},
+	},
+	{
+		name:        "habits",
+		columns:     []string{"builtin", "name", "frequency", "target", "unit", "archived"},
+		timeColumns: []string{"created_at", "updated_at"},
+		children: []reference{
+			{column: "habit_id", table: "habit_logs"},
+		},
+	},
+	{
+		name:        "routines",
+		columns:     []string{"builtin", "name", "steps"},
+		timeColumns: []string{"created_at", "updated_at"},
+		children: []reference{
+			{column: "routine_id", table: "routine_runs"},
+		},
+	},
+	{
+		name:        "answers",
+		columns:     []string{"content", "fields", "word_count", "char_count", "sentiment"},
+		timeColumns: []string{"created_at", "updated_at"},
+		parent:      &reference{column: "question_id", field: "question_uuid", table: "questions"},
+	},
+	{
+		name:        "question_skips",
+		columns:     []string{"reason"},
+		timeColumns: []string{"skipped_at", "excluded_until"},
+		parent:      &reference{column: "question_id", field: "question_uuid", table: "questions"},
+	},
+	{
+		name:        "affirmation_logs",
+		timeColumns: []string{"completed_at"},
+		parent:      &reference{column: "affirmation_id", field: "affirmation_uuid", table: "affirmations"},
+	},
+	{
+		name:        "goals",
+		columns:     []string{"title", "why", "target_date", "status"},
+		timeColumns: []string{"completed_at", "created_at", "updated_at"},
+		children: []reference{
+			{column: "goal_id", table: "goal_milestones"},
+			{column: "goal_id", table: "goal_checkins"},
+			{column: "goal_id", table: "goal_links"},
+		},
+	},
+	{
+		name:        "habit_logs",
+		columns:     []string{"log_date", "quantity", "note"},
+		timeColumns: []string{"created_at"},
+		parent:      &reference{column: "habit_id", field: "habit_uuid", table: "habits"},
+	},
+	{
+		name:        "goal_milestones",
+		columns:     []string{"title", "target_date", "position"},
+		timeColumns: []string{"completed_at", "created_at"},
+		parent:      &reference{column: "goal_id", field: "goal_uuid", table: "goals"},
+	},
+	{
+		name:        "goal_checkins",
+		columns:     []string{"week", "progress", "note"},
+		timeColumns: []string{"created_at", "updated_at"},
+		parent:      &reference{column: "goal_id", field: "goal_uuid", table: "goals"},
+	},
+	{
+		name:        "goal_links",
+		columns:     []string{"entity_type", "entity_uuid"},
+		timeColumns: []string{"created_at"},
+		parent:      &reference{column: "goal_id", field: "goal_uuid", table: "goals"},
+	},
+	{
+		name:        "routine_runs",
+		columns:     []string{"run_date"},
+		timeColumns: []string{"started_at", "completed_at"},
+		parent:      &reference{column: "routine_id", field: "routine_uuid", table: "routines"},
+		children: []reference{
+			{column: "run_id", table: "routine_run_steps"},
+		},
+	},
+	{
+		name:        "routine_run_steps",
+		columns:     []string{"step_key", "step_type", "entry_uuid", "note"},
+		timeColumns: []string{"done_at"},
+		parent:      &reference{column: "run_id", field: "run_uuid", table: "routine_runs"},
+	},
+	{
+		name:        "gratitude_items",
+		columns:     []string{"content", "entry_date", "sentiment"},
+		timeColumns: []string{"created_at"},
+	},
+	{
+		name:        "creativity_prompts",
+		columns:     []string{"content", "category", "builtin", "archived"},
+		timeColumns: []string{"created_at"},
+		children: []reference{
+			{column: "prompt_id", table: "creativity_entries", optional: true},
+		},
+	},
+	{
+		name:        "creativity_entries",
+		columns:     []string{"title", "kind", "content", "entry_date", "word_count", "char_count", "sentiment"},
+		timeColumns: []string{"created_at", "updated_at"},
+		parent:      &reference{column: "prompt_id", field: "prompt_uuid", table: "creativity_prompts", optional: true},
+	},
+}
+
+// lookup returns the synced table with the given name and its position in tables
+func lookup(name string) (*table, int, bool) {
+	for i := range tables {
+		if tables[i].name == name {
+			return &tables[i], i, true
+		}
+	}
+	return nil, 0, false
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// DeviceID returns the ID this database was given when change tracking was added
+func DeviceID(db *sql.DB) (string, error) {
+	var id string
+	err := db.QueryRow(`SELECT value FROM settings WHERE key = 'sync.device_id'`).Scan(&id)
+	return id, err
+}
+
+// LatestSeq returns the position of the most recent change
+func LatestSeq(db *sql.DB) (int64, error) {
+	var seq int64
+	err := db.QueryRow(`SELECT COALESCE(MAX(seq), 0) FROM sync_records`).Scan(&seq)
+	return seq, err
+}
+
+// Changes returns the latest version of every record changed after since,
+// in change order. Records last changed by exclude are left out, since that
+// device already has them; pass "" to include everything.
+func Changes(db *sql.DB, since int64, exclude string) ([]Change, error) {
+	rows, err := db.Query(`
+		SELECT table_name, uuid, seq, modified, device, deleted,
+			CASE WHEN device = (SELECT value FROM settings WHERE key = 'sync.device_id') THEN seq ELSE origin_seq END
+		FROM sync_records
+		WHERE seq > ? AND device != ?
+		ORDER BY seq ASC`, since, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	for rows.Next() {
+		var c Change
+		if err := rows.Scan(&c.Table, &c.UUID, &c.Seq, &c.Modified, &c.Device, &c.Deleted, &c.Origin); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range changes {
+		c := &changes[i]
+		if c.Deleted {
+			continue
+		}
+		t, _, ok := lookup(c.Table)
+		if !ok {
+			return nil, fmt.Errorf("unknown synced table %q", c.Table)
+		}
+		if c.Data, err = readRow(db, t, c.UUID); err != nil {
+			return nil, fmt.Errorf("reading %s %s: %w", c.Table, c.UUID, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// readRow reads the synced columns of a record
+func readRow(q queryer, t *table, uuid string) (map[string]any, error) {
+	var selects []string
+	var fields []string
+	for _, c := range t.columns {
+		selects = append(selects, c)
+		fields = append(fields, c)
+	}
+	for _, c := range t.timeColumns {
+		selects = append(selects, `CAST(`+c+` AS TEXT)`)
+		fields = append(fields, c)
+	}
+	if t.parent != nil {
+		selects = append(selects, `(SELECT p.uuid FROM `+t.parent.table+` p WHERE p.id = `+t.parent.column+`)`)
+		fields = append(fields, t.parent.field)
+	}
+
+	values := make([]any, len(fields))
+	ptrs := make([]any, len(fields))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	err := q.QueryRow(`SELECT `+strings.Join(selects, ", ")+` FROM `+t.name+` WHERE uuid = ?`, uuid).Scan(ptrs...)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]any, len(fields))
+	for i, f := range fields {
+		if b, ok := values[i].([]byte); ok {
+			values[i] = string(b)
+		}
+		data[f] = values[i]
+	}
+	return data, nil
+}
+
+// version is the local change record of a record
+type version struct {
+	seq      int64
+	modified int64
+	device   string
+	deleted  bool
+}
+
+// Apply merges changes from another device. Each change replaces the local
+// version when it is newer. A record changed locally after conflictSince,
+// the last local change the other device has seen, has been changed on
+// both devices and is reported as a conflict.
+func Apply(db *sql.DB, changes []Change, conflictSince int64) (*Result, error) {
+	// Parents are written before their children and deleted after them
+	ordered := make([]Change, len(changes))
+	copy(ordered, changes)
+	position := func(c Change) int {
+		_, i, _ := lookup(c.Table)
+		if c.Deleted {
+			return len(tables) + (len(tables) - i)
+		}
+		return i
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return position(ordered[i]) < position(ordered[j])
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &Result{Conflicts: []Conflict{}}
+	for _, c := range ordered {
+		t, _, ok := lookup(c.Table)
+		if !ok {
+			return nil, fmt.Errorf("unknown synced table %q", c.Table)
+		}
+
+		// Records merged into another arrive under their old UUID
+		uuid, err := resolve(tx, c.Table, c.UUID)
+		if err != nil {
+			return nil, err
+		}
+		c.UUID = uuid
+
+		var local version
+		err = tx.QueryRow(`
+			SELECT seq, modified, device, deleted
+			FROM sync_records
+			WHERE table_name = ? AND uuid = ?`, c.Table, c.UUID).Scan(&local.seq, &local.modified, &local.device, &local.deleted)
+		exists := err == nil
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		if exists {
+			if c.Modified == local.modified && c.Device == local.device && c.Deleted == local.deleted {
+				result.Skipped++
+				continue
+			}
+
+			winner := "local"
+			if c.newerThan(local.modified, local.device) {
+				winner = "remote"
+			}
+			same, err := sameContent(tx, t, local, c)
+			if err != nil {
+				return nil, err
+			}
+			// The sending device already knew about its own earlier changes
+			if local.seq > conflictSince && local.device != c.Device && !same {
+				result.Conflicts = append(result.Conflicts, Conflict{
+					Table:          c.Table,
+					UUID:           c.UUID,
+					LocalModified:  local.modified,
+					RemoteModified: c.Modified,
+					Winner:         winner,
+					Reason:         "changed on both devices",
+				})
+			}
+			if winner == "local" {
+				result.Skipped++
+				continue
+			}
+		}
+
+		if !exists && !c.Deleted && t.key != "" {
+			absorbed, err := merge(tx, t, &c)
+			if err != nil {
+				return nil, err
+			}
+			if absorbed {
+				result.Skipped++
+				continue
+			}
+		}
+
+		if c.Deleted {
+			err = deleteRow(tx, t, c.UUID)
+		} else {
+			err = writeRow(tx, t, c)
+		}
+		if err == errMissingParent {
+			// The record belongs to one deleted here, so it can't be restored
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Table:          c.Table,
+				UUID:           c.UUID,
+				LocalModified:  local.modified,
+				RemoteModified: c.Modified,
+				Winner:         "local",
+				Reason:         "belongs to a record deleted on this device",
+			})
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("applying %s %s: %w", c.Table, c.UUID, err)
+		}
+
+		// Keep the other device's version, so both devices agree on it and
+		// don't send it back and forth. Writing the row gave it a local seq,
+		// so it is passed on to devices that haven't seen it yet.
+		_, err = tx.Exec(`
+			INSERT INTO sync_records (table_name, uuid, seq, modified, device, deleted, origin_seq)
+			VALUES (?, ?, (SELECT COALESCE(MAX(seq), 0) + 1 FROM sync_records), ?, ?, ?, ?)
+			ON CONFLICT (table_name, uuid) DO UPDATE SET
+				modified = excluded.modified, device = excluded.device, deleted = excluded.deleted,
+				origin_seq = excluded.origin_seq`,
+			c.Table, c.UUID, c.Modified, c.Device, c.Deleted, c.Origin)
+		if err != nil {
+			return nil, err
+		}
+		result.Applied++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sameContent reports whether a change carries what the local record already
+// holds, ignoring timestamps. Devices that made the same change, such as
+// installing the same question pack, don't conflict.
+func sameContent(q queryer, t *table, local version, c Change) (bool, error) {
+	if local.deleted || c.Deleted {
+		return local.deleted == c.Deleted, nil
+	}
+
+	data, err := readRow(q, t, c.UUID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fields := append([]string{}, t.columns...)
+	if t.parent != nil {
+		fields = append(fields, t.parent.field)
+	}
+	for _, f := range fields {
+		if fmt.Sprint(normalize(data[f])) != fmt.Sprint(normalize(c.Data[f])) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// normalize makes numbers compare equal whether they were read from the
+// database or decoded from JSON
+func normalize(v any) any {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return v
+}
+
+// resolve returns the UUID of the record a record was merged into, or the
+// UUID itself if it wasn't merged
+func resolve(q queryer, table string, uuid string) (string, error) {
+	var target string
+	err := q.QueryRow(`SELECT target FROM sync_aliases WHERE table_name = ? AND uuid = ?`, table, uuid).Scan(&target)
+	if err == sql.ErrNoRows {
+		return uuid, nil
+	}
+	return target, err
+}
+
+// merge looks for a local record with the same key as a record new to this
+// device, meaning both devices added it. Both devices keep the UUID that
+// sorts first: a local record whose UUID sorts first absorbs the change,
+// and one whose UUID sorts after takes the change's UUID. It reports
+// whether the change was absorbed.
+func merge(tx *sql.Tx, t *table, c *Change) (bool, error) {
+	var id int64
+	var local string
+	err := tx.QueryRow(`SELECT id, uuid FROM `+t.name+` WHERE `+t.key+` = ? ORDER BY uuid LIMIT 1`, c.Data[t.key]).Scan(&id, &local)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	from, to := c.UUID, local
+	if c.UUID < local {
+		from, to = local, c.UUID
+		if _, err := tx.Exec(`UPDATE `+t.name+` SET uuid = ? WHERE id = ?`, to, id); err != nil {
+			return false, err
+		}
+		// The change is recorded under the new UUID when it is written
+		if _, err := tx.Exec(`DELETE FROM sync_records WHERE table_name = ? AND uuid = ?`, t.name, from); err != nil {
+			return false, err
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO sync_aliases (table_name, uuid, target) VALUES (?, ?, ?)
+		ON CONFLICT (table_name, uuid) DO UPDATE SET target = excluded.target`, t.name, from, to)
+	if err != nil {
+		return false, err
+	}
+	_, err = tx.Exec(`UPDATE sync_aliases SET target = ? WHERE table_name = ? AND target = ?`, to, t.name, from)
+	return to == local, err
+}
+
+var errMissingParent = fmt.Errorf("parent record is missing")
+
+// writeRow inserts or updates a record from change data
+func writeRow(tx *sql.Tx, t *table, c Change) error {
+	var columns []string
+	var values []any
+	for _, col := range append(append([]string{}, t.columns...), t.timeColumns...) {
+		columns = append(columns, col)
+		values = append(values, c.Data[col])
+	}
+
+	if t.parent != nil {
+		parentUUID := c.Data[t.parent.field]
+		if s, ok := parentUUID.(string); ok {
+			var err error
+			if parentUUID, err = resolve(tx, t.parent.table, s); err != nil {
+				return err
+			}
+		}
+
+		var parentID sql.NullInt64
+		err := tx.QueryRow(`SELECT id FROM `+t.parent.table+` WHERE uuid = ?`, parentUUID).Scan(&parentID)
+		if err == sql.ErrNoRows && !t.parent.optional {
+			return errMissingParent
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		columns = append(columns, t.parent.column)
+		values = append(values, parentID)
+	}
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM `+t.name+` WHERE uuid = ?)`, c.UUID).Scan(&exists); err != nil {
+		return err
+	}
+
+	if exists {
+		set := make([]string, len(columns))
+		for i, col := range columns {
+			set[i] = col + ` = ?`
+		}
+		_, err := tx.Exec(`UPDATE `+t.name+` SET `+strings.Join(set, ", ")+` WHERE uuid = ?`, append(values, c.UUID)...)
+		return err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)+1), ", ")
+	_, err := tx.Exec(`INSERT INTO `+t.name+` (`+strings.Join(columns, ", ")+`, uuid) VALUES (`+placeholders+`)`, append(values, c.UUID)...)
+	return err
+}
+
+// deleteRow deletes a record and the rows that belong to it, as deleting it
+// in the app would
+func deleteRow(tx *sql.Tx, t *table, uuid string) error {
+	return deleteRows(tx, t, `uuid = ?`, uuid)
+}
+
+// deleteRows deletes the rows of t matching where, along with the rows
+// that belong to them, however deep
+func deleteRows(tx *sql.Tx, t *table, where string, args ...any) error {
+	for _, child := range t.children {
+		belongs := child.column + ` IN (SELECT id FROM ` + t.name + ` WHERE ` + where + `)`
+		if child.optional {
+			if _, err := tx.Exec(`UPDATE `+child.table+` SET `+child.column+` = NULL WHERE `+belongs, args...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Children that aren't synced have no children of their own
+		if ct, _, ok := lookup(child.table); ok {
+			if err := deleteRows(tx, ct, belongs, args...); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM `+child.table+` WHERE `+belongs, args...); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(`DELETE FROM `+t.name+` WHERE `+where, args...)
+	return err
+}
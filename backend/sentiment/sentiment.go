@@ -0,0 +1,126 @@
+// backend/sentiment/sentiment.go
+package sentiment
+
+import (
+	"bufio"
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+
+	"myproject/backend/textstats"
+)
+
+//go:embed lexicon.txt
+var lexiconFile string
+
+var lexicon = parseLexicon(lexiconFile)
+
+// negations flip the score of the words that follow them
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "nothing": true, "hardly": true, "without": true,
+	"don't": true, "didn't": true, "isn't": true, "wasn't": true, "aren't": true, "weren't": true,
+	"can't": true, "couldn't": true, "won't": true, "wouldn't": true, "shouldn't": true, "haven't": true,
+}
+
+// negationWindow is how many words after a negation are flipped
+const negationWindow = 3
+
+// normalization controls how quickly the score approaches ±1 as word scores add up
+const normalization = 15
+
+// Result is the sentiment of a piece of text
+type Result struct {
+	Score    float64  `json:"score"` // From -1 (negative) to 1 (positive)
+	Positive []string `json:"positive"`
+	Negative []string `json:"negative"`
+}
+
+func parseLexicon(data string) map[string]int {
+	words := map[string]int{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		score, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		words[fields[0]] = score
+	}
+	return words
+}
+
+// Analyze scores text using the embedded lexicon. Words shortly after a
+// negation such as "not" count against their usual score.
+func Analyze(text string) Result {
+	result := Result{Positive: []string{}, Negative: []string{}}
+
+	sum := 0
+	negatedUntil := -1
+	for i, w := range textstats.Words(text) {
+		w = strings.ReplaceAll(w, "’", "'")
+		if negations[w] {
+			negatedUntil = i + negationWindow
+			continue
+		}
+
+		score, ok := lexicon[w]
+		if !ok {
+			continue
+		}
+		if i <= negatedUntil {
+			score = -score
+		}
+
+		sum += score
+		if score > 0 {
+			result.Positive = append(result.Positive, w)
+		} else if score < 0 {
+			result.Negative = append(result.Negative, w)
+		}
+	}
+
+	result.Score = float64(sum) / math.Sqrt(float64(sum*sum)+normalization)
+	return result
+}
+
+// Score returns just the score of text, from -1 to 1
+func Score(text string) float64 {
+	return Analyze(text).Score
+}
+
+// Correlation returns the Pearson correlation of two equally long series,
+// and false when it isn't defined: fewer than two points or no variation
+func Correlation(xs []float64, ys []float64) (float64, bool) {
+	n := len(xs)
+	if n < 2 || n != len(ys) {
+		return 0, false
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
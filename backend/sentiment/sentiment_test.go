@@ -0,0 +1,57 @@
+// backend/sentiment/sentiment_test.go
+package sentiment
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSentiment(t *testing.T) {
+	// Test the direction of scores
+	t.Run("Analyze", func(t *testing.T) {
+		if score := Score("What a wonderful, happy day with friends"); score <= 0.5 {
+			t.Errorf("Expected a clearly positive score, got %v", score)
+		}
+
+		if score := Score("I felt anxious and exhausted, a terrible day"); score >= -0.5 {
+			t.Errorf("Expected a clearly negative score, got %v", score)
+		}
+
+		if score := Score("I went to the shop and bought bread"); score != 0 {
+			t.Errorf("Expected a neutral score, got %v", score)
+		}
+
+		result := Analyze("Happy but tired")
+		if len(result.Positive) != 1 || len(result.Negative) != 1 {
+			t.Errorf("Expected one positive and one negative word, got %+v", result)
+		}
+	})
+
+	// Test that negation flips nearby words
+	t.Run("Negation", func(t *testing.T) {
+		if score := Score("I was not happy today"); score >= 0 {
+			t.Errorf("Expected 'not happy' to be negative, got %v", score)
+		}
+
+		if score := Score("I didn’t feel sad at all"); score <= 0 {
+			t.Errorf("Expected 'didn't feel sad' to be positive, got %v", score)
+		}
+	})
+
+	// Test the correlation helper
+	t.Run("Correlation", func(t *testing.T) {
+		r, ok := Correlation([]float64{1, 2, 3, 4}, []float64{2, 4, 6, 8})
+		if !ok || math.Abs(r-1) > 1e-9 {
+			t.Errorf("Expected perfect correlation, got %v (%v)", r, ok)
+		}
+
+		r, ok = Correlation([]float64{1, 2, 3}, []float64{3, 2, 1})
+		if !ok || math.Abs(r+1) > 1e-9 {
+			t.Errorf("Expected perfect negative correlation, got %v (%v)", r, ok)
+		}
+
+		if _, ok := Correlation([]float64{1, 1}, []float64{1, 2}); ok {
+			t.Errorf("Expected no correlation without variation")
+		}
+	})
+}
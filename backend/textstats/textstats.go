@@ -0,0 +1,205 @@
+// backend/textstats/textstats.go
+package textstats
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Words splits text into lower-case words. Apostrophes inside a word are
+// kept, so "don't" is one word.
+func Words(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.Trim(f, "'’")
+		if f != "" {
+			words = append(words, strings.ToLower(f))
+		}
+	}
+	return words
+}
+
+// Normalize reduces text to a form used to detect duplicates: lower case,
+// single spaces and no trailing punctuation
+func Normalize(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return strings.TrimRight(text, "?.!… ")
+}
+
+// Preview shortens text to at most maxLen characters on one line, for
+// showing in a list or summary
+func Preview(text string, maxLen int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return string(runes[:maxLen]) + "…"
+}
+
+// Count returns the number of words and characters in text
+func Count(text string) (words int, chars int) {
+	return len(Words(text)), utf8.RuneCountInString(text)
+}
+
+// WordCount is how often a word was used
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// Frequencies counts word use across texts, leaving out stop words,
+// numbers and single letters
+type Frequencies map[string]int
+
+// Add counts the words in text
+func (f Frequencies) Add(text string) {
+	for _, w := range Words(text) {
+		if IsStopWord(w) || utf8.RuneCountInString(w) < 2 || isNumber(w) {
+			continue
+		}
+		f[w]++
+	}
+}
+
+// Top returns the n most frequent words, most frequent first. Ties are
+// broken alphabetically.
+func (f Frequencies) Top(n int) []WordCount {
+	counts := make([]WordCount, 0, len(f))
+	for w, c := range f {
+		counts = append(counts, WordCount{Word: w, Count: c})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Word < counts[j].Word
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// hashtagPattern matches a # starting a word, followed by a letter. The
+// # can't follow a word character, so URL fragments aren't hashtags, and
+// Markdown headings need a space after the #.
+var hashtagPattern = regexp.MustCompile(`(^|[^\p{L}\p{N}_&/#])#(\p{L}[\p{L}\p{N}_-]*)`)
+
+// Hashtags returns the distinct #hashtags in text, lower-cased and without
+// the #, in the order they first appear
+func Hashtags(text string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, m := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		tag := normalizeHashtag(m[2])
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ReplaceHashtag replaces #from in text with #to, whatever case #from was written in
+func ReplaceHashtag(text string, from string, to string) string {
+	return hashtagPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := hashtagPattern.FindStringSubmatch(match)
+		if normalizeHashtag(m[2]) != from {
+			return match
+		}
+		trimmed := strings.TrimRight(m[2], "-_")
+		return m[1] + "#" + to + m[2][len(trimmed):]
+	})
+}
+
+// IsHashtag reports whether tag can be written as a #hashtag
+func IsHashtag(tag string) bool {
+	tags := Hashtags("#" + tag)
+	return len(tags) == 1 && tags[0] == tag
+}
+
+// normalizeHashtag lower-cases a hashtag and drops trailing punctuation,
+// as in "#work-"
+func normalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimRight(tag, "-_"))
+}
+
+// linkPattern matches a [[ref]] or [[ref|label]] link to another entry
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]|\n]+)(\|[^\[\]\n]*)?\]\]`)
+
+// Links returns the distinct refs of the [[links]] in text, trimmed, in the
+// order they first appear. Refs differing only in case are the same.
+func Links(text string) []string {
+	refs := []string{}
+	seen := map[string]bool{}
+	for _, m := range linkPattern.FindAllStringSubmatch(text, -1) {
+		ref := strings.TrimSpace(m[1])
+		if ref != "" && !seen[strings.ToLower(ref)] {
+			seen[strings.ToLower(ref)] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ReplaceLink points the [[links]] to from in text at to, keeping their labels
+func ReplaceLink(text string, from string, to string) string {
+	return linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := linkPattern.FindStringSubmatch(match)
+		if !strings.EqualFold(strings.TrimSpace(m[1]), from) {
+			return match
+		}
+		return "[[" + to + m[2] + "]]"
+	})
+}
+
+// IsStopWord reports whether a lower-case word is too common to be interesting
+func IsStopWord(w string) bool {
+	return stopWords[strings.ReplaceAll(w, "’", "'")]
+}
+
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		a about above after again against all also am an and any are aren't as at
+		be because been before being below between both but by
+		can can't cannot could couldn't
+		did didn't do does doesn't doing don't down during
+		each even ever every
+		few for from further
+		get got had hadn't has hasn't have haven't having he he'd he'll he's her here
+		here's hers herself him himself his how how's
+		i i'd i'll i'm i've if in into is isn't it it's its itself just
+		let's like made make many may me might more most much must mustn't my myself
+		no nor not now of off on once one only or other ought our ours ourselves out
+		over own really
+		same shan't she she'd she'll she's should shouldn't so some still such
+		than that that's the their theirs them themselves then there there's these
+		they they'd they'll they're they've thing things this those though through to
+		too under until up upon us very
+		want was wasn't way we we'd we'll we're we've well were weren't what what's
+		when when's where where's which while who who's whom why why's will with
+		won't would wouldn't yet you you'd you'll you're you've your yours yourself
+		yourselves
+	`) {
+		stopWords[w] = true
+	}
+}
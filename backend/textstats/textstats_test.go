@@ -0,0 +1,96 @@
+// backend/textstats/textstats_test.go
+package textstats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTextStats(t *testing.T) {
+	// Test splitting text into words
+	t.Run("Words", func(t *testing.T) {
+		got := Words("Today I didn't rush — I walked, slowly... 'Twas nice! Café au lait x2")
+		want := []string{"today", "i", "didn't", "rush", "i", "walked", "slowly", "twas", "nice", "café", "au", "lait", "x2"}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	// Test word and character counts
+	t.Run("Count", func(t *testing.T) {
+		words, chars := Count("Grateful for café mornings.")
+		if words != 4 {
+			t.Errorf("Expected 4 words, got %d", words)
+		}
+
+		if chars != 27 {
+			t.Errorf("Expected 27 characters, got %d", chars)
+		}
+
+		if words, chars := Count("   "); words != 0 || chars != 3 {
+			t.Errorf("Expected 0 words and 3 characters, got %d and %d", words, chars)
+		}
+	})
+
+	// Test previews are shortened to one line
+	t.Run("Preview", func(t *testing.T) {
+		if p := Preview("Slept  well.\nWent running", 80); p != "Slept well. Went running" {
+			t.Errorf("Expected the preview on one line, got %q", p)
+		}
+		if p := Preview("Café mornings", 4); p != "Café…" {
+			t.Errorf("Expected the preview shortened, got %q", p)
+		}
+	})
+
+	// Test frequent words leave out stop words
+	t.Run("Frequencies", func(t *testing.T) {
+		f := Frequencies{}
+		f.Add("I walked the dog and the dog was happy.")
+		f.Add("Walked again in 2024; I’m happy I did.")
+
+		got := f.Top(3)
+		want := []WordCount{{"dog", 2}, {"happy", 2}, {"walked", 2}}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	// Test finding and replacing hashtags
+	t.Run("Hashtags", func(t *testing.T) {
+		text := "#Family dinner, then #work-\n# Heading\nSee example.com/page#anchor and #family again. C# #2024 #café"
+		got := Hashtags(text)
+		want := []string{"family", "work", "café"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+
+		replaced := ReplaceHashtag(text, "family", "home")
+		if Hashtags(replaced)[0] != "home" || len(Hashtags(replaced)) != 3 {
+			t.Errorf("Expected #family replaced throughout, got %q", replaced)
+		}
+		if got := ReplaceHashtag("busy at #work- again", "work", "job"); got != "busy at #job- again" {
+			t.Errorf("Expected trailing punctuation kept, got %q", got)
+		}
+
+		if !IsHashtag("self-care") || IsHashtag("Self") || IsHashtag("two words") || IsHashtag("") {
+			t.Error("Expected only lower-case single words to be hashtags")
+		}
+	})
+
+	t.Run("Links", func(t *testing.T) {
+		text := "See [[Rain]] and [[ rain |the poem]], [[answer:2024-03-01]], [[]] and [not a link]"
+		got := Links(text)
+		want := []string{"Rain", "answer:2024-03-01"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+
+		replaced := ReplaceLink(text, "rain", "Storm")
+		if !strings.Contains(replaced, "See [[Storm]] and [[Storm|the poem]]") {
+			t.Errorf("Expected both links to Rain replaced with their labels kept, got %q", replaced)
+		}
+	})
+}
@@ -9,6 +9,7 @@ import {
   CalendarDaysIcon,
   EyeIcon,
   HelpCircle,
+  PlusIcon,
 } from "lucide-react";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Button } from "@/components/ui/button";
@@ -19,14 +20,24 @@ import {
 } from "@/components/ui/popover";
 import { Calendar } from "@/components/ui/calendar";
 import { Badge } from "@/components/ui/badge";
+import { Input } from "@/components/ui/input";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from "@/components/ui/select";
 import { toast } from "sonner";
 import {
-  SaveCreativityEntry,
-  GetCreativityEntryByDate,
+  CreateCreativityEntry,
+  EditCreativityEntry,
+  GetCreativityEntriesByDate,
   GetAllCreativityEntries,
   GetCreativityStreak,
-  HasCreativityEntryForDate,
+  GetDailyCreativityPrompt,
 } from "../../wailsjs/go/backend/App";
+import { models } from "../../wailsjs/go/models";
 import { CreativityEntry } from "@/types";
 import WysiwygMarkdownEditor from "./questions/wysiwyg-markdown-editor";
 import {
@@ -35,10 +46,16 @@ import {
   TooltipProvider,
   TooltipTrigger,
 } from "@/components/ui/tooltip";
-import { CREATIVITY_PROMPTS } from "@/lib/creativity-prompts";
 import DeleteDialog from "@/components/reusable/delete-dialog";
 import ReactMarkdown from "react-markdown";
 
+const entryKinds = [
+  { value: "poem", label: "Poem" },
+  { value: "story", label: "Story" },
+  { value: "sketch_notes", label: "Sketch notes" },
+  { value: "list", label: "List" },
+];
+
 const CreativityJournal: React.FC = () => {
   const [selectedDate, setSelectedDate] = useState(new Date());
   const [content, setContent] = useState("");
@@ -51,6 +68,14 @@ const CreativityJournal: React.FC = () => {
   const [datesWithEntries, setDatesWithEntries] = useState<Date[]>([]);
   const [viewMode, setViewMode] = useState<"edit" | "view">("edit");
   const [dailyPrompt, setDailyPrompt] = useState("");
+  const [dailyPromptId, setDailyPromptId] = useState<number | null>(null);
+  // The prompt the entry responds to, once its template has been added
+  const [entryPromptId, setEntryPromptId] = useState<number | null>(null);
+  // The entries written on the selected date, and the one being edited
+  const [dayEntries, setDayEntries] = useState<CreativityEntry[]>([]);
+  const [entryId, setEntryId] = useState<number | null>(null);
+  const [title, setTitle] = useState("");
+  const [kind, setKind] = useState("");
 
   // Dialog states
   const [discardChangesDialogOpen, setDiscardChangesDialogOpen] =
@@ -58,51 +83,50 @@ const CreativityJournal: React.FC = () => {
   const [promptTemplateDialogOpen, setPromptTemplateDialogOpen] =
     useState(false);
   const [pendingDateChange, setPendingDateChange] = useState<Date | null>(null);
+  const [pendingEntry, setPendingEntry] = useState<
+    CreativityEntry | null | undefined
+  >(undefined);
 
   // Format a date object to YYYY-MM-DD string
   const formatDateString = (date: Date) => {
     return format(date, "yyyy-MM-dd");
   };
 
-  // Get prompt based on day of year
-  const getPromptForDate = (date: Date) => {
-    const dayOfYear = getDayOfYear(date);
-    const promptIndex = dayOfYear % CREATIVITY_PROMPTS.length;
-    return CREATIVITY_PROMPTS[promptIndex];
-  };
-
-  // Calculate day of year (1-365/366)
-  const getDayOfYear = (date: Date) => {
-    const start = new Date(date.getFullYear(), 0, 0);
-    const diff =
-      date.getTime() -
-      start.getTime() +
-      (start.getTimezoneOffset() - date.getTimezoneOffset()) * 60 * 1000;
-    const oneDay = 1000 * 60 * 60 * 24;
-    return Math.floor(diff / oneDay);
-  };
-
   // Update daily prompt when selected date changes
   useEffect(() => {
-    setDailyPrompt(getPromptForDate(selectedDate));
+    GetDailyCreativityPrompt(formatDateString(selectedDate))
+      .then((prompt) => {
+        setDailyPrompt(prompt.content);
+        setDailyPromptId(prompt.id);
+      })
+      .catch((error) => {
+        console.error("Error loading prompt:", error);
+        setDailyPrompt("");
+        setDailyPromptId(null);
+      });
   }, [selectedDate]);
 
-  // Load entry for the selected date
-  const loadEntryForDate = async (date: Date) => {
+  // Show an entry in the editor, or a blank one for a new entry
+  const selectEntry = (entry: CreativityEntry | null) => {
+    setEntryId(entry?.id ?? null);
+    setTitle(entry?.title ?? "");
+    setKind(entry?.kind ?? "");
+    setEntryPromptId(entry?.promptId ?? null);
+    setContent(entry?.content ?? "");
+    setOriginalContent(entry?.content ?? "");
+    setHasUnsavedChanges(false);
+    setIsDirty(false);
+  };
+
+  // Load the entries for the selected date, keeping selectedId in the editor
+  const loadEntryForDate = async (date: Date, selectedId?: number) => {
     try {
-      const dateStr = formatDateString(date);
-      const hasEntry = await HasCreativityEntryForDate(dateStr);
-
-      if (hasEntry) {
-        const entry = await GetCreativityEntryByDate(dateStr);
-        setContent(entry.content);
-        setOriginalContent(entry.content);
-        setIsDirty(false);
-      } else {
-        setContent("");
-        setOriginalContent("");
-        setIsDirty(false);
-      }
+      const entries =
+        (await GetCreativityEntriesByDate(formatDateString(date))) || [];
+      setDayEntries(entries);
+      selectEntry(
+        entries.find((entry) => entry.id === selectedId) ?? entries[0] ?? null
+      );
     } catch (error) {
       console.error("Error loading entry:", error);
       toast.error("Failed to load the journal entry for the selected date.");
@@ -110,7 +134,7 @@ const CreativityJournal: React.FC = () => {
   };
 
   // Load all entries and other data
-  const loadAllData = async () => {
+  const loadAllData = async (selectedId?: number) => {
     try {
       // Get all entries
       const entries = (await GetAllCreativityEntries()) || [];
@@ -132,8 +156,8 @@ const CreativityJournal: React.FC = () => {
       const currentStreak = await GetCreativityStreak();
       setStreak(currentStreak);
 
-      // Load the entry for the current selected date
-      await loadEntryForDate(selectedDate);
+      // Load the entries for the current selected date
+      await loadEntryForDate(selectedDate, selectedId);
     } catch (error) {
       console.error("Error loading data:", error);
       toast.error("Failed to load journal data.");
@@ -163,12 +187,27 @@ const CreativityJournal: React.FC = () => {
     }
   };
 
-  // Execute date change after confirmation
+  // Switch to another of the day's entries, or a new one
+  const handleEntryChange = (entry: CreativityEntry | null) => {
+    if (hasUnsavedChanges) {
+      setPendingEntry(entry);
+      setDiscardChangesDialogOpen(true);
+    } else {
+      selectEntry(entry);
+      setViewMode(entry ? "view" : "edit");
+    }
+  };
+
+  // Execute date or entry change after confirmation
   const confirmDateChange = async () => {
     if (pendingDateChange) {
       setSelectedDate(pendingDateChange);
       setHasUnsavedChanges(false);
       setPendingDateChange(null);
+    } else if (pendingEntry !== undefined) {
+      selectEntry(pendingEntry);
+      setViewMode(pendingEntry ? "view" : "edit");
+      setPendingEntry(undefined);
     }
   };
 
@@ -198,6 +237,19 @@ const CreativityJournal: React.FC = () => {
     setIsDirty(true);
   };
 
+  // Handle title and kind changes
+  const handleTitleChange = (value: string) => {
+    setTitle(value);
+    setHasUnsavedChanges(true);
+    setIsDirty(true);
+  };
+
+  const handleKindChange = (value: string) => {
+    setKind(value === "none" ? "" : value);
+    setHasUnsavedChanges(true);
+    setIsDirty(true);
+  };
+
   // Prepare to add prompt template
   const prepareAddPromptTemplate = () => {
     if (content) {
@@ -228,6 +280,7 @@ Reflections:
 `;
 
     setContent(promptTemplate);
+    setEntryPromptId(dailyPromptId);
     setHasUnsavedChanges(true);
     setIsDirty(true);
     setPromptTemplateDialogOpen(false);
@@ -237,11 +290,23 @@ Reflections:
   const saveEntry = async () => {
     try {
       setIsSaving(true);
-      const dateStr = formatDateString(selectedDate);
-      await SaveCreativityEntry(content, dateStr);
+      const entry = models.CreativityEntry.createFrom({
+        id: entryId ?? 0,
+        title,
+        kind,
+        content,
+        entryDate: formatDateString(selectedDate),
+        promptId: entryPromptId,
+      });
+      let savedId = entryId;
+      if (entryId) {
+        await EditCreativityEntry(entry);
+      } else {
+        savedId = (await CreateCreativityEntry(entry)).id;
+      }
 
       // Refresh data
-      await loadAllData();
+      await loadAllData(savedId ?? undefined);
 
       setHasUnsavedChanges(false);
       setIsDirty(false);
@@ -249,7 +314,7 @@ Reflections:
       setViewMode("view");
     } catch (error) {
       console.error("Error saving entry:", error);
-      toast.error("Failed to save the journal entry.");
+      toast.error(`Failed to save the journal entry: ${error}`);
     } finally {
       setIsSaving(false);
     }
@@ -257,7 +322,7 @@ Reflections:
 
   // Toggle between edit and view modes
   const toggleEditMode = () => {
-    setContent(originalContent);
+    selectEntry(dayEntries.find((entry) => entry.id === entryId) ?? null);
     if (viewMode === "view") {
       setViewMode("edit");
     } else {
@@ -352,10 +417,17 @@ Reflections:
                       )
                     }
                   >
-                    <div className="font-medium">
-                      {format(
-                        parse(entry.entryDate, "yyyy-MM-dd", new Date()),
-                        "MMM d, yyyy"
+                    <div>
+                      <div className="font-medium">
+                        {format(
+                          parse(entry.entryDate, "yyyy-MM-dd", new Date()),
+                          "MMM d, yyyy"
+                        )}
+                      </div>
+                      {entry.title && (
+                        <div className="text-xs text-muted-foreground">
+                          {entry.title}
+                        </div>
                       )}
                     </div>
                   </Button>
@@ -447,7 +519,57 @@ Reflections:
                 </Button>
               </div>
             </CardHeader>
-            <CardContent>
+            <CardContent className="space-y-4">
+              <div className="flex flex-wrap gap-2">
+                {dayEntries.map((entry, i) => (
+                  <Button
+                    key={entry.id}
+                    variant={entry.id === entryId ? "secondary" : "outline"}
+                    size="sm"
+                    onClick={() => handleEntryChange(entry)}
+                  >
+                    {entry.title || `Entry ${i + 1}`}
+                    <span className="ml-2 text-xs text-muted-foreground">
+                      {entry.wordCount} words
+                    </span>
+                  </Button>
+                ))}
+                {dayEntries.length > 0 && (
+                  <Button
+                    variant={entryId === null ? "secondary" : "ghost"}
+                    size="sm"
+                    className="flex items-center gap-1"
+                    onClick={() => handleEntryChange(null)}
+                  >
+                    <PlusIcon size={14} />
+                    New entry
+                  </Button>
+                )}
+              </div>
+
+              {viewMode === "edit" && (
+                <div className="flex gap-2">
+                  <Input
+                    value={title}
+                    onChange={(e) => handleTitleChange(e.target.value)}
+                    placeholder="Title (optional)"
+                  />
+                  <Select value={kind || "none"} onValueChange={handleKindChange}>
+                    <SelectTrigger className="w-44">
+                      <SelectValue placeholder="Kind" />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="none">No kind</SelectItem>
+                      {entryKinds.map((k) => (
+                        <SelectItem key={k.value} value={k.value}>
+                          {k.label}
+                        </SelectItem>
+                      ))}
+                    </SelectContent>
+                  </Select>
+                </div>
+              )}
+
               {viewMode === "edit" ? (
                 <WysiwygMarkdownEditor
                   value={content}
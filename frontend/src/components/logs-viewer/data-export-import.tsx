@@ -23,8 +23,9 @@ import {
   GetAllGratitudeEntries,
   AddGratitudeItem,
   GetAllCreativityEntries,
-  SaveCreativityEntry,
+  CreateCreativityEntry,
 } from "../../../wailsjs/go/backend/App";
+import { models } from "../../../wailsjs/go/models";
 import Papa from "papaparse";
 import { toast } from "sonner";
 
@@ -381,7 +382,14 @@ const DataExportImport: React.FC<ExportImportProps> = ({
     if (data.creativityEntries && data.creativityEntries.length > 0) {
       for (const entry of data.creativityEntries) {
         try {
-          await SaveCreativityEntry(entry.content, entry.entryDate);
+          await CreateCreativityEntry(
+            models.CreativityEntry.createFrom({
+              title: entry.title ?? "",
+              kind: entry.kind ?? "",
+              content: entry.content,
+              entryDate: entry.entryDate,
+            })
+          );
           importedCount++;
           setImportProgress(
             progress + Math.floor((importedCount / totalItems) * 50)
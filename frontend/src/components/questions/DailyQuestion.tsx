@@ -2,6 +2,8 @@ import { useState, useEffect } from "react";
 import { Answer, Question } from "../../types";
 import {
   GetRandomQuestion,
+  SkipQuestion,
+  SnoozeQuestion,
   CreateNewAnswer,
   GetAnswerHistoryByQuestionID,
   GetRecentAnswers,
@@ -17,12 +19,19 @@ import {
   CardTitle,
 } from "@/components/ui/card";
 import { Tabs, TabsContent, TabsList, TabsTrigger } from "@/components/ui/tabs";
+import {
+  DropdownMenu,
+  DropdownMenuContent,
+  DropdownMenuItem,
+  DropdownMenuTrigger,
+} from "@/components/ui/dropdown-menu";
 import {
   CalendarDays,
   Edit2,
   ArrowLeft,
   GitCompareArrows,
   RefreshCw,
+  SkipForward,
 } from "lucide-react";
 import ReactMarkdown from "react-markdown";
 import WysiwygMarkdownEditor from "./wysiwyg-markdown-editor";
@@ -127,6 +136,39 @@ export default function DailyQuestion() {
     }
   }
 
+  async function skipQuestion(reason: string) {
+    if (!question?.id) return;
+
+    try {
+      await SkipQuestion(question.id, reason);
+      await fetchRandomQuestion();
+    } catch (error) {
+      console.error("Error skipping question:", error);
+      toast.error("Failed to skip question");
+    }
+  }
+
+  async function snoozeQuestion(days: number) {
+    if (!question?.id) return;
+
+    const until = new Date();
+    until.setDate(until.getDate() + days);
+    const date = [
+      until.getFullYear(),
+      String(until.getMonth() + 1).padStart(2, "0"),
+      String(until.getDate()).padStart(2, "0"),
+    ].join("-");
+
+    try {
+      await SnoozeQuestion(question.id, date);
+      toast.success(`Snoozed until ${until.toLocaleDateString()}`);
+      await fetchRandomQuestion();
+    } catch (error) {
+      console.error("Error snoozing question:", error);
+      toast.error("Failed to snooze question");
+    }
+  }
+
   async function fetchAnswerHistory(questionId: number) {
     setIsLoadingHistory(true);
     try {
@@ -252,6 +294,34 @@ export default function DailyQuestion() {
               </CardDescription>
             </div>
             <div className="flex gap-2">
+              <DropdownMenu>
+                <DropdownMenuTrigger asChild>
+                  <Button
+                    variant="outline"
+                    size="sm"
+                    disabled={answeredToday || !question}
+                    className="flex items-center gap-2"
+                    title="Skip this question"
+                  >
+                    <SkipForward size={16} />
+                    Skip
+                  </Button>
+                </DropdownMenuTrigger>
+                <DropdownMenuContent align="end">
+                  <DropdownMenuItem onClick={() => skipQuestion("not_relevant")}>
+                    Not relevant to me
+                  </DropdownMenuItem>
+                  <DropdownMenuItem onClick={() => skipQuestion("answer_later")}>
+                    Answer later
+                  </DropdownMenuItem>
+                  <DropdownMenuItem onClick={() => skipQuestion("other")}>
+                    Other reason
+                  </DropdownMenuItem>
+                  <DropdownMenuItem onClick={() => snoozeQuestion(7)}>
+                    Snooze for a week
+                  </DropdownMenuItem>
+                </DropdownMenuContent>
+              </DropdownMenu>
               <Button
                 variant="outline"
                 size="sm"
@@ -4,13 +4,37 @@ import { LucideIcon } from "lucide-react";
 export interface Question {
   id: number;
   content: string;
+  template?: Template;
   createdAt: string;
 }
 
+export type FieldType = "text" | "list" | "scale" | "yesno";
+
+export interface TemplateField {
+  key: string;
+  label: string;
+  type: FieldType;
+  required: boolean;
+  count?: number;
+}
+
+export interface Template {
+  fields: TemplateField[];
+}
+
+export interface FieldValue {
+  key: string;
+  text?: string;
+  items?: string[];
+  scale?: number;
+  yes?: boolean;
+}
+
 export interface Answer {
   id: number;
   questionId: number;
   content: string;
+  fields?: FieldValue[];
   createdAt: string;
   updatedAt: string;
 }
@@ -132,8 +156,12 @@ export interface GratitudeEntry {
 
 export interface CreativityEntry {
   id: number;
+  title: string;
+  kind: string;
   content: string;
   entryDate: string;
+  promptId?: number | null;
+  wordCount: number;
   createdAt: string;
   updatedAt: string;
 }
@@ -1,31 +1,98 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {attachments} from '../models';
 import {models} from '../models';
+import {profiles} from '../models';
+import {ics} from '../models';
+import {packs} from '../models';
+import {reminders} from '../models';
+import {backend} from '../models';
+import {lansync} from '../models';
+import {importer} from '../models';
+import {foldersync} from '../models';
+
+export function AddAttachment(arg1:string,arg2:number,arg3:string):Promise<attachments.Attachment>;
+
+export function AddCreativityPrompt(arg1:string,arg2:string):Promise<models.CreativityPrompt>;
+
+export function AddGoalMilestone(arg1:number,arg2:string,arg3:string):Promise<models.GoalMilestone>;
 
 export function AddGratitudeItem(arg1:string):Promise<models.GratitudeItem>;
 
 export function AddQuestion(arg1:string):Promise<models.Question>;
 
+export function ArchiveCreativityPrompt(arg1:number,arg2:boolean):Promise<void>;
+
+export function ArchiveHabit(arg1:number,arg2:boolean):Promise<void>;
+
+export function CheckInGoal(arg1:number,arg2:number,arg3:string):Promise<models.GoalCheckIn>;
+
 export function CheckTodayAffirmation(arg1:number):Promise<boolean>;
 
+export function CommitDraft(arg1:number):Promise<models.Answer>;
+
+export function CompleteRoutineStep(arg1:number,arg2:string,arg3:number,arg4:string):Promise<models.RoutineStatus>;
+
 export function CountTodayGratitudeEntries():Promise<number>;
 
+export function CreateCreativityEntry(arg1:models.CreativityEntry):Promise<models.CreativityEntry>;
+
+export function CreateDiagnosticsBundle():Promise<string>;
+
+export function CreateGoal(arg1:models.Goal):Promise<models.Goal>;
+
+export function CreateHabit(arg1:models.Habit):Promise<models.Habit>;
+
 export function CreateNewAnswer(arg1:number,arg2:string):Promise<models.Answer>;
 
+export function CreateProfile(arg1:string,arg2:string):Promise<profiles.Profile>;
+
+export function CreateRoutine(arg1:models.Routine):Promise<models.Routine>;
+
+export function CreateStructuredAnswer(arg1:number,arg2:Array<models.FieldValue>):Promise<models.Answer>;
+
 export function DeleteAffirmation(arg1:number):Promise<void>;
 
 export function DeleteAffirmationLog(arg1:number):Promise<void>;
 
 export function DeleteAnswer(arg1:number):Promise<void>;
 
+export function DeleteAttachment(arg1:number):Promise<void>;
+
 export function DeleteCreativityEntry(arg1:number):Promise<void>;
 
+export function DeleteCreativityPrompt(arg1:number):Promise<void>;
+
+export function DeleteGoal(arg1:number):Promise<void>;
+
+export function DeleteGoalMilestone(arg1:number):Promise<void>;
+
 export function DeleteGratitudeItem(arg1:number):Promise<void>;
 
+export function DeleteHabit(arg1:number):Promise<void>;
+
+export function DeleteHabitLog(arg1:number,arg2:number):Promise<void>;
+
+export function DeleteProfile(arg1:string):Promise<void>;
+
 export function DeleteQuestion(arg1:number):Promise<void>;
 
+export function DeleteRoutine(arg1:number):Promise<void>;
+
+export function DiscardDraft(arg1:number):Promise<void>;
+
+export function EditCreativityEntry(arg1:models.CreativityEntry):Promise<void>;
+
+export function EndWritingSession(arg1:number):Promise<models.WritingSession>;
+
+export function ExportICS(arg1:string,arg2:ics.ExportOptions):Promise<void>;
+
+export function ExportQuestionPack(arg1:string,arg2:string):Promise<void>;
+
 export function GetActiveAffirmation():Promise<models.Affirmation>;
 
+export function GetActiveProfile():Promise<profiles.Profile>;
+
 export function GetAffirmationStreak():Promise<number>;
 
 export function GetAllAffirmationLogs():Promise<Array<models.AffirmationLog>>;
@@ -42,40 +109,192 @@ export function GetAllQuestions():Promise<Array<models.Question>>;
 
 export function GetAnswerHistoryByQuestionID(arg1:number):Promise<Array<models.AnswerHistory>>;
 
-export function GetCreativityEntryByDate(arg1:string):Promise<models.CreativityEntry>;
+export function GetBacklinks(arg1:string,arg2:number):Promise<Array<models.Backlink>>;
+
+export function GetBrokenLinks():Promise<Array<models.EntryLink>>;
+
+export function GetBuiltinQuestionPacks():Promise<Array<packs.Pack>>;
+
+export function GetCreativityEntriesByDate(arg1:string):Promise<Array<models.CreativityEntry>>;
+
+export function GetCreativityEntriesByPrompt(arg1:number):Promise<Array<models.CreativityEntry>>;
+
+export function GetCreativityOnePerDay():Promise<boolean>;
+
+export function GetCreativityPromptCategories():Promise<Array<string>>;
+
+export function GetCreativityPrompts(arg1:boolean):Promise<Array<models.CreativityPrompt>>;
 
 export function GetCreativityStreak():Promise<number>;
 
+export function GetDailyCreativityPrompt(arg1:string):Promise<models.CreativityPrompt>;
+
+export function GetDrafts():Promise<Array<models.Draft>>;
+
+export function GetEntriesByTag(arg1:string):Promise<Array<models.EntrySummary>>;
+
+export function GetEntryLinks(arg1:string,arg2:number):Promise<Array<models.EntryLink>>;
+
+export function GetEntryTags(arg1:string,arg2:number):Promise<Array<string>>;
+
+export function GetGoal(arg1:number):Promise<models.Goal>;
+
+export function GetGoalCheckIns(arg1:number):Promise<Array<models.GoalCheckIn>>;
+
+export function GetGoalTimeline(arg1:number):Promise<Array<models.GoalTimelineItem>>;
+
+export function GetGoals(arg1:string):Promise<Array<models.Goal>>;
+
 export function GetGratitudeItemsByDate(arg1:string):Promise<Array<models.GratitudeItem>>;
 
 export function GetGratitudeStreak():Promise<number>;
 
+export function GetHabitLogs(arg1:number,arg2:models.DateRange):Promise<Array<models.HabitLog>>;
+
+export function GetHabitStats(arg1:number,arg2:models.DateRange):Promise<models.HabitStats>;
+
+export function GetHabits(arg1:boolean):Promise<Array<models.Habit>>;
+
+export function GetImportFormats():Promise<Array<string>>;
+
 export function GetLastNDaysWithGratitude(arg1:number):Promise<Array<models.GratitudeEntry>>;
 
+export function GetLinkedGoals(arg1:string,arg2:number):Promise<Array<models.Goal>>;
+
+export function GetMostSkippedQuestions(arg1:number):Promise<Array<models.SkippedQuestion>>;
+
 export function GetQuestionById(arg1:number):Promise<models.Question>;
 
+export function GetQuestionPacks():Promise<Array<models.QuestionPack>>;
+
+export function GetQuestionStrategies():Promise<Array<string>>;
+
+export function GetQuestionStrategy():Promise<string>;
+
 export function GetRandomQuestion():Promise<models.Question>;
 
 export function GetRecentAnswers(arg1:number):Promise<Array<models.Answer>>;
 
+export function GetReminderRules():Promise<Array<reminders.Rule>>;
+
+export function GetRoutineStatus(arg1:string):Promise<Array<models.RoutineStatus>>;
+
+export function GetRoutines():Promise<Array<models.Routine>>;
+
+export function GetSeedSets():Promise<Array<string>>;
+
+export function GetSentimentTrend(arg1:string,arg2:string,arg3:string):Promise<models.SentimentTrend>;
+
+export function GetStartupStatus():Promise<backend.StartupStatus>;
+
+export function GetSyncFolder():Promise<string>;
+
+export function GetSyncPeers():Promise<Array<lansync.Peer>>;
+
+export function GetSyncStatus():Promise<backend.SyncStatus>;
+
+export function GetTagCloud(arg1:models.DateRange):Promise<Array<models.Tag>>;
+
+export function GetTags():Promise<Array<models.Tag>>;
+
 export function GetTodayGratitudeItems():Promise<Array<models.GratitudeItem>>;
 
+export function GetWritingStats(arg1:models.DateRange):Promise<models.WritingStats>;
+
 export function HasCreativityEntryForDate(arg1:string):Promise<boolean>;
 
 export function HasTodayGratitudeEntries():Promise<boolean>;
 
+export function InstallBuiltinQuestionPack(arg1:string):Promise<models.PackInstallResult>;
+
+export function InstallQuestionPack(arg1:string):Promise<models.PackInstallResult>;
+
+export function LinkToGoal(arg1:number,arg2:string,arg3:number):Promise<void>;
+
+export function ListAttachments(arg1:string,arg2:number):Promise<Array<attachments.Attachment>>;
+
+export function ListProfiles():Promise<Array<profiles.Profile>>;
+
 export function LogAffirmation(arg1:number):Promise<void>;
 
+export function LogHabit(arg1:number,arg2:string,arg3:any,arg4:string):Promise<models.HabitLog>;
+
+export function MergeTags(arg1:Array<string>,arg2:string):Promise<number>;
+
+export function PairSyncDevice(arg1:string,arg2:string):Promise<lansync.Peer>;
+
+export function PreviewImport(arg1:importer.Options):Promise<importer.Report>;
+
+export function RemoveSyncPeer(arg1:string):Promise<void>;
+
+export function RenameTag(arg1:string,arg2:string):Promise<number>;
+
+export function RunImport(arg1:importer.Options):Promise<importer.Report>;
+
 export function SaveAffirmation(arg1:string):Promise<models.Affirmation>;
 
 export function SaveCreativityEntry(arg1:string,arg2:string):Promise<models.CreativityEntry>;
 
+export function SaveCreativityEntryForPrompt(arg1:string,arg2:string,arg3:any):Promise<models.CreativityEntry>;
+
+export function SaveDraft(arg1:number,arg2:string):Promise<models.Draft>;
+
+export function SaveReminderRules(arg1:Array<reminders.Rule>):Promise<void>;
+
+export function SaveStructuredDraft(arg1:number,arg2:Array<models.FieldValue>):Promise<models.Draft>;
+
+export function SetCreativityOnePerDay(arg1:boolean):Promise<void>;
+
+export function SetGoalMilestoneDone(arg1:number,arg2:boolean):Promise<void>;
+
+export function SetGoalStatus(arg1:number,arg2:string):Promise<void>;
+
+export function SetQuestionStrategy(arg1:string):Promise<void>;
+
+export function SetQuestionTemplate(arg1:number,arg2:models.Template):Promise<void>;
+
+export function SetSyncFolder(arg1:string):Promise<void>;
+
+export function SkipQuestion(arg1:number,arg2:string):Promise<models.QuestionSkip>;
+
+export function SnoozeQuestion(arg1:number,arg2:string):Promise<models.QuestionSkip>;
+
+export function StartRoutine(arg1:number):Promise<models.RoutineRun>;
+
+export function StartSyncPairing():Promise<lansync.PairingCode>;
+
+export function StartSyncServer(arg1:number):Promise<backend.SyncStatus>;
+
+export function StartWritingSession(arg1:string):Promise<models.WritingSession>;
+
+export function StopSyncServer():Promise<void>;
+
+export function SwitchProfile(arg1:string):Promise<void>;
+
+export function SyncFolderNow():Promise<foldersync.Result>;
+
+export function SyncWithDevice(arg1:string):Promise<lansync.SyncResult>;
+
+export function UninstallQuestionPack(arg1:string):Promise<models.PackUninstallResult>;
+
+export function UnlinkFromGoal(arg1:number,arg2:string,arg3:number):Promise<void>;
+
 export function UpdateAffirmation(arg1:number,arg2:string):Promise<void>;
 
 export function UpdateAnswer(arg1:number,arg2:string):Promise<void>;
 
 export function UpdateCreativityEntry(arg1:number,arg2:string):Promise<void>;
 
+export function UpdateCreativityPrompt(arg1:number,arg2:string,arg3:string):Promise<void>;
+
+export function UpdateGoal(arg1:models.Goal):Promise<void>;
+
 export function UpdateGratitudeItem(arg1:number,arg2:string):Promise<void>;
 
+export function UpdateHabit(arg1:models.Habit):Promise<void>;
+
 export function UpdateQuestion(arg1:number,arg2:string):Promise<void>;
+
+export function UpdateRoutine(arg1:models.Routine):Promise<void>;
+
+export function UpdateStructuredAnswer(arg1:number,arg2:Array<models.FieldValue>):Promise<void>;
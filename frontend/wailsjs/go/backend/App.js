@@ -2,6 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddAttachment(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['AddAttachment'](arg1, arg2, arg3);
+}
+
+export function AddCreativityPrompt(arg1, arg2) {
+  return window['go']['backend']['App']['AddCreativityPrompt'](arg1, arg2);
+}
+
+export function AddGoalMilestone(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['AddGoalMilestone'](arg1, arg2, arg3);
+}
+
 export function AddGratitudeItem(arg1) {
   return window['go']['backend']['App']['AddGratitudeItem'](arg1);
 }
@@ -10,18 +22,66 @@ export function AddQuestion(arg1) {
   return window['go']['backend']['App']['AddQuestion'](arg1);
 }
 
+export function ArchiveCreativityPrompt(arg1, arg2) {
+  return window['go']['backend']['App']['ArchiveCreativityPrompt'](arg1, arg2);
+}
+
+export function ArchiveHabit(arg1, arg2) {
+  return window['go']['backend']['App']['ArchiveHabit'](arg1, arg2);
+}
+
+export function CheckInGoal(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['CheckInGoal'](arg1, arg2, arg3);
+}
+
 export function CheckTodayAffirmation(arg1) {
   return window['go']['backend']['App']['CheckTodayAffirmation'](arg1);
 }
 
+export function CommitDraft(arg1) {
+  return window['go']['backend']['App']['CommitDraft'](arg1);
+}
+
+export function CompleteRoutineStep(arg1, arg2, arg3, arg4) {
+  return window['go']['backend']['App']['CompleteRoutineStep'](arg1, arg2, arg3, arg4);
+}
+
 export function CountTodayGratitudeEntries() {
   return window['go']['backend']['App']['CountTodayGratitudeEntries']();
 }
 
+export function CreateCreativityEntry(arg1) {
+  return window['go']['backend']['App']['CreateCreativityEntry'](arg1);
+}
+
+export function CreateDiagnosticsBundle() {
+  return window['go']['backend']['App']['CreateDiagnosticsBundle']();
+}
+
+export function CreateGoal(arg1) {
+  return window['go']['backend']['App']['CreateGoal'](arg1);
+}
+
+export function CreateHabit(arg1) {
+  return window['go']['backend']['App']['CreateHabit'](arg1);
+}
+
 export function CreateNewAnswer(arg1, arg2) {
   return window['go']['backend']['App']['CreateNewAnswer'](arg1, arg2);
 }
 
+export function CreateProfile(arg1, arg2) {
+  return window['go']['backend']['App']['CreateProfile'](arg1, arg2);
+}
+
+export function CreateRoutine(arg1) {
+  return window['go']['backend']['App']['CreateRoutine'](arg1);
+}
+
+export function CreateStructuredAnswer(arg1, arg2) {
+  return window['go']['backend']['App']['CreateStructuredAnswer'](arg1, arg2);
+}
+
 export function DeleteAffirmation(arg1) {
   return window['go']['backend']['App']['DeleteAffirmation'](arg1);
 }
@@ -34,22 +94,78 @@ export function DeleteAnswer(arg1) {
   return window['go']['backend']['App']['DeleteAnswer'](arg1);
 }
 
+export function DeleteAttachment(arg1) {
+  return window['go']['backend']['App']['DeleteAttachment'](arg1);
+}
+
 export function DeleteCreativityEntry(arg1) {
   return window['go']['backend']['App']['DeleteCreativityEntry'](arg1);
 }
 
+export function DeleteCreativityPrompt(arg1) {
+  return window['go']['backend']['App']['DeleteCreativityPrompt'](arg1);
+}
+
+export function DeleteGoal(arg1) {
+  return window['go']['backend']['App']['DeleteGoal'](arg1);
+}
+
+export function DeleteGoalMilestone(arg1) {
+  return window['go']['backend']['App']['DeleteGoalMilestone'](arg1);
+}
+
 export function DeleteGratitudeItem(arg1) {
   return window['go']['backend']['App']['DeleteGratitudeItem'](arg1);
 }
 
+export function DeleteHabit(arg1) {
+  return window['go']['backend']['App']['DeleteHabit'](arg1);
+}
+
+export function DeleteHabitLog(arg1, arg2) {
+  return window['go']['backend']['App']['DeleteHabitLog'](arg1, arg2);
+}
+
+export function DeleteProfile(arg1) {
+  return window['go']['backend']['App']['DeleteProfile'](arg1);
+}
+
 export function DeleteQuestion(arg1) {
   return window['go']['backend']['App']['DeleteQuestion'](arg1);
 }
 
+export function DeleteRoutine(arg1) {
+  return window['go']['backend']['App']['DeleteRoutine'](arg1);
+}
+
+export function DiscardDraft(arg1) {
+  return window['go']['backend']['App']['DiscardDraft'](arg1);
+}
+
+export function EditCreativityEntry(arg1) {
+  return window['go']['backend']['App']['EditCreativityEntry'](arg1);
+}
+
+export function EndWritingSession(arg1) {
+  return window['go']['backend']['App']['EndWritingSession'](arg1);
+}
+
+export function ExportICS(arg1, arg2) {
+  return window['go']['backend']['App']['ExportICS'](arg1, arg2);
+}
+
+export function ExportQuestionPack(arg1, arg2) {
+  return window['go']['backend']['App']['ExportQuestionPack'](arg1, arg2);
+}
+
 export function GetActiveAffirmation() {
   return window['go']['backend']['App']['GetActiveAffirmation']();
 }
 
+export function GetActiveProfile() {
+  return window['go']['backend']['App']['GetActiveProfile']();
+}
+
 export function GetAffirmationStreak() {
   return window['go']['backend']['App']['GetAffirmationStreak']();
 }
@@ -82,14 +198,78 @@ export function GetAnswerHistoryByQuestionID(arg1) {
   return window['go']['backend']['App']['GetAnswerHistoryByQuestionID'](arg1);
 }
 
-export function GetCreativityEntryByDate(arg1) {
-  return window['go']['backend']['App']['GetCreativityEntryByDate'](arg1);
+export function GetBacklinks(arg1, arg2) {
+  return window['go']['backend']['App']['GetBacklinks'](arg1, arg2);
+}
+
+export function GetBrokenLinks() {
+  return window['go']['backend']['App']['GetBrokenLinks']();
+}
+
+export function GetBuiltinQuestionPacks() {
+  return window['go']['backend']['App']['GetBuiltinQuestionPacks']();
+}
+
+export function GetCreativityEntriesByDate(arg1) {
+  return window['go']['backend']['App']['GetCreativityEntriesByDate'](arg1);
+}
+
+export function GetCreativityEntriesByPrompt(arg1) {
+  return window['go']['backend']['App']['GetCreativityEntriesByPrompt'](arg1);
+}
+
+export function GetCreativityOnePerDay() {
+  return window['go']['backend']['App']['GetCreativityOnePerDay']();
+}
+
+export function GetCreativityPromptCategories() {
+  return window['go']['backend']['App']['GetCreativityPromptCategories']();
+}
+
+export function GetCreativityPrompts(arg1) {
+  return window['go']['backend']['App']['GetCreativityPrompts'](arg1);
 }
 
 export function GetCreativityStreak() {
   return window['go']['backend']['App']['GetCreativityStreak']();
 }
 
+export function GetDailyCreativityPrompt(arg1) {
+  return window['go']['backend']['App']['GetDailyCreativityPrompt'](arg1);
+}
+
+export function GetDrafts() {
+  return window['go']['backend']['App']['GetDrafts']();
+}
+
+export function GetEntriesByTag(arg1) {
+  return window['go']['backend']['App']['GetEntriesByTag'](arg1);
+}
+
+export function GetEntryLinks(arg1, arg2) {
+  return window['go']['backend']['App']['GetEntryLinks'](arg1, arg2);
+}
+
+export function GetEntryTags(arg1, arg2) {
+  return window['go']['backend']['App']['GetEntryTags'](arg1, arg2);
+}
+
+export function GetGoal(arg1) {
+  return window['go']['backend']['App']['GetGoal'](arg1);
+}
+
+export function GetGoalCheckIns(arg1) {
+  return window['go']['backend']['App']['GetGoalCheckIns'](arg1);
+}
+
+export function GetGoalTimeline(arg1) {
+  return window['go']['backend']['App']['GetGoalTimeline'](arg1);
+}
+
+export function GetGoals(arg1) {
+  return window['go']['backend']['App']['GetGoals'](arg1);
+}
+
 export function GetGratitudeItemsByDate(arg1) {
   return window['go']['backend']['App']['GetGratitudeItemsByDate'](arg1);
 }
@@ -98,14 +278,50 @@ export function GetGratitudeStreak() {
   return window['go']['backend']['App']['GetGratitudeStreak']();
 }
 
+export function GetHabitLogs(arg1, arg2) {
+  return window['go']['backend']['App']['GetHabitLogs'](arg1, arg2);
+}
+
+export function GetHabitStats(arg1, arg2) {
+  return window['go']['backend']['App']['GetHabitStats'](arg1, arg2);
+}
+
+export function GetHabits(arg1) {
+  return window['go']['backend']['App']['GetHabits'](arg1);
+}
+
+export function GetImportFormats() {
+  return window['go']['backend']['App']['GetImportFormats']();
+}
+
 export function GetLastNDaysWithGratitude(arg1) {
   return window['go']['backend']['App']['GetLastNDaysWithGratitude'](arg1);
 }
 
+export function GetLinkedGoals(arg1, arg2) {
+  return window['go']['backend']['App']['GetLinkedGoals'](arg1, arg2);
+}
+
+export function GetMostSkippedQuestions(arg1) {
+  return window['go']['backend']['App']['GetMostSkippedQuestions'](arg1);
+}
+
 export function GetQuestionById(arg1) {
   return window['go']['backend']['App']['GetQuestionById'](arg1);
 }
 
+export function GetQuestionPacks() {
+  return window['go']['backend']['App']['GetQuestionPacks']();
+}
+
+export function GetQuestionStrategies() {
+  return window['go']['backend']['App']['GetQuestionStrategies']();
+}
+
+export function GetQuestionStrategy() {
+  return window['go']['backend']['App']['GetQuestionStrategy']();
+}
+
 export function GetRandomQuestion() {
   return window['go']['backend']['App']['GetRandomQuestion']();
 }
@@ -114,10 +330,58 @@ export function GetRecentAnswers(arg1) {
   return window['go']['backend']['App']['GetRecentAnswers'](arg1);
 }
 
+export function GetReminderRules() {
+  return window['go']['backend']['App']['GetReminderRules']();
+}
+
+export function GetRoutineStatus(arg1) {
+  return window['go']['backend']['App']['GetRoutineStatus'](arg1);
+}
+
+export function GetRoutines() {
+  return window['go']['backend']['App']['GetRoutines']();
+}
+
+export function GetSeedSets() {
+  return window['go']['backend']['App']['GetSeedSets']();
+}
+
+export function GetSentimentTrend(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['GetSentimentTrend'](arg1, arg2, arg3);
+}
+
+export function GetStartupStatus() {
+  return window['go']['backend']['App']['GetStartupStatus']();
+}
+
+export function GetSyncFolder() {
+  return window['go']['backend']['App']['GetSyncFolder']();
+}
+
+export function GetSyncPeers() {
+  return window['go']['backend']['App']['GetSyncPeers']();
+}
+
+export function GetSyncStatus() {
+  return window['go']['backend']['App']['GetSyncStatus']();
+}
+
+export function GetTagCloud(arg1) {
+  return window['go']['backend']['App']['GetTagCloud'](arg1);
+}
+
+export function GetTags() {
+  return window['go']['backend']['App']['GetTags']();
+}
+
 export function GetTodayGratitudeItems() {
   return window['go']['backend']['App']['GetTodayGratitudeItems']();
 }
 
+export function GetWritingStats(arg1) {
+  return window['go']['backend']['App']['GetWritingStats'](arg1);
+}
+
 export function HasCreativityEntryForDate(arg1) {
   return window['go']['backend']['App']['HasCreativityEntryForDate'](arg1);
 }
@@ -126,10 +390,58 @@ export function HasTodayGratitudeEntries() {
   return window['go']['backend']['App']['HasTodayGratitudeEntries']();
 }
 
+export function InstallBuiltinQuestionPack(arg1) {
+  return window['go']['backend']['App']['InstallBuiltinQuestionPack'](arg1);
+}
+
+export function InstallQuestionPack(arg1) {
+  return window['go']['backend']['App']['InstallQuestionPack'](arg1);
+}
+
+export function LinkToGoal(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['LinkToGoal'](arg1, arg2, arg3);
+}
+
+export function ListAttachments(arg1, arg2) {
+  return window['go']['backend']['App']['ListAttachments'](arg1, arg2);
+}
+
+export function ListProfiles() {
+  return window['go']['backend']['App']['ListProfiles']();
+}
+
 export function LogAffirmation(arg1) {
   return window['go']['backend']['App']['LogAffirmation'](arg1);
 }
 
+export function LogHabit(arg1, arg2, arg3, arg4) {
+  return window['go']['backend']['App']['LogHabit'](arg1, arg2, arg3, arg4);
+}
+
+export function MergeTags(arg1, arg2) {
+  return window['go']['backend']['App']['MergeTags'](arg1, arg2);
+}
+
+export function PairSyncDevice(arg1, arg2) {
+  return window['go']['backend']['App']['PairSyncDevice'](arg1, arg2);
+}
+
+export function PreviewImport(arg1) {
+  return window['go']['backend']['App']['PreviewImport'](arg1);
+}
+
+export function RemoveSyncPeer(arg1) {
+  return window['go']['backend']['App']['RemoveSyncPeer'](arg1);
+}
+
+export function RenameTag(arg1, arg2) {
+  return window['go']['backend']['App']['RenameTag'](arg1, arg2);
+}
+
+export function RunImport(arg1) {
+  return window['go']['backend']['App']['RunImport'](arg1);
+}
+
 export function SaveAffirmation(arg1) {
   return window['go']['backend']['App']['SaveAffirmation'](arg1);
 }
@@ -138,6 +450,94 @@ export function SaveCreativityEntry(arg1, arg2) {
   return window['go']['backend']['App']['SaveCreativityEntry'](arg1, arg2);
 }
 
+export function SaveCreativityEntryForPrompt(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['SaveCreativityEntryForPrompt'](arg1, arg2, arg3);
+}
+
+export function SaveDraft(arg1, arg2) {
+  return window['go']['backend']['App']['SaveDraft'](arg1, arg2);
+}
+
+export function SaveReminderRules(arg1) {
+  return window['go']['backend']['App']['SaveReminderRules'](arg1);
+}
+
+export function SaveStructuredDraft(arg1, arg2) {
+  return window['go']['backend']['App']['SaveStructuredDraft'](arg1, arg2);
+}
+
+export function SetCreativityOnePerDay(arg1) {
+  return window['go']['backend']['App']['SetCreativityOnePerDay'](arg1);
+}
+
+export function SetGoalMilestoneDone(arg1, arg2) {
+  return window['go']['backend']['App']['SetGoalMilestoneDone'](arg1, arg2);
+}
+
+export function SetGoalStatus(arg1, arg2) {
+  return window['go']['backend']['App']['SetGoalStatus'](arg1, arg2);
+}
+
+export function SetQuestionStrategy(arg1) {
+  return window['go']['backend']['App']['SetQuestionStrategy'](arg1);
+}
+
+export function SetQuestionTemplate(arg1, arg2) {
+  return window['go']['backend']['App']['SetQuestionTemplate'](arg1, arg2);
+}
+
+export function SetSyncFolder(arg1) {
+  return window['go']['backend']['App']['SetSyncFolder'](arg1);
+}
+
+export function SkipQuestion(arg1, arg2) {
+  return window['go']['backend']['App']['SkipQuestion'](arg1, arg2);
+}
+
+export function SnoozeQuestion(arg1, arg2) {
+  return window['go']['backend']['App']['SnoozeQuestion'](arg1, arg2);
+}
+
+export function StartRoutine(arg1) {
+  return window['go']['backend']['App']['StartRoutine'](arg1);
+}
+
+export function StartSyncPairing() {
+  return window['go']['backend']['App']['StartSyncPairing']();
+}
+
+export function StartSyncServer(arg1) {
+  return window['go']['backend']['App']['StartSyncServer'](arg1);
+}
+
+export function StartWritingSession(arg1) {
+  return window['go']['backend']['App']['StartWritingSession'](arg1);
+}
+
+export function StopSyncServer() {
+  return window['go']['backend']['App']['StopSyncServer']();
+}
+
+export function SwitchProfile(arg1) {
+  return window['go']['backend']['App']['SwitchProfile'](arg1);
+}
+
+export function SyncFolderNow() {
+  return window['go']['backend']['App']['SyncFolderNow']();
+}
+
+export function SyncWithDevice(arg1) {
+  return window['go']['backend']['App']['SyncWithDevice'](arg1);
+}
+
+export function UninstallQuestionPack(arg1) {
+  return window['go']['backend']['App']['UninstallQuestionPack'](arg1);
+}
+
+export function UnlinkFromGoal(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['UnlinkFromGoal'](arg1, arg2, arg3);
+}
+
 export function UpdateAffirmation(arg1, arg2) {
   return window['go']['backend']['App']['UpdateAffirmation'](arg1, arg2);
 }
@@ -150,10 +550,30 @@ export function UpdateCreativityEntry(arg1, arg2) {
   return window['go']['backend']['App']['UpdateCreativityEntry'](arg1, arg2);
 }
 
+export function UpdateCreativityPrompt(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['UpdateCreativityPrompt'](arg1, arg2, arg3);
+}
+
+export function UpdateGoal(arg1) {
+  return window['go']['backend']['App']['UpdateGoal'](arg1);
+}
+
 export function UpdateGratitudeItem(arg1, arg2) {
   return window['go']['backend']['App']['UpdateGratitudeItem'](arg1, arg2);
 }
 
+export function UpdateHabit(arg1) {
+  return window['go']['backend']['App']['UpdateHabit'](arg1);
+}
+
 export function UpdateQuestion(arg1, arg2) {
   return window['go']['backend']['App']['UpdateQuestion'](arg1, arg2);
 }
+
+export function UpdateRoutine(arg1) {
+  return window['go']['backend']['App']['UpdateRoutine'](arg1);
+}
+
+export function UpdateStructuredAnswer(arg1, arg2) {
+  return window['go']['backend']['App']['UpdateStructuredAnswer'](arg1, arg2);
+}
@@ -1,23 +1,1437 @@
+export namespace attachments {
+	
+	export class Attachment {
+	    id: number;
+	    uuid: string;
+	    entryType: string;
+	    entryId: number;
+	    name: string;
+	    hash: string;
+	    mimeType: string;
+	    size: number;
+	    width: number;
+	    height: number;
+	    path: string;
+	    thumbnail: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Attachment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.entryType = source["entryType"];
+	        this.entryId = source["entryId"];
+	        this.name = source["name"];
+	        this.hash = source["hash"];
+	        this.mimeType = source["mimeType"];
+	        this.size = source["size"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.path = source["path"];
+	        this.thumbnail = source["thumbnail"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace backend {
+	
+	export class StartupError {
+	    stage: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stage = source["stage"];
+	        this.message = source["message"];
+	    }
+	}
+	export class StartupStatus {
+	    ready: boolean;
+	    error?: StartupError;
+	    dataDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ready = source["ready"];
+	        this.error = this.convertValues(source["error"], StartupError);
+	        this.dataDir = source["dataDir"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SyncStatus {
+	    running: boolean;
+	    port: number;
+	    deviceId: string;
+	    name: string;
+	    addresses: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.port = source["port"];
+	        this.deviceId = source["deviceId"];
+	        this.name = source["name"];
+	        this.addresses = source["addresses"];
+	    }
+	}
+
+}
+
+export namespace foldersync {
+	
+	export class Result {
+	    exported: number;
+	    imported: number;
+	    devices: number;
+	    conflicts: replication.Conflict[];
+	    // Go type: time
+	    at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.exported = source["exported"];
+	        this.imported = source["imported"];
+	        this.devices = source["devices"];
+	        this.conflicts = this.convertValues(source["conflicts"], replication.Conflict);
+	        this.at = this.convertValues(source["at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace ics {
+	
+	export class ExportOptions {
+	    types: string[];
+	    from: string;
+	    to: string;
+	    includeContent: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.types = source["types"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.includeContent = source["includeContent"];
+	    }
+	}
+
+}
+
+export namespace importer {
+	
+	export class CSVMapping {
+	    dateColumn: string;
+	    contentColumn: string;
+	    questionColumn: string;
+	    dateFormat: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVMapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dateColumn = source["dateColumn"];
+	        this.contentColumn = source["contentColumn"];
+	        this.questionColumn = source["questionColumn"];
+	        this.dateFormat = source["dateFormat"];
+	    }
+	}
+	export class Options {
+	    format: string;
+	    path: string;
+	    target: string;
+	    defaultQuestion: string;
+	    csv: CSVMapping;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.path = source["path"];
+	        this.target = source["target"];
+	        this.defaultQuestion = source["defaultQuestion"];
+	        this.csv = this.convertValues(source["csv"], CSVMapping);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlannedItem {
+	    target: string;
+	    date: string;
+	    question?: string;
+	    preview: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlannedItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.date = source["date"];
+	        this.question = source["question"];
+	        this.preview = source["preview"];
+	    }
+	}
+	export class SkippedRecord {
+	    index: number;
+	    date: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkippedRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.date = source["date"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class Report {
+	    source: string;
+	    dryRun: boolean;
+	    total: number;
+	    answers: number;
+	    creativity: number;
+	    newQuestions: string[];
+	    items: PlannedItem[];
+	    skipped: SkippedRecord[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.dryRun = source["dryRun"];
+	        this.total = source["total"];
+	        this.answers = source["answers"];
+	        this.creativity = source["creativity"];
+	        this.newQuestions = source["newQuestions"];
+	        this.items = this.convertValues(source["items"], PlannedItem);
+	        this.skipped = this.convertValues(source["skipped"], SkippedRecord);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace lansync {
+	
+	export class PairingCode {
+	    code: string;
+	    // Go type: time
+	    expiresAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PairingCode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.expiresAt = this.convertValues(source["expiresAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Peer {
+	    deviceId: string;
+	    name: string;
+	    address: string;
+	    sentSeq: number;
+	    receivedSeq: number;
+	    // Go type: time
+	    pairedAt: any;
+	    // Go type: time
+	    lastSyncAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Peer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.name = source["name"];
+	        this.address = source["address"];
+	        this.sentSeq = source["sentSeq"];
+	        this.receivedSeq = source["receivedSeq"];
+	        this.pairedAt = this.convertValues(source["pairedAt"], null);
+	        this.lastSyncAt = this.convertValues(source["lastSyncAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SyncResult {
+	    deviceId: string;
+	    received: number;
+	    sent: number;
+	    conflicts: replication.Conflict[];
+	    // Go type: time
+	    at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deviceId = source["deviceId"];
+	        this.received = source["received"];
+	        this.sent = source["sent"];
+	        this.conflicts = this.convertValues(source["conflicts"], replication.Conflict);
+	        this.at = this.convertValues(source["at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace models {
 	
-	export class Affirmation {
+	export class Affirmation {
+	    id: number;
+	    uuid: string;
+	    content: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Affirmation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.content = source["content"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AffirmationLog {
+	    id: number;
+	    uuid: string;
+	    affirmationId: number;
+	    // Go type: time
+	    completedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AffirmationLog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.affirmationId = source["affirmationId"];
+	        this.completedAt = this.convertValues(source["completedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FieldValue {
+	    key: string;
+	    text?: string;
+	    items?: string[];
+	    scale?: number;
+	    yes?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.text = source["text"];
+	        this.items = source["items"];
+	        this.scale = source["scale"];
+	        this.yes = source["yes"];
+	    }
+	}
+	export class Answer {
+	    id: number;
+	    uuid: string;
+	    questionId: number;
+	    content: string;
+	    fields?: FieldValue[];
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Answer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.questionId = source["questionId"];
+	        this.content = source["content"];
+	        this.fields = this.convertValues(source["fields"], FieldValue);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnswerHistory {
+	    id: number;
+	    uuid: string;
+	    questionId: number;
+	    content: string;
+	    fields?: FieldValue[];
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnswerHistory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.questionId = source["questionId"];
+	        this.content = source["content"];
+	        this.fields = this.convertValues(source["fields"], FieldValue);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EntrySummary {
+	    type: string;
+	    id: number;
+	    uuid: string;
+	    date: string;
+	    // Go type: time
+	    at: any;
+	    title: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EntrySummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.date = source["date"];
+	        this.at = this.convertValues(source["at"], null);
+	        this.title = source["title"];
+	        this.content = source["content"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Backlink {
+	    entry: EntrySummary;
+	    ref: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Backlink(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = this.convertValues(source["entry"], EntrySummary);
+	        this.ref = source["ref"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CreativityEntry {
+	    id: number;
+	    uuid: string;
+	    title: string;
+	    kind: string;
+	    content: string;
+	    entryDate: string;
+	    promptId?: number;
+	    wordCount: number;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreativityEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.title = source["title"];
+	        this.kind = source["kind"];
+	        this.content = source["content"];
+	        this.entryDate = source["entryDate"];
+	        this.promptId = source["promptId"];
+	        this.wordCount = source["wordCount"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CreativityPrompt {
+	    id: number;
+	    uuid: string;
+	    content: string;
+	    category: string;
+	    builtin: boolean;
+	    archived: boolean;
+	    entries: number;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreativityPrompt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.content = source["content"];
+	        this.category = source["category"];
+	        this.builtin = source["builtin"];
+	        this.archived = source["archived"];
+	        this.entries = source["entries"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DateRange {
+	    from: string;
+	    to: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DateRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class Draft {
+	    id: number;
+	    questionId: number;
+	    content: string;
+	    fields: FieldValue[];
+	    draftDate: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Draft(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.questionId = source["questionId"];
+	        this.content = source["content"];
+	        this.fields = this.convertValues(source["fields"], FieldValue);
+	        this.draftDate = source["draftDate"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EntryLink {
+	    id: number;
+	    sourceType: string;
+	    sourceId: number;
+	    ref: string;
+	    target?: EntrySummary;
+	
+	    static createFrom(source: any = {}) {
+	        return new EntryLink(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sourceType = source["sourceType"];
+	        this.sourceId = source["sourceId"];
+	        this.ref = source["ref"];
+	        this.target = this.convertValues(source["target"], EntrySummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class GoalMilestone {
+	    id: number;
+	    uuid: string;
+	    goalId: number;
+	    title: string;
+	    targetDate: string;
+	    position: number;
+	    // Go type: time
+	    completedAt?: any;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GoalMilestone(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.goalId = source["goalId"];
+	        this.title = source["title"];
+	        this.targetDate = source["targetDate"];
+	        this.position = source["position"];
+	        this.completedAt = this.convertValues(source["completedAt"], null);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Goal {
+	    id: number;
+	    uuid: string;
+	    title: string;
+	    why: string;
+	    targetDate: string;
+	    status: string;
+	    // Go type: time
+	    completedAt?: any;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	    milestones: GoalMilestone[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Goal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.title = source["title"];
+	        this.why = source["why"];
+	        this.targetDate = source["targetDate"];
+	        this.status = source["status"];
+	        this.completedAt = this.convertValues(source["completedAt"], null);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.milestones = this.convertValues(source["milestones"], GoalMilestone);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GoalCheckIn {
+	    id: number;
+	    uuid: string;
+	    goalId: number;
+	    week: string;
+	    progress: number;
+	    note: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GoalCheckIn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.goalId = source["goalId"];
+	        this.week = source["week"];
+	        this.progress = source["progress"];
+	        this.note = source["note"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class GoalTimelineItem {
+	    type: string;
+	    id: number;
+	    date: string;
+	    // Go type: time
+	    at: any;
+	    title: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GoalTimelineItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.id = source["id"];
+	        this.date = source["date"];
+	        this.at = this.convertValues(source["at"], null);
+	        this.title = source["title"];
+	        this.content = source["content"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GratitudeItem {
+	    id: number;
+	    uuid: string;
+	    content: string;
+	    entryDate: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GratitudeItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.content = source["content"];
+	        this.entryDate = source["entryDate"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GratitudeEntry {
+	    date: string;
+	    items: GratitudeItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GratitudeEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.items = this.convertValues(source["items"], GratitudeItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Habit {
+	    id: number;
+	    uuid: string;
+	    builtin: string;
+	    name: string;
+	    frequency: string;
+	    target: number;
+	    unit: string;
+	    archived: boolean;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Habit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.builtin = source["builtin"];
+	        this.name = source["name"];
+	        this.frequency = source["frequency"];
+	        this.target = source["target"];
+	        this.unit = source["unit"];
+	        this.archived = source["archived"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HabitLog {
+	    id: number;
+	    uuid: string;
+	    habitId: number;
+	    date: string;
+	    quantity?: number;
+	    note: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new HabitLog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.uuid = source["uuid"];
+	        this.habitId = source["habitId"];
+	        this.date = source["date"];
+	        this.quantity = source["quantity"];
+	        this.note = source["note"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HabitStats {
+	    habitId: number;
+	    range: DateRange;
+	    currentStreak: number;
+	    longestStreak: number;
+	    completions: number;
+	    quantity: number;
+	    periods: number;
+	    periodsMet: number;
+	    completionRate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HabitStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.habitId = source["habitId"];
+	        this.range = this.convertValues(source["range"], DateRange);
+	        this.currentStreak = source["currentStreak"];
+	        this.longestStreak = source["longestStreak"];
+	        this.completions = source["completions"];
+	        this.quantity = source["quantity"];
+	        this.periods = source["periods"];
+	        this.periodsMet = source["periodsMet"];
+	        this.completionRate = source["completionRate"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LongestEntry {
+	    type: string;
+	    id: number;
+	    date: string;
+	    words: number;
+	    preview: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LongestEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.id = source["id"];
+	        this.date = source["date"];
+	        this.words = source["words"];
+	        this.preview = source["preview"];
+	    }
+	}
+	export class PackInstallResult {
+	    packId: string;
+	    added: number;
+	    skipped: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PackInstallResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.packId = source["packId"];
+	        this.added = source["added"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class PackUninstallResult {
+	    packId: string;
+	    removed: number;
+	    kept: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PackUninstallResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.packId = source["packId"];
+	        this.removed = source["removed"];
+	        this.kept = source["kept"];
+	    }
+	}
+	export class TemplateField {
+	    key: string;
+	    label: string;
+	    type: string;
+	    required: boolean;
+	    count?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateField(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.required = source["required"];
+	        this.count = source["count"];
+	    }
+	}
+	export class Template {
+	    fields: TemplateField[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Template(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fields = this.convertValues(source["fields"], TemplateField);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Question {
 	    id: number;
+	    uuid: string;
 	    content: string;
+	    packId?: string;
+	    template?: Template;
 	    // Go type: time
 	    createdAt: any;
-	    // Go type: time
-	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Affirmation(source);
+	        return new Question(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.uuid = source["uuid"];
 	        this.content = source["content"];
+	        this.packId = source["packId"];
+	        this.template = this.convertValues(source["template"], Template);
 	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -38,21 +1452,33 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class AffirmationLog {
-	    id: number;
-	    affirmationId: number;
+	export class QuestionPack {
+	    id: string;
+	    name: string;
+	    version: string;
+	    author: string;
+	    language: string;
+	    tags: string[];
+	    description: string;
+	    questionCount: number;
 	    // Go type: time
-	    completedAt: any;
+	    installedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new AffirmationLog(source);
+	        return new QuestionPack(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.affirmationId = source["affirmationId"];
-	        this.completedAt = this.convertValues(source["completedAt"], null);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.author = source["author"];
+	        this.language = source["language"];
+	        this.tags = source["tags"];
+	        this.description = source["description"];
+	        this.questionCount = source["questionCount"];
+	        this.installedAt = this.convertValues(source["installedAt"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -73,26 +1499,28 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class Answer {
+	export class QuestionSkip {
 	    id: number;
+	    uuid: string;
 	    questionId: number;
-	    content: string;
+	    reason: string;
 	    // Go type: time
-	    createdAt: any;
+	    skippedAt: any;
 	    // Go type: time
-	    updatedAt: any;
+	    excludedUntil?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Answer(source);
+	        return new QuestionSkip(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.uuid = source["uuid"];
 	        this.questionId = source["questionId"];
-	        this.content = source["content"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.reason = source["reason"];
+	        this.skippedAt = this.convertValues(source["skippedAt"], null);
+	        this.excludedUntil = this.convertValues(source["excludedUntil"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -113,24 +1541,44 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class AnswerHistory {
+	export class RoutineStep {
+	    key: string;
+	    type: string;
+	    label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RoutineStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.type = source["type"];
+	        this.label = source["label"];
+	    }
+	}
+	export class Routine {
 	    id: number;
-	    questionId: number;
-	    content: string;
+	    uuid: string;
+	    builtin: string;
+	    name: string;
+	    steps: RoutineStep[];
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
 	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new AnswerHistory(source);
+	        return new Routine(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.questionId = source["questionId"];
-	        this.content = source["content"];
+	        this.uuid = source["uuid"];
+	        this.builtin = source["builtin"];
+	        this.name = source["name"];
+	        this.steps = this.convertValues(source["steps"], RoutineStep);
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -153,26 +1601,28 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class CreativityEntry {
+	export class RoutineRun {
 	    id: number;
-	    content: string;
-	    entryDate: string;
+	    uuid: string;
+	    routineId: number;
+	    date: string;
 	    // Go type: time
-	    createdAt: any;
+	    startedAt: any;
 	    // Go type: time
-	    updatedAt: any;
+	    completedAt?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new CreativityEntry(source);
+	        return new RoutineRun(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.content = source["content"];
-	        this.entryDate = source["entryDate"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.uuid = source["uuid"];
+	        this.routineId = source["routineId"];
+	        this.date = source["date"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.completedAt = this.convertValues(source["completedAt"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -193,23 +1643,27 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class GratitudeItem {
-	    id: number;
-	    content: string;
-	    entryDate: string;
+	export class RoutineStepStatus {
+	    step: RoutineStep;
+	    done: boolean;
 	    // Go type: time
-	    createdAt: any;
+	    doneAt?: any;
+	    entry?: EntrySummary;
+	    note: string;
+	    intention?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new GratitudeItem(source);
+	        return new RoutineStepStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.content = source["content"];
-	        this.entryDate = source["entryDate"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.step = this.convertValues(source["step"], RoutineStep);
+	        this.done = source["done"];
+	        this.doneAt = this.convertValues(source["doneAt"], null);
+	        this.entry = this.convertValues(source["entry"], EntrySummary);
+	        this.note = source["note"];
+	        this.intention = source["intention"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -230,18 +1684,24 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class GratitudeEntry {
+	export class RoutineStatus {
+	    routine: Routine;
 	    date: string;
-	    items: GratitudeItem[];
+	    run?: RoutineRun;
+	    steps: RoutineStepStatus[];
+	    done: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new GratitudeEntry(source);
+	        return new RoutineStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.routine = this.convertValues(source["routine"], Routine);
 	        this.date = source["date"];
-	        this.items = this.convertValues(source["items"], GratitudeItem);
+	        this.run = this.convertValues(source["run"], RoutineRun);
+	        this.steps = this.convertValues(source["steps"], RoutineStepStatus);
+	        this.done = source["done"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -263,20 +1723,294 @@ export namespace models {
 		}
 	}
 	
-	export class Question {
+	
+	export class SentimentPoint {
+	    period: string;
+	    average: number;
+	    entries: number;
+	    positive: number;
+	    negative: number;
+	    mood?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SentimentPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.average = source["average"];
+	        this.entries = source["entries"];
+	        this.positive = source["positive"];
+	        this.negative = source["negative"];
+	        this.mood = source["mood"];
+	    }
+	}
+	export class SentimentTrend {
+	    range: DateRange;
+	    granularity: string;
+	    average: number;
+	    points: SentimentPoint[];
+	    moodCorrelation?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SentimentTrend(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.range = this.convertValues(source["range"], DateRange);
+	        this.granularity = source["granularity"];
+	        this.average = source["average"];
+	        this.points = this.convertValues(source["points"], SentimentPoint);
+	        this.moodCorrelation = source["moodCorrelation"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SkippedQuestion {
+	    question: Question;
+	    skips: number;
+	    snoozes: number;
+	    reasons: Record<string, number>;
+	    answers: number;
+	    // Go type: time
+	    lastSkipped: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkippedQuestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.question = this.convertValues(source["question"], Question);
+	        this.skips = source["skips"];
+	        this.snoozes = source["snoozes"];
+	        this.reasons = source["reasons"];
+	        this.answers = source["answers"];
+	        this.lastSkipped = this.convertValues(source["lastSkipped"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Tag {
 	    id: number;
-	    content: string;
+	    name: string;
+	    entries: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.entries = source["entries"];
+	    }
+	}
+	
+	
+	export class WritingSession {
+	    id: number;
+	    activity: string;
+	    // Go type: time
+	    startedAt: any;
+	    // Go type: time
+	    endedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new WritingSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.activity = source["activity"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.endedAt = this.convertValues(source["endedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WritingTotals {
+	    entries: number;
+	    words: number;
+	    characters: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WritingTotals(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = source["entries"];
+	        this.words = source["words"];
+	        this.characters = source["characters"];
+	    }
+	}
+	export class WritingStats {
+	    range: DateRange;
+	    entries: number;
+	    words: number;
+	    characters: number;
+	    activeDays: number;
+	    averageWordsPerDay: number;
+	    averageWordsPerEntry: number;
+	    answers: WritingTotals;
+	    creativity: WritingTotals;
+	    sessions: number;
+	    secondsWriting: number;
+	    averageSecondsPerDay: number;
+	    longest?: LongestEntry;
+	    topWords: textstats.WordCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WritingStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.range = this.convertValues(source["range"], DateRange);
+	        this.entries = source["entries"];
+	        this.words = source["words"];
+	        this.characters = source["characters"];
+	        this.activeDays = source["activeDays"];
+	        this.averageWordsPerDay = source["averageWordsPerDay"];
+	        this.averageWordsPerEntry = source["averageWordsPerEntry"];
+	        this.answers = this.convertValues(source["answers"], WritingTotals);
+	        this.creativity = this.convertValues(source["creativity"], WritingTotals);
+	        this.sessions = source["sessions"];
+	        this.secondsWriting = source["secondsWriting"];
+	        this.averageSecondsPerDay = source["averageSecondsPerDay"];
+	        this.longest = this.convertValues(source["longest"], LongestEntry);
+	        this.topWords = this.convertValues(source["topWords"], textstats.WordCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace packs {
+	
+	export class Pack {
+	    format: number;
+	    id: string;
+	    name: string;
+	    version: string;
+	    author: string;
+	    language: string;
+	    tags: string[];
+	    description: string;
+	    questions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Pack(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.author = source["author"];
+	        this.language = source["language"];
+	        this.tags = source["tags"];
+	        this.description = source["description"];
+	        this.questions = source["questions"];
+	    }
+	}
+
+}
+
+export namespace profiles {
+	
+	export class Profile {
+	    id: string;
+	    name: string;
+	    file: string;
+	    seedSet: string;
+	    seeded: boolean;
 	    // Go type: time
 	    createdAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Question(source);
+	        return new Profile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.content = source["content"];
+	        this.name = source["name"];
+	        this.file = source["file"];
+	        this.seedSet = source["seedSet"];
+	        this.seeded = source["seeded"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	    }
 	
@@ -301,3 +2035,76 @@ export namespace models {
 
 }
 
+export namespace reminders {
+	
+	export class Rule {
+	    id: string;
+	    activity: string;
+	    at: string;
+	    title: string;
+	    message: string;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.activity = source["activity"];
+	        this.at = source["at"];
+	        this.title = source["title"];
+	        this.message = source["message"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+
+}
+
+export namespace replication {
+	
+	export class Conflict {
+	    table: string;
+	    uuid: string;
+	    localModified: number;
+	    remoteModified: number;
+	    winner: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Conflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.uuid = source["uuid"];
+	        this.localModified = source["localModified"];
+	        this.remoteModified = source["remoteModified"];
+	        this.winner = source["winner"];
+	        this.reason = source["reason"];
+	    }
+	}
+
+}
+
+export namespace textstats {
+	
+	export class WordCount {
+	    word: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WordCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.word = source["word"];
+	        this.count = source["count"];
+	    }
+	}
+
+}
+
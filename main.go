@@ -15,7 +15,7 @@ var assets embed.FS
 
 func main() {
 	// Create an instance of the app
-	app := backend.NewApp()
+	app := backend.NewApp(backend.Options{})
 
 	// Create application with options
 	err := wails.Run(&options.App{